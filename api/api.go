@@ -3,6 +3,7 @@ package api
 import (
 	"github.com/kencx/dusk"
 	"github.com/kencx/dusk/file"
+	"github.com/kencx/dusk/jobs"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -10,11 +11,12 @@ import (
 type Handler struct {
 	db       dusk.Store
 	fs       *file.Service
+	runner   *jobs.Runner
 	revision string
 }
 
-func Router(revision string, db dusk.Store, fs *file.Service) chi.Router {
-	s := Handler{db, fs, revision}
+func Router(revision string, db dusk.Store, fs *file.Service, runner *jobs.Runner) chi.Router {
+	s := Handler{db, fs, runner, revision}
 	api := chi.NewRouter()
 
 	api.Route("/books", func(r chi.Router) {
@@ -25,9 +27,123 @@ func Router(revision string, db dusk.Store, fs *file.Service) chi.Router {
 		r.Post("/{id:[0-9]+}/format", s.AddBookFormat)
 		r.Put("/{id:[0-9]+}", s.UpdateBook)
 		r.Delete("/{id:[0-9]+}", s.DeleteBook)
+		r.Get("/{id:[0-9]+}/sessions", s.GetReadingSessionsFromBook)
+		r.Post("/{id:[0-9]+}/sessions", s.AddReadingSession)
+		r.Get("/{id:[0-9]+}/copies", s.GetCopiesFromBook)
+		r.Post("/{id:[0-9]+}/copies", s.AddCopy)
+		r.Get("/{id:[0-9]+}/highlights", s.GetHighlightsFromBook)
+		r.Post("/{id:[0-9]+}/highlights", s.AddHighlight)
 	})
 
-	api.Route("/authors", func(r chi.Router) {})
-	api.Route("/tags", func(r chi.Router) {})
+	api.Route("/wishlist", func(r chi.Router) {
+		r.Get("/", s.GetWishlist)
+		r.Post("/{id:[0-9]+}/own", s.OwnWishlistBook)
+	})
+
+	api.Route("/sessions", func(r chi.Router) {
+		r.Get("/{id:[0-9]+}", s.GetReadingSession)
+		r.Put("/{id:[0-9]+}", s.UpdateReadingSession)
+		r.Delete("/{id:[0-9]+}", s.DeleteReadingSession)
+		r.Post("/{id:[0-9]+}/progress", s.AddReadingProgress)
+	})
+
+	api.Route("/copies", func(r chi.Router) {
+		r.Get("/{id:[0-9]+}", s.GetCopy)
+		r.Put("/{id:[0-9]+}", s.UpdateCopy)
+		r.Delete("/{id:[0-9]+}", s.DeleteCopy)
+		r.Post("/{id:[0-9]+}/loans", s.AddLoan)
+	})
+
+	api.Route("/loans", func(r chi.Router) {
+		r.Get("/", s.GetAllLoans)
+		r.Get("/{id:[0-9]+}", s.GetLoan)
+		r.Put("/{id:[0-9]+}", s.UpdateLoan)
+		r.Delete("/{id:[0-9]+}", s.DeleteLoan)
+		r.Post("/{id:[0-9]+}/return", s.ReturnLoan)
+	})
+
+	api.Route("/highlights", func(r chi.Router) {
+		r.Get("/", s.SearchHighlights)
+		r.Get("/{id:[0-9]+}", s.GetHighlight)
+		r.Put("/{id:[0-9]+}", s.UpdateHighlight)
+		r.Delete("/{id:[0-9]+}", s.DeleteHighlight)
+	})
+
+	api.Route("/authors", func(r chi.Router) {
+		r.Get("/{id:[0-9]+}", s.GetAuthor)
+		r.Get("/{id:[0-9]+}/books", s.GetAllBooksFromAuthor)
+		r.Get("/", s.GetAllAuthors)
+		r.Post("/", s.AddAuthor)
+		r.Put("/{id:[0-9]+}", s.UpdateAuthor)
+		r.Delete("/{id:[0-9]+}", s.DeleteAuthor)
+		r.Post("/{id:[0-9]+}/merge/{other:[0-9]+}", s.MergeAuthors)
+	})
+
+	api.Route("/tags", func(r chi.Router) {
+		r.Get("/{id:[0-9]+}", s.GetTag)
+		r.Get("/{id:[0-9]+}/books", s.GetAllBooksFromTag)
+		r.Get("/", s.GetAllTags)
+		r.Post("/", s.AddTag)
+		r.Put("/{id:[0-9]+}", s.UpdateTag)
+		r.Delete("/{id:[0-9]+}", s.DeleteTag)
+		r.Post("/{id:[0-9]+}/merge", s.MergeTags)
+		r.Delete("/unused", s.DeleteUnusedTags)
+	})
+
+	api.Route("/collections", func(r chi.Router) {
+		r.Get("/{id:[0-9]+}", s.GetCollection)
+		r.Get("/", s.GetAllCollections)
+		r.Post("/", s.AddCollection)
+		r.Put("/{id:[0-9]+}", s.UpdateCollection)
+		r.Delete("/{id:[0-9]+}", s.DeleteCollection)
+		r.Put("/{id:[0-9]+}/books/{book:[0-9]+}", s.AddBookToCollection)
+		r.Delete("/{id:[0-9]+}/books/{book:[0-9]+}", s.RemoveBookFromCollection)
+		r.Put("/{id:[0-9]+}/order", s.ReorderCollection)
+	})
+
+	api.Route("/smart-collections", func(r chi.Router) {
+		r.Get("/{id:[0-9]+}", s.GetSmartCollection)
+		r.Get("/{id:[0-9]+}/books", s.GetAllBooksFromSmartCollection)
+		r.Get("/", s.GetAllSmartCollections)
+		r.Post("/", s.AddSmartCollection)
+		r.Put("/{id:[0-9]+}", s.UpdateSmartCollection)
+		r.Delete("/{id:[0-9]+}", s.DeleteSmartCollection)
+	})
+
+	api.Route("/works", func(r chi.Router) {
+		r.Get("/{id:[0-9]+}", s.GetWork)
+		r.Get("/", s.GetAllWorks)
+		r.Put("/{id:[0-9]+}", s.UpdateWork)
+		r.Delete("/{id:[0-9]+}", s.DeleteWork)
+		r.Put("/{id:[0-9]+}/books/{book:[0-9]+}", s.AddBookToWork)
+		r.Delete("/{id:[0-9]+}/books/{book:[0-9]+}", s.RemoveBookFromWork)
+	})
+
+	api.Route("/series", func(r chi.Router) {
+		r.Get("/{id:[0-9]+}", s.GetSeries)
+		r.Get("/{id:[0-9]+}/books", s.GetAllBooksFromSeries)
+		r.Get("/", s.GetAllSeries)
+		r.Post("/", s.AddSeries)
+		r.Put("/{id:[0-9]+}", s.UpdateSeries)
+		r.Delete("/{id:[0-9]+}", s.DeleteSeries)
+	})
+
+	api.Get("/stats", s.GetStats)
+	api.Get("/export/goodreads", s.ExportGoodreads)
+
+	api.Route("/goals", func(r chi.Router) {
+		r.Get("/{year:[0-9]{4}}", s.GetGoal)
+		r.Get("/", s.GetAllGoals)
+		r.Put("/{year:[0-9]{4}}", s.SetGoal)
+		r.Delete("/{year:[0-9]{4}}", s.DeleteGoal)
+	})
+
+	api.Route("/jobs", func(r chi.Router) {
+		r.Get("/{id:[0-9]+}", s.GetJob)
+		r.Get("/", s.GetAllJobs)
+		r.Post("/{id:[0-9]+}/cancel", s.CancelJob)
+		r.Post("/{id:[0-9]+}/retry", s.RetryJob)
+		r.Delete("/{id:[0-9]+}", s.DeleteJob)
+	})
 	return api
 }
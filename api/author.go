@@ -1,10 +1,12 @@
 package api
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/filters"
 	"github.com/kencx/dusk/http/request"
 	"github.com/kencx/dusk/http/response"
 	"github.com/kencx/dusk/util"
@@ -37,8 +39,13 @@ func (s *Handler) GetAuthor(rw http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Handler) GetAllAuthors(rw http.ResponseWriter, r *http.Request) {
+	f := initSearchFilters(r, filters.AuthorSafeList())
+	if errMap := f.Valid(); len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
 
-	a, err := s.db.GetAllAuthors(nil)
+	a, err := s.db.GetAllAuthors(f)
 	if err == dusk.ErrNoRows {
 		response.NoContent(rw, r)
 		return
@@ -48,7 +55,43 @@ func (s *Handler) GetAllAuthors(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	res, err := util.ToJSON(response.Envelope{"authors": a})
+	res, err := util.ToJSON(response.Envelope{"authors": a.Items, "page": a.Metadata()})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) GetAllBooksFromAuthor(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	f := initBookFilters(r)
+	if errMap := f.Valid(); len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	if _, err := s.db.GetAuthor(id); err != nil {
+		if errors.Is(err, dusk.ErrDoesNotExist) {
+			response.NotFound(rw, r, err)
+			return
+		}
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	b, err := s.db.GetAllBooksFromAuthor(id, f)
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"books": b.Items, "page": b.Metadata()})
 	if err != nil {
 		response.InternalServerError(rw, r, err)
 		return
@@ -74,6 +117,10 @@ func (s *Handler) AddAuthor(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	result, err := s.db.CreateAuthor(&author)
+	if errors.Is(err, dusk.ErrUniqueConstraint) {
+		response.Conflict(rw, r, err)
+		return
+	}
 	if err != nil {
 		response.InternalServerError(rw, r, err)
 		return
@@ -93,13 +140,32 @@ func (s *Handler) UpdateAuthor(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// marshal payload to struct
-	var author dusk.Author
-	err := request.ReadJSON(rw, r, &author)
+	current, err := s.db.GetAuthor(id)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	// marshal payload over the current author, so fields missing from the
+	// payload are kept. Identifiers are replaced instead of merged.
+	author := *current
+	author.Identifiers = nil
+	err = request.ReadJSON(rw, r, &author)
 	if err != nil {
 		response.BadRequest(rw, r, err)
 		return
 	}
+	if author.Identifiers == nil {
+		author.Identifiers = current.Identifiers
+	}
+	// the sort name of a renamed author is derived again
+	if author.Name != current.Name && author.SortName == current.SortName {
+		author.SortName = ""
+	}
 
 	errMap := validator.Validate(author)
 	if len(errMap) > 0 {
@@ -108,8 +174,12 @@ func (s *Handler) UpdateAuthor(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	result, err := s.db.UpdateAuthor(id, &author)
-	if err == dusk.ErrDoesNotExist {
-		response.InternalServerError(rw, r, err)
+	if errors.Is(err, dusk.ErrNoChange) {
+		response.NotFound(rw, r, dusk.ErrDoesNotExist)
+		return
+	}
+	if errors.Is(err, dusk.ErrUniqueConstraint) {
+		response.Conflict(rw, r, err)
 		return
 	}
 	if err != nil {
@@ -117,6 +187,13 @@ func (s *Handler) UpdateAuthor(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the old photo is removed once it is replaced or cleared
+	if current.Photo.Valid && current.Photo != result.Photo {
+		if err := s.fs.DeleteAuthorPhoto(current); err != nil {
+			slog.Warn("[API] Failed to delete author photo", slog.Int64("author_id", id), slog.Any("err", err))
+		}
+	}
+
 	body, err := util.ToJSON(response.Envelope{"authors": result})
 	if err != nil {
 		response.InternalServerError(rw, r, err)
@@ -133,8 +210,12 @@ func (s *Handler) DeleteAuthor(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	err := s.db.DeleteAuthor(id)
-	if err == dusk.ErrDoesNotExist {
-		response.NotFound(rw, r, err)
+	if errors.Is(err, dusk.ErrNoChange) {
+		response.NotFound(rw, r, dusk.ErrDoesNotExist)
+		return
+	}
+	if errors.Is(err, dusk.ErrHasBooks) {
+		response.Conflict(rw, r, dusk.ErrHasBooks)
 		return
 	}
 
@@ -146,3 +227,43 @@ func (s *Handler) DeleteAuthor(rw http.ResponseWriter, r *http.Request) {
 	slog.Debug("Deleted author", slog.Int64("author_id", id))
 	response.OK(rw, r, nil)
 }
+
+// MergeAuthors merges the author other into the author id
+func (s *Handler) MergeAuthors(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+	otherId := request.HandleInt64("other", rw, r)
+	if otherId == -1 {
+		return
+	}
+
+	if id == otherId {
+		response.BadRequest(rw, r, errors.New("cannot merge author with itself"))
+		return
+	}
+
+	result, err := s.db.MergeAuthors(id, otherId)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if errors.Is(err, dusk.ErrUniqueConstraint) {
+		response.Conflict(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"authors": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	slog.Debug("Merged authors", slog.Int64("author_id", id), slog.Int64("other_id", otherId))
+	response.OK(rw, r, body)
+}
@@ -3,10 +3,16 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/file"
+	"github.com/kencx/dusk/filters"
 	"github.com/kencx/dusk/mock"
+	"github.com/kencx/dusk/null"
+	"github.com/kencx/dusk/page"
 	"github.com/kencx/dusk/util"
 
 	"github.com/matryer/is"
@@ -19,7 +25,7 @@ var (
 	testAuthor2 = &dusk.Author{
 		Name: "Author 2",
 	}
-	testAuthors = []*dusk.Author{testAuthor1, testAuthor2}
+	testAuthors = []dusk.Author{*testAuthor1, *testAuthor2}
 )
 
 func TestGetAuthor(t *testing.T) {
@@ -52,8 +58,8 @@ func TestGetAuthor(t *testing.T) {
 func TestGetAllAuthors(t *testing.T) {
 	is := is.New(t)
 	testHandler.db = &mock.Store{
-		GetAllAuthorsFn: func() (dusk.Authors, error) {
-			return testAuthors, nil
+		GetAllAuthorsFn: func(f *filters.Search) (*page.Page[dusk.Author], error) {
+			return page.New(len(testAuthors), 1, len(testAuthors), &f.Base, testAuthors), nil
 		},
 	}
 
@@ -65,11 +71,13 @@ func TestGetAllAuthors(t *testing.T) {
 	w, err := testResponse(t, tc)
 	is.NoErr(err)
 
-	var env map[string][]*dusk.Author
+	var env struct {
+		Items []*dusk.Author `json:"authors"`
+	}
 	err = json.NewDecoder(w.Body).Decode(&env)
 	is.NoErr(err)
 
-	got := env["authors"]
+	got := env.Items
 	for i, v := range got {
 		is.Equal(v.Name, testAuthors[i].Name)
 	}
@@ -77,10 +85,24 @@ func TestGetAllAuthors(t *testing.T) {
 	is.Equal(w.Result().Header.Get("Content-Type"), "application/json")
 }
 
+func TestGetAllAuthorsInvalidSort(t *testing.T) {
+	testHandler.db = &mock.Store{}
+
+	// tags can be sorted by their number of books, authors cannot
+	tc := &testCase{
+		method: http.MethodGet,
+		url:    "/api/authors/?sort=bookCount",
+		fn:     testHandler.GetAllAuthors,
+	}
+	w, err := testResponse(t, tc)
+	is.New(t).NoErr(err)
+	assertValidationError(t, w, "sort", "invalid sort value")
+}
+
 func TestGetAllAuthorsNil(t *testing.T) {
 	is := is.New(t)
 	testHandler.db = &mock.Store{
-		GetAllAuthorsFn: func() (dusk.Authors, error) {
+		GetAllAuthorsFn: func(f *filters.Search) (*page.Page[dusk.Author], error) {
 			return nil, dusk.ErrNoRows
 		},
 	}
@@ -156,6 +178,9 @@ func TestUpdateAuthor(t *testing.T) {
 	is.NoErr(err)
 
 	testHandler.db = &mock.Store{
+		GetAuthorFn: func(id int64) (*dusk.Author, error) {
+			return testAuthor1, nil
+		},
 		UpdateAuthorFn: func(id int64, a *dusk.Author) (*dusk.Author, error) {
 			return testAuthor2, nil
 		},
@@ -181,6 +206,61 @@ func TestUpdateAuthor(t *testing.T) {
 	is.Equal(w.Result().Header.Get("Content-Type"), "application/json")
 }
 
+func TestUpdateAuthorPartial(t *testing.T) {
+	is := is.New(t)
+	dir := t.TempDir()
+	photo := "author/1/photo.jpg"
+	is.NoErr(os.MkdirAll(filepath.Join(dir, "author", "1"), 0755))
+	is.NoErr(os.WriteFile(filepath.Join(dir, photo), []byte("foo"), 0644))
+
+	current := &dusk.Author{
+		Id:          1,
+		Name:        "Ursula Le Guin",
+		SortName:    "Le Guin, Ursula",
+		Aliases:     []string{"U. K. Le Guin"},
+		Bio:         null.StringFrom("bio"),
+		Photo:       null.StringFrom(photo),
+		Identifiers: map[string]string{"openlibrary": "OL1A", "wikidata": "Q1"},
+	}
+
+	var got *dusk.Author
+	testHandler.fs = &file.Service{Directory: dir}
+	testHandler.db = &mock.Store{
+		GetAuthorFn: func(id int64) (*dusk.Author, error) {
+			c := *current
+			return &c, nil
+		},
+		UpdateAuthorFn: func(id int64, a *dusk.Author) (*dusk.Author, error) {
+			got = a
+			return a, nil
+		},
+	}
+	defer func() { testHandler.fs = nil }()
+
+	tc := &testCase{
+		method: http.MethodPut,
+		url:    "/api/authors/1",
+		data:   []byte(`{"name": "Ursula K. Le Guin", "photo": null, "identifiers": {"wikidata": "Q1"}}`),
+		params: map[string]string{"id": "1"},
+		fn:     testHandler.UpdateAuthor,
+	}
+	w, err := testResponse(t, tc)
+	is.NoErr(err)
+	is.Equal(w.Code, http.StatusOK)
+
+	// missing fields are kept
+	is.Equal(got.Name, "Ursula K. Le Guin")
+	is.Equal(got.SortName, "")
+	is.Equal(got.Aliases, current.Aliases)
+	is.Equal(got.Bio, current.Bio)
+	is.Equal(got.Identifiers, map[string]string{"wikidata": "Q1"})
+
+	// the cleared photo is deleted
+	is.True(!got.Photo.Valid)
+	_, err = os.Stat(filepath.Join(dir, photo))
+	is.True(os.IsNotExist(err))
+}
+
 func TestDeleteAuthor(t *testing.T) {
 	is := is.New(t)
 	testHandler.db = &mock.Store{
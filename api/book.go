@@ -1,10 +1,12 @@
 package api
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/dedup"
 	"github.com/kencx/dusk/http/request"
 	"github.com/kencx/dusk/http/response"
 	"github.com/kencx/dusk/util"
@@ -37,7 +39,13 @@ func (s *Handler) GetBook(rw http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Handler) GetAllBooks(rw http.ResponseWriter, r *http.Request) {
-	b, err := s.db.GetAllBooks(nil)
+	f := initBookFilters(r)
+	if errMap := f.Valid(); len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	b, err := s.db.GetAllBooks(f)
 	if err == dusk.ErrNoRows {
 		response.NoContent(rw, r)
 		return
@@ -47,7 +55,7 @@ func (s *Handler) GetAllBooks(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	res, err := util.ToJSON(response.Envelope{"books": b})
+	res, err := util.ToJSON(response.Envelope{"books": b.Items, "page": b.Metadata()})
 	if err != nil {
 		response.InternalServerError(rw, r, err)
 		return
@@ -72,17 +80,47 @@ func (s *Handler) AddBook(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := s.db.CreateBook(&book)
+	// possible duplicates are returned with 409 unless another action is given
+	action, err := dedup.ParseAction(request.QueryString(r.URL.Query(), "duplicate", "ask"))
 	if err != nil {
 		response.BadRequest(rw, r, err)
 		return
 	}
 
+	result, err := dedup.Add(s.db, &book, action)
+
+	var dupErr *dedup.Error
+	if errors.As(err, &dupErr) {
+		body, err := util.ToJSON(response.Envelope{"error": dupErr.Error(), "duplicates": dupErr.Matches})
+		if err != nil {
+			response.InternalServerError(rw, r, err)
+			return
+		}
+		response.Custom(rw, r, http.StatusConflict, nil, body)
+		return
+	}
+
+	if errors.Is(err, dusk.ErrIdentifierExists) {
+		response.Conflict(rw, r, err)
+		return
+	}
+	skipped := errors.Is(err, dusk.ErrSkipped)
+	if err != nil && !skipped {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
 	body, err := util.ToJSON(response.Envelope{"books": result})
 	if err != nil {
 		response.InternalServerError(rw, r, err)
 		return
 	}
+
+	// the existing book is returned as is
+	if skipped {
+		response.OK(rw, r, body)
+		return
+	}
 	response.Created(rw, r, body)
 }
 
@@ -204,6 +242,10 @@ func (s *Handler) UpdateBook(rw http.ResponseWriter, r *http.Request) {
 		response.NotFound(rw, r, err)
 		return
 	}
+	if errors.Is(err, dusk.ErrIdentifierExists) {
+		response.Conflict(rw, r, err)
+		return
+	}
 	if err != nil {
 		response.InternalServerError(rw, r, err)
 		return
@@ -6,7 +6,9 @@ import (
 	"testing"
 
 	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/filters"
 	"github.com/kencx/dusk/mock"
+	"github.com/kencx/dusk/page"
 	"github.com/kencx/dusk/util"
 
 	"github.com/matryer/is"
@@ -28,7 +30,7 @@ var (
 		Author: []string{"Billy Foo", "Carl Baz"},
 		Isbn10: []string{"0143039822"},
 	}
-	testBooks = []*dusk.Book{testBook1, testBook2, testBook3}
+	testBooks = []dusk.Book{*testBook1, *testBook2, *testBook3}
 )
 
 func TestGetBook(t *testing.T) {
@@ -84,8 +86,8 @@ func TestGetAllBooks(t *testing.T) {
 	is := is.New(t)
 	t.Run("success", func(t *testing.T) {
 		testHandler.db = &mock.Store{
-			GetAllBooksFn: func() (dusk.Books, error) {
-				return testBooks, nil
+			GetAllBooksFn: func(f *filters.Book) (*page.Page[dusk.Book], error) {
+				return page.New(len(testBooks), 1, len(testBooks), &f.Base, testBooks), nil
 			},
 		}
 
@@ -98,11 +100,13 @@ func TestGetAllBooks(t *testing.T) {
 		w, err := testResponse(t, tc)
 		is.NoErr(err)
 
-		var env map[string][]*dusk.Book
+		var env struct {
+			Items []*dusk.Book `json:"books"`
+		}
 		err = json.NewDecoder(w.Body).Decode(&env)
 		is.NoErr(err)
 
-		got := env["books"]
+		got := env.Items
 		for i, v := range got {
 			is.Equal(v.Title, testBooks[i].Title)
 			is.Equal(v.Author[0], testBooks[i].Author[0])
@@ -114,7 +118,7 @@ func TestGetAllBooks(t *testing.T) {
 
 	t.Run("no content", func(t *testing.T) {
 		testHandler.db = &mock.Store{
-			GetAllBooksFn: func() (dusk.Books, error) {
+			GetAllBooksFn: func(f *filters.Book) (*page.Page[dusk.Book], error) {
 				return nil, dusk.ErrNoRows
 			},
 		}
@@ -137,6 +141,9 @@ func TestAddBook(t *testing.T) {
 	is.NoErr(err)
 
 	testHandler.db = &mock.Store{
+		GetDuplicateCandidatesFn: func(isbns, authors []string) (dusk.Books, error) {
+			return nil, nil
+		},
 		CreateBookFn: func(b *dusk.Book) (*dusk.Book, error) {
 			return testBook1, nil
 		},
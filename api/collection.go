@@ -0,0 +1,410 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/http/response"
+	"github.com/kencx/dusk/query"
+	"github.com/kencx/dusk/util"
+	"github.com/kencx/dusk/validator"
+)
+
+func (s *Handler) GetCollection(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	c, err := s.db.GetCollection(id)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"collections": c})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) GetAllCollections(rw http.ResponseWriter, r *http.Request) {
+	c, err := s.db.GetAllCollections()
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"collections": c})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) AddCollection(rw http.ResponseWriter, r *http.Request) {
+	var collection dusk.Collection
+	err := request.ReadJSON(rw, r, &collection)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	errMap := validator.Validate(collection)
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	result, err := s.db.CreateCollection(&collection)
+	if errors.Is(err, dusk.ErrUniqueConstraint) {
+		response.Conflict(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"collections": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.Created(rw, r, body)
+}
+
+func (s *Handler) UpdateCollection(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	var collection dusk.Collection
+	err := request.ReadJSON(rw, r, &collection)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	errMap := validator.Validate(collection)
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	result, err := s.db.UpdateCollection(id, &collection)
+	if errors.Is(err, dusk.ErrNoChange) {
+		response.NotFound(rw, r, dusk.ErrDoesNotExist)
+		return
+	}
+	if errors.Is(err, dusk.ErrUniqueConstraint) {
+		response.Conflict(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"collections": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.OK(rw, r, body)
+}
+
+func (s *Handler) DeleteCollection(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	err := s.db.DeleteCollection(id)
+	if errors.Is(err, dusk.ErrNoChange) {
+		response.NotFound(rw, r, dusk.ErrDoesNotExist)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	slog.Debug("Deleted collection", slog.Int64("collection_id", id))
+	response.OK(rw, r, nil)
+}
+
+func (s *Handler) AddBookToCollection(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+	bookId := request.HandleInt64("book", rw, r)
+	if bookId == -1 {
+		return
+	}
+
+	result, err := s.db.AddBookToCollection(id, bookId)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if errors.Is(err, dusk.ErrUniqueConstraint) {
+		response.Conflict(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"collections": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	slog.Debug("Added book to collection", slog.Int64("collection_id", id), slog.Int64("book_id", bookId))
+	response.OK(rw, r, body)
+}
+
+func (s *Handler) RemoveBookFromCollection(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+	bookId := request.HandleInt64("book", rw, r)
+	if bookId == -1 {
+		return
+	}
+
+	err := s.db.RemoveBookFromCollection(id, bookId)
+	if errors.Is(err, dusk.ErrNoChange) {
+		response.NotFound(rw, r, dusk.ErrDoesNotExist)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	slog.Debug("Removed book from collection", slog.Int64("collection_id", id), slog.Int64("book_id", bookId))
+	response.OK(rw, r, nil)
+}
+
+// ReorderCollection moves the given books, in order, to the start of the
+// collection.
+func (s *Handler) ReorderCollection(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	var input struct {
+		Books []int64 `json:"books"`
+	}
+	err := request.ReadJSON(rw, r, &input)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	result, err := s.db.ReorderCollection(id, input.Books)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"collections": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.OK(rw, r, body)
+}
+
+func (s *Handler) GetSmartCollection(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	c, err := s.db.GetSmartCollection(id)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"smart_collections": c})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) GetAllSmartCollections(rw http.ResponseWriter, r *http.Request) {
+	c, err := s.db.GetAllSmartCollections()
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"smart_collections": c})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) GetAllBooksFromSmartCollection(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	f := initBookFilters(r)
+	if errMap := f.Valid(); len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	b, err := s.db.GetAllBooksFromSmartCollection(id, f)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if errors.Is(err, query.ErrInvalidQuery) {
+		response.BadRequest(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"books": b.Items, "page": b.Metadata()})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) AddSmartCollection(rw http.ResponseWriter, r *http.Request) {
+	var collection dusk.SmartCollection
+	err := request.ReadJSON(rw, r, &collection)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	errMap := validator.Validate(collection)
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	result, err := s.db.CreateSmartCollection(&collection)
+	if errors.Is(err, dusk.ErrUniqueConstraint) {
+		response.Conflict(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"smart_collections": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.Created(rw, r, body)
+}
+
+func (s *Handler) UpdateSmartCollection(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	var collection dusk.SmartCollection
+	err := request.ReadJSON(rw, r, &collection)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	errMap := validator.Validate(collection)
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	result, err := s.db.UpdateSmartCollection(id, &collection)
+	if errors.Is(err, dusk.ErrNoChange) {
+		response.NotFound(rw, r, dusk.ErrDoesNotExist)
+		return
+	}
+	if errors.Is(err, dusk.ErrUniqueConstraint) {
+		response.Conflict(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"smart_collections": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.OK(rw, r, body)
+}
+
+func (s *Handler) DeleteSmartCollection(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	err := s.db.DeleteSmartCollection(id)
+	if errors.Is(err, dusk.ErrNoChange) {
+		response.NotFound(rw, r, dusk.ErrDoesNotExist)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	slog.Debug("Deleted smart collection", slog.Int64("smart_collection_id", id))
+	response.OK(rw, r, nil)
+}
@@ -0,0 +1,355 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/http/response"
+	"github.com/kencx/dusk/util"
+	"github.com/kencx/dusk/validator"
+)
+
+func (s *Handler) GetCopiesFromBook(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	if _, err := s.db.GetBook(id); err != nil {
+		if errors.Is(err, dusk.ErrDoesNotExist) {
+			response.NotFound(rw, r, err)
+			return
+		}
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	copies, err := s.db.GetCopies(id)
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"copies": copies})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) GetCopy(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	c, err := s.db.GetCopy(id)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"copies": c})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) AddCopy(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	var c dusk.Copy
+	err := request.ReadJSON(rw, r, &c)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	errMap := validator.Validate(c)
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	result, err := s.db.CreateCopy(id, &c)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if errors.Is(err, dusk.ErrCopyLent) {
+		response.Conflict(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"copies": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.Created(rw, r, body)
+}
+
+func (s *Handler) UpdateCopy(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	var c dusk.Copy
+	err := request.ReadJSON(rw, r, &c)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	// PUT should require all fields
+	errMap := validator.Validate(c)
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	result, err := s.db.UpdateCopy(id, &c)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"copies": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.OK(rw, r, body)
+}
+
+func (s *Handler) DeleteCopy(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	err := s.db.DeleteCopy(id)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	slog.Debug("Deleted copy", slog.Int64("copy_id", id))
+	response.OK(rw, r, nil)
+}
+
+// GetAllLoans returns all copies that are currently lent out, or only the
+// overdue ones with ?overdue=true.
+func (s *Handler) GetAllLoans(rw http.ResponseWriter, r *http.Request) {
+	loans, err := s.db.GetActiveLoans()
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	if r.URL.Query().Get("overdue") == "true" {
+		var overdue []dusk.Loan
+		now := time.Now()
+		for _, l := range loans {
+			if l.Overdue(now) {
+				overdue = append(overdue, l)
+			}
+		}
+		loans = overdue
+	}
+
+	res, err := util.ToJSON(response.Envelope{"loans": loans})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) GetLoan(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	l, err := s.db.GetLoan(id)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"loans": l})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+// AddLoan lends the copy to a borrower
+func (s *Handler) AddLoan(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	var l dusk.Loan
+	err := request.ReadJSON(rw, r, &l)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	errMap := validator.Validate(l)
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	result, err := s.db.CreateLoan(id, &l)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if errors.Is(err, dusk.ErrCopyLent) {
+		response.Conflict(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"loans": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.Created(rw, r, body)
+}
+
+func (s *Handler) UpdateLoan(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	var l dusk.Loan
+	err := request.ReadJSON(rw, r, &l)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	// PUT should require all fields
+	errMap := validator.Validate(l)
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	result, err := s.db.UpdateLoan(id, &l)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if errors.Is(err, dusk.ErrCopyLent) {
+		response.Conflict(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"loans": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.OK(rw, r, body)
+}
+
+// ReturnLoan records the return of a lent copy today
+func (s *Handler) ReturnLoan(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	result, err := s.db.ReturnLoan(id, time.Now())
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if errors.Is(err, dusk.ErrNoChange) {
+		response.Conflict(rw, r, errors.New("copy has already been returned"))
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"loans": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.OK(rw, r, body)
+}
+
+func (s *Handler) DeleteLoan(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	err := s.db.DeleteLoan(id)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	slog.Debug("Deleted loan", slog.Int64("loan_id", id))
+	response.OK(rw, r, nil)
+}
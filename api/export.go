@@ -0,0 +1,35 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/kencx/dusk/http/response"
+	"github.com/kencx/dusk/integration/goodreads"
+)
+
+// ExportGoodreads writes all books that match the filters as a Goodreads CSV
+// export. With copies=true, the details of their copies are included in an
+// extra column.
+func (s *Handler) ExportGoodreads(rw http.ResponseWriter, r *http.Request) {
+	f := initBookFilters(r)
+	if errMap := f.Valid(); len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	// the details of copies are not part of a Goodreads export
+	copies := r.URL.Query().Get("copies") == "true"
+
+	var buf bytes.Buffer
+	if err := goodreads.Export(&buf, s.db, f, copies); err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.Custom(rw, r, http.StatusOK, map[string]string{
+		"Content-Type":        "text/csv; charset=utf-8",
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", goodreads.ExportFilename),
+	}, buf.Bytes())
+}
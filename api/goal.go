@@ -0,0 +1,112 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/http/response"
+	"github.com/kencx/dusk/util"
+	"github.com/kencx/dusk/validator"
+)
+
+func (s *Handler) GetGoal(rw http.ResponseWriter, r *http.Request) {
+	year := request.HandleInt64("year", rw, r)
+	if year == -1 {
+		return
+	}
+
+	g, err := s.db.GetGoal(int(year))
+	if err == dusk.ErrDoesNotExist {
+		response.NotFound(rw, r, err)
+		return
+
+	} else if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"goals": g})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) GetAllGoals(rw http.ResponseWriter, r *http.Request) {
+	g, err := s.db.GetAllGoals()
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"goals": g})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) SetGoal(rw http.ResponseWriter, r *http.Request) {
+	year := request.HandleInt64("year", rw, r)
+	if year == -1 {
+		return
+	}
+
+	// marshal payload to struct
+	var goal dusk.Goal
+	err := request.ReadJSON(rw, r, &goal)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+	goal.Year = int(year)
+
+	// PUT should require all fields
+	errMap := validator.Validate(goal)
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	result, err := s.db.SetGoal(&goal)
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"goals": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, body)
+}
+
+func (s *Handler) DeleteGoal(rw http.ResponseWriter, r *http.Request) {
+	year := request.HandleInt64("year", rw, r)
+	if year == -1 {
+		return
+	}
+
+	err := s.db.DeleteGoal(int(year))
+	if errors.Is(err, dusk.ErrNoChange) {
+		response.NotFound(rw, r, dusk.ErrDoesNotExist)
+		return
+	}
+
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	slog.Debug("Deleted goal", slog.Int64("year", year))
+	response.OK(rw, r, nil)
+}
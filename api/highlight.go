@@ -0,0 +1,195 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/http/response"
+	"github.com/kencx/dusk/util"
+	"github.com/kencx/dusk/validator"
+)
+
+func (s *Handler) GetHighlightsFromBook(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	if _, err := s.db.GetBook(id); err != nil {
+		if errors.Is(err, dusk.ErrDoesNotExist) {
+			response.NotFound(rw, r, err)
+			return
+		}
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	highlights, err := s.db.GetHighlights(id)
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"highlights": highlights})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+// SearchHighlights returns a page of the highlights of all books whose text or
+// note contain ?q=, or all highlights if it is empty.
+func (s *Handler) SearchHighlights(rw http.ResponseWriter, r *http.Request) {
+	f := initHighlightFilters(r)
+	if errMap := f.Valid(); len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	p, err := s.db.SearchHighlights(f)
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"highlights": p.Items, "page": p.Metadata()})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) GetHighlight(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	h, err := s.db.GetHighlight(id)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"highlights": h})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) AddHighlight(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	var h dusk.Highlight
+	err := request.ReadJSON(rw, r, &h)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	errMap := validator.Validate(h)
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	result, err := s.db.CreateHighlight(id, &h)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if errors.Is(err, dusk.ErrUniqueConstraint) {
+		response.Conflict(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"highlights": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.Created(rw, r, body)
+}
+
+func (s *Handler) UpdateHighlight(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	var h dusk.Highlight
+	err := request.ReadJSON(rw, r, &h)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	// PUT should require all fields
+	errMap := validator.Validate(h)
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	result, err := s.db.UpdateHighlight(id, &h)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if errors.Is(err, dusk.ErrUniqueConstraint) {
+		response.Conflict(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"highlights": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.OK(rw, r, body)
+}
+
+func (s *Handler) DeleteHighlight(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	err := s.db.DeleteHighlight(id)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	slog.Debug("Deleted highlight", slog.Int64("highlight_id", id))
+	response.OK(rw, r, nil)
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/http/response"
+	"github.com/kencx/dusk/util"
+)
+
+func (s *Handler) GetJob(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	j, err := s.db.GetJob(id)
+	if err == dusk.ErrDoesNotExist {
+		response.NotFound(rw, r, err)
+		return
+
+	} else if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"jobs": j})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) GetAllJobs(rw http.ResponseWriter, r *http.Request) {
+	j, err := s.db.GetAllJobs()
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"jobs": j})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) CancelJob(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	j, err := s.runner.Cancel(id)
+	if err == dusk.ErrDoesNotExist {
+		response.NotFound(rw, r, err)
+		return
+
+	} else if errors.Is(err, dusk.ErrNoChange) {
+		response.Conflict(rw, r, errors.New("job is already finished"))
+		return
+
+	} else if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"jobs": j})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	slog.Debug("Cancelled job", slog.Int64("id", id))
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) RetryJob(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	j, err := s.runner.Retry(id)
+	if err == dusk.ErrDoesNotExist {
+		response.NotFound(rw, r, err)
+		return
+
+	} else if errors.Is(err, dusk.ErrNoChange) {
+		response.Conflict(rw, r, errors.New("job is not finished or has no failed items"))
+		return
+
+	} else if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"jobs": j})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	slog.Debug("Retrying job", slog.Int64("id", id))
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) DeleteJob(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	j, err := s.db.GetJob(id)
+	if err == dusk.ErrDoesNotExist {
+		response.NotFound(rw, r, err)
+		return
+
+	} else if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	if !j.Finished() {
+		response.Conflict(rw, r, errors.New("job must be cancelled before it is deleted"))
+		return
+	}
+
+	if err := s.db.DeleteJob(id); err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	slog.Debug("Deleted job", slog.Int64("id", id))
+	response.OK(rw, r, nil)
+}
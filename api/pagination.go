@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/kencx/dusk/filters"
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/page"
+)
+
+var (
+	defaultFilters = &filters.Base{
+		AfterId:       0,
+		Limit:         30,
+		Sort:          "name",
+		SortDirection: "ASC",
+		SortSafeList:  filters.DefaultSafeList(),
+	}
+	defaultBookSort      = "title"
+	defaultHighlightSort = "rank"
+)
+
+func initSearchFilters(r *http.Request, safeList []string) *filters.Search {
+	qs := r.URL.Query()
+
+	return &filters.Search{
+		Search: request.QueryString(qs, "q", ""),
+		Base: filters.Base{
+			AfterId:       request.QueryInt(qs, page.After, defaultFilters.AfterId),
+			Limit:         request.QueryInt(qs, page.Limit, defaultFilters.Limit),
+			Sort:          request.QueryString(qs, page.Sort, defaultFilters.Sort),
+			SortDirection: request.QueryString(qs, page.SortDirection, defaultFilters.SortDirection),
+			SortSafeList:  safeList,
+		},
+	}
+}
+
+// initHighlightFilters ranks highlights by best match unless sorted otherwise
+func initHighlightFilters(r *http.Request) *filters.Search {
+	f := initSearchFilters(r, filters.HighlightSafeList())
+	f.Sort = request.QueryString(r.URL.Query(), page.Sort, defaultHighlightSort)
+	return f
+}
+
+func initBookFilters(r *http.Request) *filters.Book {
+	qs := r.URL.Query()
+
+	return &filters.Book{
+		Title:  request.QueryString(qs, "title", ""),
+		Author: request.QueryString(qs, "author", ""),
+		Tag:    request.QueryString(qs, "tag", ""),
+		Series: request.QueryString(qs, "series", ""),
+
+		Identifier: request.QueryString(qs, "identifier", ""),
+		Ownership:  request.QueryString(qs, "ownership", ""),
+		Search: filters.Search{
+			Search: request.QueryString(qs, "q", ""),
+			Base: filters.Base{
+				AfterId:       request.QueryInt(qs, page.After, defaultFilters.AfterId),
+				Limit:         request.QueryInt(qs, page.Limit, defaultFilters.Limit),
+				Sort:          request.QueryString(qs, page.Sort, defaultBookSort),
+				SortDirection: request.QueryString(qs, page.SortDirection, defaultFilters.SortDirection),
+				SortSafeList:  filters.BookSafeList(),
+			},
+		},
+	}
+}
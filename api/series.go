@@ -0,0 +1,201 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/filters"
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/http/response"
+	"github.com/kencx/dusk/util"
+	"github.com/kencx/dusk/validator"
+)
+
+func (s *Handler) GetSeries(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	a, err := s.db.GetSeries(id)
+	if err == dusk.ErrDoesNotExist {
+		response.NotFound(rw, r, err)
+		return
+
+	} else if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"series": a})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) GetAllSeries(rw http.ResponseWriter, r *http.Request) {
+	f := initSearchFilters(r, filters.SeriesSafeList())
+	if errMap := f.Valid(); len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	a, err := s.db.GetAllSeries(f)
+	if err == dusk.ErrNoRows {
+		response.NoContent(rw, r)
+		return
+
+	} else if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"series": a.Items, "page": a.Metadata()})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) GetAllBooksFromSeries(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	f := initBookFilters(r)
+	f.SortSafeList = filters.SeriesBookSafeList()
+	if errMap := f.Valid(); len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	if _, err := s.db.GetSeries(id); err != nil {
+		if errors.Is(err, dusk.ErrDoesNotExist) {
+			response.NotFound(rw, r, err)
+			return
+		}
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	b, err := s.db.GetAllBooksFromSeries(id, f)
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"books": b.Items, "page": b.Metadata()})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) AddSeries(rw http.ResponseWriter, r *http.Request) {
+
+	// marshal payload to struct
+	var series dusk.Series
+	err := request.ReadJSON(rw, r, &series)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	errMap := validator.Validate(series)
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	result, err := s.db.CreateSeries(&series)
+	if errors.Is(err, dusk.ErrUniqueConstraint) {
+		response.Conflict(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"series": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.Created(rw, r, body)
+}
+
+func (s *Handler) UpdateSeries(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	// marshal payload to struct
+	var series dusk.Series
+	err := request.ReadJSON(rw, r, &series)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	// PUT should require all fields
+	errMap := validator.Validate(series)
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	result, err := s.db.UpdateSeries(id, &series)
+	if errors.Is(err, dusk.ErrNoChange) {
+		response.NotFound(rw, r, dusk.ErrDoesNotExist)
+		return
+	}
+	if errors.Is(err, dusk.ErrUniqueConstraint) {
+		response.Conflict(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"series": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, body)
+}
+
+func (s *Handler) DeleteSeries(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	err := s.db.DeleteSeries(id)
+	if errors.Is(err, dusk.ErrNoChange) {
+		response.NotFound(rw, r, dusk.ErrDoesNotExist)
+		return
+	}
+
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	slog.Debug("Deleted series", slog.Int64("series_id", id))
+	response.OK(rw, r, nil)
+}
@@ -0,0 +1,205 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/http/response"
+	"github.com/kencx/dusk/util"
+	"github.com/kencx/dusk/validator"
+)
+
+func (s *Handler) GetReadingSessionsFromBook(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	if _, err := s.db.GetBook(id); err != nil {
+		if errors.Is(err, dusk.ErrDoesNotExist) {
+			response.NotFound(rw, r, err)
+			return
+		}
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	sessions, err := s.db.GetReadingSessions(id)
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"sessions": sessions})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) GetReadingSession(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	session, err := s.db.GetReadingSession(id)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+
+	} else if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"sessions": session})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) AddReadingSession(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	// marshal payload to struct
+	var session dusk.ReadingSession
+	err := request.ReadJSON(rw, r, &session)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	errMap := validator.Validate(session)
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	result, err := s.db.CreateReadingSession(id, &session)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"sessions": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.Created(rw, r, body)
+}
+
+func (s *Handler) UpdateReadingSession(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	// marshal payload to struct
+	var session dusk.ReadingSession
+	err := request.ReadJSON(rw, r, &session)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	// PUT should require all fields
+	errMap := validator.Validate(session)
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	result, err := s.db.UpdateReadingSession(id, &session)
+	if errors.Is(err, dusk.ErrDoesNotExist) || errors.Is(err, dusk.ErrNoChange) {
+		response.NotFound(rw, r, dusk.ErrDoesNotExist)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"sessions": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, body)
+}
+
+func (s *Handler) DeleteReadingSession(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	err := s.db.DeleteReadingSession(id)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	slog.Debug("Deleted reading session", slog.Int64("session_id", id))
+	response.OK(rw, r, nil)
+}
+
+func (s *Handler) AddReadingProgress(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	// marshal payload to struct
+	var progress dusk.ReadingProgress
+	err := request.ReadJSON(rw, r, &progress)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	errMap := validator.Validate(progress)
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	result, err := s.db.AddReadingProgress(id, &progress)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"progress": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.Created(rw, r, body)
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/http/response"
+	"github.com/kencx/dusk/util"
+	"github.com/kencx/dusk/validator"
+)
+
+func (s *Handler) GetStats(rw http.ResponseWriter, r *http.Request) {
+	year := request.QueryInt(r.URL.Query(), "year", 0)
+
+	errMap := validator.New()
+	errMap.Check(year == 0 || (year >= 1000 && year <= 9999), "year", "must be a 4 digit year")
+	if len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	stats, err := s.db.GetStats(year)
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"stats": stats})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
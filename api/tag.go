@@ -1,10 +1,13 @@
 package api
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
+	"slices"
 
 	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/filters"
 	"github.com/kencx/dusk/http/request"
 	"github.com/kencx/dusk/http/response"
 	"github.com/kencx/dusk/util"
@@ -37,8 +40,13 @@ func (s *Handler) GetTag(rw http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Handler) GetAllTags(rw http.ResponseWriter, r *http.Request) {
+	f := initSearchFilters(r, filters.TagSafeList())
+	if errMap := f.Valid(); len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
 
-	a, err := s.db.GetAllTags(nil)
+	a, err := s.db.GetAllTags(f)
 	if err == dusk.ErrNoRows {
 		response.NoContent(rw, r)
 		return
@@ -48,7 +56,43 @@ func (s *Handler) GetAllTags(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	res, err := util.ToJSON(response.Envelope{"tags": a})
+	res, err := util.ToJSON(response.Envelope{"tags": a.Items, "page": a.Metadata()})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+func (s *Handler) GetAllBooksFromTag(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	f := initBookFilters(r)
+	if errMap := f.Valid(); len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	if _, err := s.db.GetTag(id); err != nil {
+		if errors.Is(err, dusk.ErrDoesNotExist) {
+			response.NotFound(rw, r, err)
+			return
+		}
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	b, err := s.db.GetAllBooksFromTag(id, f)
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"books": b.Items, "page": b.Metadata()})
 	if err != nil {
 		response.InternalServerError(rw, r, err)
 		return
@@ -109,8 +153,16 @@ func (s *Handler) UpdateTag(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	result, err := s.db.UpdateTag(id, &tag)
-	if err == dusk.ErrDoesNotExist {
-		response.InternalServerError(rw, r, err)
+	if errors.Is(err, dusk.ErrNoChange) {
+		response.NotFound(rw, r, dusk.ErrDoesNotExist)
+		return
+	}
+	if errors.Is(err, dusk.ErrTagCycle) {
+		response.BadRequest(rw, r, err)
+		return
+	}
+	if errors.Is(err, dusk.ErrUniqueConstraint) {
+		response.Conflict(rw, r, err)
 		return
 	}
 	if err != nil {
@@ -134,8 +186,8 @@ func (s *Handler) DeleteTag(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	err := s.db.DeleteTag(id)
-	if err == dusk.ErrDoesNotExist {
-		response.NotFound(rw, r, err)
+	if errors.Is(err, dusk.ErrNoChange) {
+		response.NotFound(rw, r, dusk.ErrDoesNotExist)
 		return
 	}
 
@@ -147,3 +199,68 @@ func (s *Handler) DeleteTag(rw http.ResponseWriter, r *http.Request) {
 	slog.Debug("Deleted tag", slog.Int64("tag_id", id))
 	response.OK(rw, r, nil)
 }
+
+func (s *Handler) MergeTags(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	var input struct {
+		Tags []int64 `json:"tags"`
+	}
+	err := request.ReadJSON(rw, r, &input)
+	if err != nil {
+		response.BadRequest(rw, r, err)
+		return
+	}
+
+	if len(input.Tags) == 0 {
+		response.BadRequest(rw, r, errors.New("no tags to merge"))
+		return
+	}
+	if slices.Contains(input.Tags, id) {
+		response.BadRequest(rw, r, errors.New("cannot merge tag with itself"))
+		return
+	}
+
+	result, err := s.db.MergeTags(id, input.Tags)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if errors.Is(err, dusk.ErrTagCycle) {
+		response.BadRequest(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"tags": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	slog.Debug("Merged tags", slog.Int64("tag_id", id), slog.Any("other_ids", input.Tags))
+	response.OK(rw, r, body)
+}
+
+func (s *Handler) DeleteUnusedTags(rw http.ResponseWriter, r *http.Request) {
+	count, err := s.db.DeleteUnusedTags()
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	body, err := util.ToJSON(response.Envelope{"deleted": count})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	slog.Debug("Deleted unused tags", slog.Int64("count", count))
+	response.OK(rw, r, body)
+}
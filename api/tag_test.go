@@ -6,7 +6,9 @@ import (
 	"testing"
 
 	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/filters"
 	"github.com/kencx/dusk/mock"
+	"github.com/kencx/dusk/page"
 	"github.com/kencx/dusk/util"
 
 	"github.com/matryer/is"
@@ -19,7 +21,7 @@ var (
 	testTag2 = &dusk.Tag{
 		Name: "Tag 2",
 	}
-	testTags = []*dusk.Tag{testTag1, testTag2}
+	testTags = []dusk.Tag{*testTag1, *testTag2}
 )
 
 func TestGetTag(t *testing.T) {
@@ -52,8 +54,8 @@ func TestGetTag(t *testing.T) {
 func TestGetAllTags(t *testing.T) {
 	is := is.New(t)
 	testHandler.db = &mock.Store{
-		GetAllTagsFn: func() (dusk.Tags, error) {
-			return testTags, nil
+		GetAllTagsFn: func(f *filters.Search) (*page.Page[dusk.Tag], error) {
+			return page.New(len(testTags), 1, len(testTags), &f.Base, testTags), nil
 		},
 	}
 
@@ -65,11 +67,13 @@ func TestGetAllTags(t *testing.T) {
 	w, err := testResponse(t, tc)
 	is.NoErr(err)
 
-	var env map[string][]*dusk.Tag
+	var env struct {
+		Items []*dusk.Tag `json:"tags"`
+	}
 	err = json.NewDecoder(w.Body).Decode(&env)
 	is.NoErr(err)
 
-	got := env["tags"]
+	got := env.Items
 	for i, v := range got {
 		is.Equal(v.Name, testTags[i].Name)
 	}
@@ -80,7 +84,7 @@ func TestGetAllTags(t *testing.T) {
 func TestGetAllTagsNil(t *testing.T) {
 	is := is.New(t)
 	testHandler.db = &mock.Store{
-		GetAllTagsFn: func() (dusk.Tags, error) {
+		GetAllTagsFn: func(f *filters.Search) (*page.Page[dusk.Tag], error) {
 			return nil, dusk.ErrNoRows
 		},
 	}
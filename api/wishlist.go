@@ -0,0 +1,66 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/http/response"
+	"github.com/kencx/dusk/util"
+)
+
+// GetWishlist returns all books on the wishlist with their price, priority
+// and notes.
+func (s *Handler) GetWishlist(rw http.ResponseWriter, r *http.Request) {
+	books, err := s.db.GetWishlist()
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"books": books})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
+
+// OwnWishlistBook moves a book from the wishlist into the library.
+func (s *Handler) OwnWishlistBook(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	b, err := s.db.GetBook(id)
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	if b.Ownership != dusk.Wishlisted {
+		response.Conflict(rw, r, errors.New("book is not on the wishlist"))
+		return
+	}
+
+	b.Ownership = dusk.Owned
+	result, err := s.db.UpdateBook(id, b)
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	res, err := util.ToJSON(response.Envelope{"books": result})
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	response.OK(rw, r, res)
+}
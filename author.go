@@ -5,15 +5,62 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/kencx/dusk/null"
 	"github.com/kencx/dusk/validator"
 	"github.com/kennygrant/sanitize"
 )
 
 type Author struct {
-	Id   int64  `json:"id"`
-	Name string `json:"name"`
+	Id       int64  `json:"id"`
+	Name     string `json:"name"`
+	SortName string `json:"sort_name" db:"sortName"`
+
+	// other spellings of the author's name
+	Aliases []string `json:"aliases,omitempty" db:"-"`
+
+	Bio null.String `json:"bio,omitempty" db:"bio"`
+	// dates are kept as given by the source, which may only be a year
+	BirthDate null.String `json:"birth_date,omitempty" db:"birthDate"`
+	DeathDate null.String `json:"death_date,omitempty" db:"deathDate"`
+	Photo     null.String `json:"photo,omitempty" db:"photo"`
+
+	// ids of the author in external sources, such as openlibrary or wikidata
+	Identifiers map[string]string `json:"identifiers,omitempty" db:"-"`
+	Links       []AuthorLink      `json:"links,omitempty" db:"-"`
+}
+
+type AuthorLink struct {
+	Title string `json:"title"`
+	Url   string `json:"url"`
+}
+
+// identifierUrls are the profile pages of known identifier sources
+var identifierUrls = map[string]string{
+	"openlibrary":  "https://openlibrary.org/authors/%s",
+	"wikidata":     "https://www.wikidata.org/wiki/%s",
+	"viaf":         "https://viaf.org/viaf/%s",
+	"isni":         "https://isni.org/isni/%s",
+	"goodreads":    "https://www.goodreads.com/author/show/%s",
+	"librarything": "https://www.librarything.com/author/%s",
+}
+
+// IdentifierUrl returns the profile page of the author in the given source or
+// an empty string if the source is unknown.
+func (a Author) IdentifierUrl(source string) string {
+	id, ok := a.Identifiers[source]
+	format, known := identifierUrls[source]
+	if !ok || !known {
+		return ""
+	}
+	return fmt.Sprintf(format, id)
 }
 
+// name particles that belong to the last name
+var nameParticles = []string{"da", "de", "del", "della", "der", "di", "du", "la", "le", "van", "von"}
+
+// name suffixes that follow the last name after a comma
+var nameSuffixes = []string{"jr", "jr.", "sr", "sr.", "ii", "iii", "iv", "phd", "ph.d."}
+
 func (a Author) Slugify() string {
 	name := strings.ReplaceAll(a.Name, ".", "")
 	return sanitize.Path(fmt.Sprintf("%s-%d", name, a.Id))
@@ -22,6 +69,9 @@ func (a Author) Slugify() string {
 func (a Author) Valid() validator.ErrMap {
 	err := validator.New()
 	err.Check(a.Name != "", "name", "value is missing")
+	for _, l := range a.Links {
+		err.Check(strings.HasPrefix(l.Url, "http://") || strings.HasPrefix(l.Url, "https://"), "links", "must be http or https urls")
+	}
 	return err
 }
 
@@ -46,3 +96,38 @@ func (a Author) Equal(other Author) bool {
 	slices.Reverse(split)
 	return a.Name == strings.Join(split, " ")
 }
+
+// NormaliseAuthorName returns the name in "First Last" form. Names with a
+// single comma, other than before a suffix such as "Jr.", are assumed to be in
+// "Last, First" form.
+func NormaliseAuthorName(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if strings.Count(name, ",") != 1 {
+		return name
+	}
+
+	last, first, _ := strings.Cut(name, ",")
+	last, first = strings.TrimSpace(last), strings.TrimSpace(first)
+	if last == "" || first == "" || slices.Contains(nameSuffixes, strings.ToLower(first)) {
+		return name
+	}
+	return first + " " + last
+}
+
+// AuthorSortName returns the name in "Last, First" form. Particles such as
+// "van" or "Le" are kept with the last name.
+func AuthorSortName(name string) string {
+	name = NormaliseAuthorName(name)
+
+	// names that still have a comma end with a suffix and are left as is
+	fields := strings.Fields(name)
+	if len(fields) < 2 || strings.Contains(name, ",") {
+		return name
+	}
+
+	i := len(fields) - 1
+	for i > 1 && slices.Contains(nameParticles, strings.ToLower(fields[i-1])) {
+		i--
+	}
+	return strings.Join(fields[i:], " ") + ", " + strings.Join(fields[:i], " ")
+}
@@ -21,6 +21,13 @@ func TestValidateAuthor(t *testing.T) {
 			Name: "",
 		},
 		err: map[string]string{"name": "value is missing"},
+	}, {
+		name: "invalid link",
+		author: &Author{
+			Name:  "John Doe",
+			Links: []AuthorLink{{Title: "Site", Url: "javascript:alert(1)"}},
+		},
+		err: map[string]string{"links": "must be http or https urls"},
 	}}
 
 	for _, tt := range tests {
@@ -125,3 +132,43 @@ func TestEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthorNames(t *testing.T) {
+	tests := []struct {
+		name       string
+		normalised string
+		sortName   string
+	}{
+		{"Ursula K. Le Guin", "Ursula K. Le Guin", "Le Guin, Ursula K."},
+		{"Le Guin, Ursula K.", "Ursula K. Le Guin", "Le Guin, Ursula K."},
+		{" James S. A.  Corey ", "James S. A. Corey", "Corey, James S. A."},
+		{"Ludwig van Beethoven", "Ludwig van Beethoven", "van Beethoven, Ludwig"},
+		{"Martin Luther King, Jr.", "Martin Luther King, Jr.", "Martin Luther King, Jr."},
+		{"Homer", "Homer", "Homer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormaliseAuthorName(tt.name); got != tt.normalised {
+				t.Errorf("got normalised name %q, want %q", got, tt.normalised)
+			}
+			if got := AuthorSortName(tt.name); got != tt.sortName {
+				t.Errorf("got sort name %q, want %q", got, tt.sortName)
+			}
+		})
+	}
+}
+
+func TestIdentifierUrl(t *testing.T) {
+	a := Author{Identifiers: map[string]string{"openlibrary": "OL26320A", "foo": "bar"}}
+
+	if got := a.IdentifierUrl("openlibrary"); got != "https://openlibrary.org/authors/OL26320A" {
+		t.Errorf("got %q, want openlibrary url", got)
+	}
+	if got := a.IdentifierUrl("foo"); got != "" {
+		t.Errorf("got %q for unknown source, want empty url", got)
+	}
+	if got := a.IdentifierUrl("wikidata"); got != "" {
+		t.Errorf("got %q for missing identifier, want empty url", got)
+	}
+}
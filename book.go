@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
@@ -23,6 +24,16 @@ const (
 	Read
 )
 
+// Ownership is how a book is held, independent of its ReadStatus.
+type Ownership int
+
+const (
+	Owned Ownership = iota
+	Wishlisted
+	Borrowed
+	LibraryLoan
+)
+
 type Book struct {
 	Id       int64       `json:"id" db:"id"`
 	Title    string      `json:"title" db:"title"`
@@ -33,19 +44,22 @@ type Book struct {
 	Tag    []string `json:"tag,omitempty"`
 
 	// one to many
-	Isbn10 []string `json:"isbn,omitempty"`
-	Isbn13 []string `json:"isbn13,omitempty"`
-	// Identifiers map[string][]string `json:"identifiers"`
+	Isbn10      []string    `json:"isbn,omitempty"`
+	Isbn13      []string    `json:"isbn13,omitempty"`
+	Identifiers Identifiers `json:"identifiers,omitempty"`
 
 	NumOfPages int        `json:"num_of_pages" db:"numOfPages"`
 	Progress   int        `json:"progress" db:"progress"`
 	Rating     int        `json:"rating" db:"rating"`
 	Status     ReadStatus `json:"status" db:"status"`
+	Ownership  Ownership  `json:"ownership" db:"ownership"`
 
 	Publisher     null.String `json:"publisher" db:"publisher"`
 	DatePublished null.Time   `json:"date_published" db:"datePublished"`
 
-	Series      null.String `json:"series,omitempty" db:"series"`
+	Series         null.String `json:"series,omitempty" db:"series"`
+	SeriesPosition null.Float  `json:"series_position,omitempty" db:"seriesPosition"`
+
 	Description null.String `json:"description,omitempty" db:"description"`
 	Notes       null.String `json:"notes,omitempty" db:"notes"`
 
@@ -57,6 +71,22 @@ type Book struct {
 	DateStarted   null.Time `json:"date_started" db:"dateStarted"`
 	DateCompleted null.Time `json:"date_completed" db:"dateCompleted"`
 	DateAdded     null.Time `json:"date_added" db:"dateAdded"`
+
+	// one to many
+	// reading history in chronological order. Status, DateStarted,
+	// DateCompleted and Progress are derived from the latest session.
+	Sessions []ReadingSession `json:"sessions,omitempty"`
+
+	// one to many
+	// physical copies with their loans
+	Copies []Copy `json:"copies,omitempty"`
+
+	// price, priority and notes of a book on the wishlist
+	Wishlist *WishlistItem `json:"wishlist,omitempty"`
+
+	// many to one
+	// work that the book is an edition of, without its editions
+	Work *Work `json:"work,omitempty"`
 }
 
 type Books []*Book
@@ -169,12 +199,28 @@ func (b Book) Valid() validator.ErrMap {
 		}
 	}
 
+	for scheme, values := range b.Identifiers {
+		if scheme == "" || strings.ContainsAny(scheme, ": ") {
+			errMap.Add("identifiers", fmt.Sprintf("invalid identifier scheme: %q", scheme))
+		}
+		if slices.Contains(values, "") {
+			errMap.Add("identifiers", fmt.Sprintf("missing %s identifier", scheme))
+		}
+	}
+
 	errMap.Check(b.NumOfPages >= 0, "numOfPages", "must be >= 0")
 	errMap.Check(b.Progress >= 0, "progress", "must be >= 0")
 	errMap.Check(b.Progress >= 0, "progress", "must be <= 100")
 	errMap.Check(b.Rating >= 0, "rating", "must be >= 0")
 	errMap.Check(b.Rating <= 10, "rating", "must be <= 10")
+	errMap.Check(b.SeriesPosition.ValueOrZero() >= 0, "seriesPosition", "must be >= 0")
 	errMap.Check(b.Status >= Unread, "status", "invalid status: must be unread, read or reading")
+	errMap.Check(b.Ownership >= Owned && b.Ownership <= LibraryLoan, "ownership", "invalid ownership: must be owned, wishlist, borrowed or library loan")
+	if b.Wishlist != nil {
+		for k, v := range b.Wishlist.Valid() {
+			errMap.Add(k, v)
+		}
+	}
 
 	return errMap
 }
@@ -199,6 +245,7 @@ func (b *Book) Equal(a *Book) bool {
 			a.Publisher.Equal(b.Publisher) &&
 			a.DatePublished.Equal(b.DatePublished) &&
 			a.Series.Equal(b.Series) &&
+			a.SeriesPosition.Equal(b.SeriesPosition) &&
 			a.Description.Equal(b.Description) &&
 			a.Notes.Equal(b.Notes) &&
 			a.Cover.Equal(b.Cover) &&
@@ -208,7 +255,178 @@ func (b *Book) Equal(a *Book) bool {
 			tagEqual &&
 			isbn10Equal &&
 			isbn13Equal &&
+			a.Identifiers.Equal(b.Identifiers) &&
+			a.Work.Equal(b.Work) &&
 			formatEqual)
 	}
 	return a == b
 }
+
+// MergeFrom fills in the missing metadata of b with the metadata of other.
+// Existing values are kept, while tags, ISBNs and identifiers are combined.
+// The reading status and history of b are left unchanged, and b only takes
+// the work of other if it is not an edition of a work yet. It returns true if
+// b was modified.
+func (b *Book) MergeFrom(other *Book) bool {
+	if other == nil {
+		return false
+	}
+	before := *b
+
+	if !b.Subtitle.Valid || b.Subtitle.String == "" {
+		b.Subtitle = other.Subtitle
+	}
+	if b.NumOfPages == 0 {
+		b.NumOfPages = other.NumOfPages
+	}
+	if b.Rating == 0 {
+		b.Rating = other.Rating
+	}
+	if !b.Publisher.Valid || b.Publisher.String == "" {
+		b.Publisher = other.Publisher
+	}
+	if !b.DatePublished.Valid {
+		b.DatePublished = other.DatePublished
+	}
+	if !b.Series.Valid || b.Series.String == "" {
+		b.Series = other.Series
+		b.SeriesPosition = other.SeriesPosition
+	}
+	if !b.Description.Valid || b.Description.String == "" {
+		b.Description = other.Description
+	}
+	if !b.Notes.Valid || b.Notes.String == "" {
+		b.Notes = other.Notes
+	}
+	if !b.Cover.Valid || b.Cover.String == "" {
+		b.Cover = other.Cover
+	}
+	if b.Work == nil {
+		b.Work = other.Work
+	}
+
+	b.Tag = mergeValues(b.Tag, other.Tag)
+	b.Isbn10 = mergeValues(b.Isbn10, other.Isbn10)
+	b.Isbn13 = mergeValues(b.Isbn13, other.Isbn13)
+	b.Identifiers = b.Identifiers.Merge(other.Identifiers)
+
+	return !b.Equal(&before)
+}
+
+// mergeValues appends the values of other that are missing from s
+func mergeValues(s, other []string) []string {
+	result := slices.Clone(s)
+	for _, v := range other {
+		if v != "" && !slices.Contains(result, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// mergeField is a single valued field that can be taken from another book
+// when merging
+type mergeField struct {
+	isSet func(b *Book) bool
+	equal func(a, b *Book) bool
+	take  func(dst, src *Book)
+}
+
+var mergeFields = map[string]mergeField{
+	"title": {
+		func(b *Book) bool { return b.Title != "" },
+		func(a, b *Book) bool { return a.Title == b.Title },
+		func(dst, src *Book) { dst.Title = src.Title },
+	},
+	"subtitle": {
+		func(b *Book) bool { return b.Subtitle.ValueOrZero() != "" },
+		func(a, b *Book) bool { return a.Subtitle.Equal(b.Subtitle) },
+		func(dst, src *Book) { dst.Subtitle = src.Subtitle },
+	},
+	"num_of_pages": {
+		func(b *Book) bool { return b.NumOfPages != 0 },
+		func(a, b *Book) bool { return a.NumOfPages == b.NumOfPages },
+		func(dst, src *Book) { dst.NumOfPages = src.NumOfPages },
+	},
+	"rating": {
+		func(b *Book) bool { return b.Rating != 0 },
+		func(a, b *Book) bool { return a.Rating == b.Rating },
+		func(dst, src *Book) { dst.Rating = src.Rating },
+	},
+	"publisher": {
+		func(b *Book) bool { return b.Publisher.ValueOrZero() != "" },
+		func(a, b *Book) bool { return a.Publisher.Equal(b.Publisher) },
+		func(dst, src *Book) { dst.Publisher = src.Publisher },
+	},
+	"date_published": {
+		func(b *Book) bool { return b.DatePublished.Valid },
+		func(a, b *Book) bool { return a.DatePublished.Equal(b.DatePublished) },
+		func(dst, src *Book) { dst.DatePublished = src.DatePublished },
+	},
+	"series": {
+		func(b *Book) bool { return b.Series.ValueOrZero() != "" },
+		func(a, b *Book) bool { return a.Series.Equal(b.Series) && a.SeriesPosition.Equal(b.SeriesPosition) },
+		func(dst, src *Book) { dst.Series, dst.SeriesPosition = src.Series, src.SeriesPosition },
+	},
+	"description": {
+		func(b *Book) bool { return b.Description.ValueOrZero() != "" },
+		func(a, b *Book) bool { return a.Description.Equal(b.Description) },
+		func(dst, src *Book) { dst.Description = src.Description },
+	},
+	"notes": {
+		func(b *Book) bool { return b.Notes.ValueOrZero() != "" },
+		func(a, b *Book) bool { return a.Notes.Equal(b.Notes) },
+		func(dst, src *Book) { dst.Notes = src.Notes },
+	},
+	"date_added": {
+		func(b *Book) bool { return b.DateAdded.Valid },
+		func(a, b *Book) bool { return a.DateAdded.Equal(b.DateAdded) },
+		func(dst, src *Book) { dst.DateAdded = src.DateAdded },
+	},
+}
+
+// MergeFieldOrder lists the fields that can conflict when merging two books,
+// in display order.
+var MergeFieldOrder = []string{
+	"title", "subtitle", "series", "num_of_pages", "rating", "publisher",
+	"date_published", "date_added", "description", "notes",
+}
+
+// Conflicts returns the fields that are set to different values in b and
+// other.
+func (b *Book) Conflicts(other *Book) []string {
+	var result []string
+	for _, name := range MergeFieldOrder {
+		f := mergeFields[name]
+		if f.isSet(b) && f.isSet(other) && !f.equal(b, other) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// Merge combines other into b. The given conflicting fields are taken from
+// other, while all other fields keep the value of b unless it is missing.
+// Authors, tags, ISBNs and identifiers are combined. Formats and the cover
+// are left as is since their files have to be moved.
+func (b *Book) Merge(other *Book, fields []string) {
+	for _, name := range fields {
+		if f, ok := mergeFields[name]; ok && f.isSet(other) {
+			f.take(b, other)
+		}
+	}
+
+	cover := b.Cover
+	b.MergeFrom(other)
+	b.Cover = cover
+
+	for _, a := range other.Author {
+		if !slices.ContainsFunc(b.Author, func(name string) bool {
+			x := Author{Name: strings.ToLower(name)}
+			y := Author{Name: strings.ToLower(a)}
+			return x.Equal(y) || y.Equal(x)
+		}) {
+			b.Author = append(b.Author, a)
+		}
+	}
+}
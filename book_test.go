@@ -1,7 +1,10 @@
 package dusk
 
 import (
+	"reflect"
 	"testing"
+
+	"github.com/kencx/dusk/null"
 )
 
 var (
@@ -71,6 +74,23 @@ func TestValidateBook(t *testing.T) {
 			Author: []string{"John Doe"},
 		},
 		err: nil,
+	}, {
+		name: "invalid ownership",
+		book: &Book{
+			Title:     "Foo Bar",
+			Author:    []string{"John Doe"},
+			Ownership: LibraryLoan + 1,
+		},
+		err: map[string]string{"ownership": "invalid ownership: must be owned, wishlist, borrowed or library loan"},
+	}, {
+		name: "invalid wishlist",
+		book: &Book{
+			Title:     "Foo Bar",
+			Author:    []string{"John Doe"},
+			Ownership: Wishlisted,
+			Wishlist:  &WishlistItem{Priority: 4},
+		},
+		err: map[string]string{"priority": "invalid priority: must be between 0 and 3"},
 	}}
 
 	for _, tt := range tests {
@@ -104,3 +124,93 @@ func TestValidateBook(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeFrom(t *testing.T) {
+	b := &Book{
+		Title:     "Foo",
+		Author:    []string{"John Doe"},
+		Tag:       []string{"fiction"},
+		Isbn10:    isbnPass,
+		Publisher: null.StringFrom("Penguin"),
+		Status:    Read,
+
+		Identifiers: Identifiers{IdentifierGoodreads: {"1"}},
+	}
+	other := &Book{
+		Title:      "Foo Bar",
+		Author:     []string{"Jane Doe"},
+		Tag:        []string{"fiction", "classics"},
+		Isbn13:     isbn13Pass,
+		NumOfPages: 100,
+		Publisher:  null.StringFrom("Vintage"),
+		Status:     Unread,
+
+		Identifiers: Identifiers{IdentifierGoodreads: {"2"}, IdentifierAsin: {"B1"}},
+		Work:        &Work{Title: "Foo", OpenLibrary: null.StringFrom("OL1W")},
+	}
+
+	if !b.MergeFrom(other) {
+		t.Fatalf("got no change, want merged book")
+	}
+
+	if b.Title != "Foo" || b.Publisher.String != "Penguin" || b.Status != Read {
+		t.Errorf("existing values were overwritten: %+v", b)
+	}
+	if !reflect.DeepEqual(b.Author, []string{"John Doe"}) {
+		t.Errorf("got author %v, want %v", b.Author, []string{"John Doe"})
+	}
+	if b.NumOfPages != 100 {
+		t.Errorf("got num of pages %d, want 100", b.NumOfPages)
+	}
+	if !reflect.DeepEqual(b.Tag, []string{"fiction", "classics"}) {
+		t.Errorf("got tags %v, want %v", b.Tag, []string{"fiction", "classics"})
+	}
+	if !reflect.DeepEqual(b.Isbn13, isbn13Pass) {
+		t.Errorf("got isbn13 %v, want %v", b.Isbn13, isbn13Pass)
+	}
+	want := Identifiers{IdentifierGoodreads: {"1", "2"}, IdentifierAsin: {"B1"}}
+	if !reflect.DeepEqual(b.Identifiers, want) {
+		t.Errorf("got identifiers %v, want %v", b.Identifiers, want)
+	}
+	if !b.Work.Equal(other.Work) {
+		t.Errorf("got work %v, want %v", b.Work, other.Work)
+	}
+
+	if b.MergeFrom(other) {
+		t.Errorf("got change on second merge, want none")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	b := &Book{
+		Title:  "Foo",
+		Author: []string{"John Doe"},
+		Rating: 6,
+		Notes:  null.StringFrom("mine"),
+		Cover:  null.StringFrom("foo-1/cover.jpg"),
+	}
+	other := &Book{
+		Title:      "Foo",
+		Author:     []string{"Doe, John", "Jane Doe"},
+		Rating:     8,
+		Notes:      null.StringFrom("theirs"),
+		NumOfPages: 100,
+		Cover:      null.StringFrom("foo-2/cover.jpg"),
+	}
+
+	conflicts := b.Conflicts(other)
+	if !reflect.DeepEqual(conflicts, []string{"rating", "notes"}) {
+		t.Fatalf("got conflicts %v, want %v", conflicts, []string{"rating", "notes"})
+	}
+
+	b.Merge(other, []string{"notes"})
+	if b.Rating != 6 || b.Notes.String != "theirs" || b.NumOfPages != 100 {
+		t.Errorf("got rating %d, notes %q, pages %d", b.Rating, b.Notes.String, b.NumOfPages)
+	}
+	if !reflect.DeepEqual(b.Author, []string{"John Doe", "Jane Doe"}) {
+		t.Errorf("got author %v, want %v", b.Author, []string{"John Doe", "Jane Doe"})
+	}
+	if b.Cover.String != "foo-1/cover.jpg" {
+		t.Errorf("got cover %q, want foo-1/cover.jpg", b.Cover.String)
+	}
+}
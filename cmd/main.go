@@ -17,14 +17,21 @@ import (
 	"github.com/kencx/dusk/file"
 	dhttp "github.com/kencx/dusk/http"
 	"github.com/kencx/dusk/integration"
+	"github.com/kencx/dusk/integration/calibre"
+	"github.com/kencx/dusk/integration/goodreads"
 	"github.com/kencx/dusk/integration/googlebooks"
+	"github.com/kencx/dusk/integration/highlights"
 	"github.com/kencx/dusk/integration/openlibrary"
+	"github.com/kencx/dusk/jobs"
 	"github.com/kencx/dusk/storage"
 )
 
 var version string
 
-const dbName = "library.db"
+const (
+	dbName     = "library.db"
+	jobWorkers = 2
+)
 
 type config struct {
 	port     int
@@ -92,7 +99,18 @@ func main() {
 	}
 
 	store := storage.New(db)
-	err = store.MigrateUp("schema.sql")
+
+	if flag.Arg(0) == "migrate" {
+		if err := migrate(store, flag.Args()[1:]); err != nil {
+			store.Close()
+			log.Fatal(err)
+		}
+		store.Close()
+		return
+	}
+
+	// refuses to run against a database migrated by a newer binary
+	err = store.MigrateUp()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -101,7 +119,16 @@ func main() {
 	// 	slog.Error("Migration step failed", slog.Any("err", err))
 	// }
 
-	srv := dhttp.New(version, store, fw, fetchers)
+	// init background jobs
+	runner := jobs.New(store, jobWorkers)
+	runner.Register(goodreads.JobKind, goodreads.ImportHandler(store))
+	runner.Register(calibre.JobKind, calibre.ImportHandler(store, fw))
+	runner.Register(highlights.JobKind, highlights.ImportHandler(store))
+	if err := runner.Start(); err != nil {
+		log.Fatal(err)
+	}
+
+	srv := dhttp.New(version, store, fw, fetchers, runner)
 	go func() error {
 		slog.Info(fmt.Sprintf("Starting server on port %d", config.port))
 		err := srv.Run(fmt.Sprintf(":%d", config.port), config.tlsCert, config.tlsKey)
@@ -116,6 +143,9 @@ func main() {
 	s := <-sig
 	slog.Info(fmt.Sprintf("Received signal %s, shutting down...", s.String()))
 
+	runner.Close()
+	slog.Info("Background jobs stopped")
+
 	if err := store.Close(); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,64 @@
+package dusk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kencx/dusk/null"
+	"github.com/kencx/dusk/query"
+	"github.com/kencx/dusk/validator"
+	"github.com/kennygrant/sanitize"
+)
+
+// Collection is a user-curated shelf of books in a manual order, e.g. "book
+// club 2026". Unlike tags, collections do not describe the books in them.
+type Collection struct {
+	Id          int64       `json:"id"`
+	Name        string      `json:"name" db:"name"`
+	Description null.String `json:"description" db:"description"`
+	BookCount   int         `json:"book_count" db:"bookCount"`
+
+	// books in collection order
+	Books []Book `json:"books,omitempty"`
+}
+
+func (c Collection) Slugify() string {
+	return sanitize.Path(fmt.Sprintf("%s-%d", c.Name, c.Id))
+}
+
+func (c Collection) Valid() validator.ErrMap {
+	err := validator.New()
+	err.Check(c.Name != "", "name", "value is missing")
+	return err
+}
+
+// SmartCollection is a saved library query whose books are found again each
+// time it is viewed, e.g. "status:unread -has:cover".
+type SmartCollection struct {
+	Id    int64  `json:"id"`
+	Name  string `json:"name" db:"name"`
+	Query string `json:"query" db:"query"`
+}
+
+func (c SmartCollection) Slugify() string {
+	return sanitize.Path(fmt.Sprintf("%s-%d", c.Name, c.Id))
+}
+
+func (c SmartCollection) Valid() validator.ErrMap {
+	err := validator.New()
+	err.Check(c.Name != "", "name", "value is missing")
+	err.Check(strings.TrimSpace(c.Query) != "", "query", "value is missing")
+	if _, qerr := query.Parse(c.Query); qerr != nil {
+		err.Add("query", qerr.Error())
+	}
+	return err
+}
+
+// Search returns the query of the collection narrowed down by a search
+// within it.
+func (c SmartCollection) Search(q string) string {
+	if strings.TrimSpace(q) == "" {
+		return c.Query
+	}
+	return fmt.Sprintf("(%s) (%s)", c.Query, q)
+}
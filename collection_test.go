@@ -0,0 +1,55 @@
+package dusk
+
+import (
+	"testing"
+)
+
+func TestValidateSmartCollection(t *testing.T) {
+	tests := []struct {
+		name string
+		c    SmartCollection
+		keys []string
+	}{{
+		name: "success",
+		c:    SmartCollection{Name: "To buy", Query: "status:unread -has:format"},
+	}, {
+		name: "no name",
+		c:    SmartCollection{Query: "status:unread"},
+		keys: []string{"name"},
+	}, {
+		name: "no query",
+		c:    SmartCollection{Name: "To buy", Query: " "},
+		keys: []string{"query"},
+	}, {
+		name: "invalid query",
+		c:    SmartCollection{Name: "To buy", Query: "rating>=high"},
+		keys: []string{"query"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errMap := tt.c.Valid()
+			if len(errMap) != len(tt.keys) {
+				t.Fatalf("got errs %v, want errs for %v", errMap, tt.keys)
+			}
+			for _, k := range tt.keys {
+				if _, ok := errMap[k]; !ok {
+					t.Errorf("err field missing %q", k)
+				}
+			}
+		})
+	}
+}
+
+func TestSmartCollectionSearch(t *testing.T) {
+	c := SmartCollection{Query: "tag:scifi OR tag:fantasy"}
+
+	if got := c.Search(" "); got != c.Query {
+		t.Errorf("got %q, want %q", got, c.Query)
+	}
+
+	want := "(tag:scifi OR tag:fantasy) (dune OR rating>8)"
+	if got := c.Search("dune OR rating>8"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,92 @@
+package dusk
+
+import (
+	"slices"
+	"time"
+
+	"github.com/kencx/dusk/null"
+	"github.com/kencx/dusk/validator"
+)
+
+// CopyConditions are the conditions of a physical copy, from best to worst.
+var CopyConditions = []string{"new", "fine", "good", "fair", "poor"}
+
+// Copy is a physical copy of a book, e.g. a paperback on a shelf. A book that
+// is only owned as files has no copies.
+type Copy struct {
+	Id           int64       `json:"id" db:"id"`
+	BookId       int64       `json:"book_id" db:"bookId"`
+	Condition    null.String `json:"condition" db:"condition"`
+	Location     null.String `json:"location" db:"location"`
+	DateAcquired null.Time   `json:"date_acquired" db:"dateAcquired"`
+	Price        null.Float  `json:"price" db:"price"`
+	Notes        null.String `json:"notes,omitempty" db:"notes"`
+
+	// loan ledger in chronological order
+	Loans []Loan `json:"loans,omitempty"`
+}
+
+// Loan is a copy lent to a borrower. A copy can only be lent to one borrower
+// at a time.
+type Loan struct {
+	Id           int64       `json:"id" db:"id"`
+	CopyId       int64       `json:"copy_id" db:"copyId"`
+	Borrower     string      `json:"borrower" db:"borrower"`
+	DateLent     null.Time   `json:"date_lent" db:"dateLent"`
+	DateDue      null.Time   `json:"date_due" db:"dateDue"`
+	DateReturned null.Time   `json:"date_returned" db:"dateReturned"`
+	Notes        null.String `json:"notes,omitempty" db:"notes"`
+
+	// book of the lent copy, only set when listing loans across books
+	Book *Book `json:"book,omitempty"`
+}
+
+func (c Copy) Valid() validator.ErrMap {
+	errMap := validator.New()
+
+	if c.Condition.Valid {
+		errMap.Check(slices.Contains(CopyConditions, c.Condition.String), "condition", "invalid condition: must be new, fine, good, fair or poor")
+	}
+	errMap.Check(c.Price.ValueOrZero() >= 0, "price", "must be >= 0")
+	for _, l := range c.Loans {
+		for k, v := range l.Valid() {
+			errMap.Add(k, v)
+		}
+	}
+	return errMap
+}
+
+// Loan returns the loan of the copy that has not been returned, if any.
+func (c Copy) Loan() *Loan {
+	for i := range c.Loans {
+		if c.Loans[i].Active() {
+			return &c.Loans[i]
+		}
+	}
+	return nil
+}
+
+func (l Loan) Valid() validator.ErrMap {
+	errMap := validator.New()
+
+	errMap.Check(l.Borrower != "", "borrower", "value is missing")
+	errMap.Check(l.DateLent.Valid, "dateLent", "value is missing")
+	if l.DateLent.Valid && l.DateDue.Valid {
+		errMap.Check(!l.DateDue.Time.Before(l.DateLent.Time), "dateDue", "must be after date lent")
+	}
+	if l.DateLent.Valid && l.DateReturned.Valid {
+		errMap.Check(!l.DateReturned.Time.Before(l.DateLent.Time), "dateReturned", "must be after date lent")
+	}
+	return errMap
+}
+
+// Active reports whether the copy has not been returned yet.
+func (l Loan) Active() bool {
+	return !l.DateReturned.Valid
+}
+
+// Overdue reports whether the copy has not been returned by the end of its
+// due date.
+func (l Loan) Overdue(now time.Time) bool {
+	return l.Active() && l.DateDue.Valid && now.After(l.DateDue.Time.AddDate(0, 0, 1))
+}
@@ -0,0 +1,155 @@
+package dusk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kencx/dusk/null"
+)
+
+func TestValidateCopy(t *testing.T) {
+	lent := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		c    Copy
+		keys []string
+	}{{
+		name: "success",
+		c:    Copy{Condition: null.StringFrom("good"), Price: null.FloatFrom(12.5)},
+	}, {
+		name: "empty",
+		c:    Copy{},
+	}, {
+		name: "invalid condition",
+		c:    Copy{Condition: null.StringFrom("mint")},
+		keys: []string{"condition"},
+	}, {
+		name: "negative price",
+		c:    Copy{Price: null.FloatFrom(-1)},
+		keys: []string{"price"},
+	}, {
+		name: "invalid loan",
+		c:    Copy{Loans: []Loan{{DateLent: null.TimeFrom(lent)}}},
+		keys: []string{"borrower"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errMap := tt.c.Valid()
+			if len(errMap) != len(tt.keys) {
+				t.Fatalf("got errs %v, want errs for %v", errMap, tt.keys)
+			}
+			for _, k := range tt.keys {
+				if _, ok := errMap[k]; !ok {
+					t.Errorf("err field missing %q", k)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateLoan(t *testing.T) {
+	lent := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		l    Loan
+		keys []string
+	}{{
+		name: "success",
+		l: Loan{
+			Borrower: "Foo",
+			DateLent: null.TimeFrom(lent),
+			DateDue:  null.TimeFrom(lent),
+		},
+	}, {
+		name: "no borrower or date lent",
+		l:    Loan{},
+		keys: []string{"borrower", "dateLent"},
+	}, {
+		name: "due before lent",
+		l: Loan{
+			Borrower: "Foo",
+			DateLent: null.TimeFrom(lent),
+			DateDue:  null.TimeFrom(lent.AddDate(0, 0, -1)),
+		},
+		keys: []string{"dateDue"},
+	}, {
+		name: "returned before lent",
+		l: Loan{
+			Borrower:     "Foo",
+			DateLent:     null.TimeFrom(lent),
+			DateReturned: null.TimeFrom(lent.AddDate(0, 0, -1)),
+		},
+		keys: []string{"dateReturned"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errMap := tt.l.Valid()
+			if len(errMap) != len(tt.keys) {
+				t.Fatalf("got errs %v, want errs for %v", errMap, tt.keys)
+			}
+			for _, k := range tt.keys {
+				if _, ok := errMap[k]; !ok {
+					t.Errorf("err field missing %q", k)
+				}
+			}
+		})
+	}
+}
+
+func TestLoanOverdue(t *testing.T) {
+	due := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		l    Loan
+		now  time.Time
+		want bool
+	}{{
+		name: "no due date",
+		l:    Loan{},
+		now:  due.AddDate(1, 0, 0),
+		want: false,
+	}, {
+		name: "on due date",
+		l:    Loan{DateDue: null.TimeFrom(due)},
+		now:  due.Add(20 * time.Hour),
+		want: false,
+	}, {
+		name: "after due date",
+		l:    Loan{DateDue: null.TimeFrom(due)},
+		now:  due.AddDate(0, 0, 2),
+		want: true,
+	}, {
+		name: "returned",
+		l:    Loan{DateDue: null.TimeFrom(due), DateReturned: null.TimeFrom(due.AddDate(0, 0, 5))},
+		now:  due.AddDate(0, 0, 10),
+		want: false,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.l.Overdue(tt.now); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyLoan(t *testing.T) {
+	c := Copy{Loans: []Loan{
+		{Id: 1, DateReturned: null.TimeFrom(time.Now())},
+		{Id: 2},
+	}}
+	if l := c.Loan(); l == nil || l.Id != 2 {
+		t.Errorf("got %v, want loan 2", l)
+	}
+
+	c.Loans[1].DateReturned = null.TimeFrom(time.Now())
+	if l := c.Loan(); l != nil {
+		t.Errorf("got %v, want no loan", l)
+	}
+}
@@ -0,0 +1,308 @@
+package dedup
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/util"
+)
+
+// minSimilarity is the minimum similarity of two titles by the same author to
+// be considered duplicates
+const minSimilarity = 0.85
+
+type Reason int
+
+const (
+	SameIsbn Reason = iota
+	SameTitleAuthor
+	SimilarTitle
+)
+
+var reasonMap = map[Reason]string{
+	SameIsbn:        "same isbn",
+	SameTitleAuthor: "same title and author",
+	SimilarTitle:    "similar title",
+}
+
+func (r Reason) String() string {
+	return reasonMap[r]
+}
+
+func (r Reason) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// Match is an existing book that is a possible duplicate.
+type Match struct {
+	Book       *dusk.Book `json:"book"`
+	Reason     Reason     `json:"reason"`
+	Similarity float64    `json:"similarity"`
+}
+
+// Action decides what happens to a book that has duplicates.
+type Action int
+
+const (
+	// Ask returns the duplicates in an Error so the user can decide
+	Ask Action = iota
+	// Skip keeps the existing book as is
+	Skip
+	// Merge fills in the missing metadata of the existing book
+	Merge
+	// Create adds the book regardless of its duplicates
+	Create
+)
+
+func ParseAction(s string) (Action, error) {
+	switch strings.ToLower(s) {
+	case "", "ask":
+		return Ask, nil
+	case "skip":
+		return Skip, nil
+	case "merge":
+		return Merge, nil
+	case "create":
+		return Create, nil
+	default:
+		return Ask, fmt.Errorf("invalid duplicate action %q: must be ask, skip, merge or create", s)
+	}
+}
+
+// Error is returned by Add when a book has duplicates and the action is Ask.
+type Error struct {
+	Matches []Match
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("book has %d possible duplicates", len(e.Matches))
+}
+
+// Find returns the existing books that are possible duplicates of b, best
+// match first. Books match on any ISBN-10 or ISBN-13, including converted
+// ISBNs, or on an author and a similar title.
+func Find(db dusk.Store, b *dusk.Book) ([]Match, error) {
+	isbns := isbnSet(b)
+	candidates, err := db.GetDuplicateCandidates(isbns, authorNames(b.Author))
+	if err != nil {
+		return nil, err
+	}
+
+	title := normaliseTitle(b.Title)
+	var matches []Match
+	for _, c := range candidates {
+		if c.Id == b.Id {
+			continue
+		}
+
+		if sharesIsbn(isbns, c) {
+			matches = append(matches, Match{c, SameIsbn, 1})
+			continue
+		}
+		if !sharesAuthor(b.Author, c.Author) {
+			continue
+		}
+
+		other := normaliseTitle(c.Title)
+		if title == other {
+			matches = append(matches, Match{c, SameTitleAuthor, 1})
+			continue
+		}
+		if sim := similarity(title, other); sim >= minSimilarity {
+			matches = append(matches, Match{c, SimilarTitle, sim})
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b Match) int {
+		if a.Reason != b.Reason {
+			return int(a.Reason - b.Reason)
+		}
+		switch {
+		case a.Similarity > b.Similarity:
+			return -1
+		case a.Similarity < b.Similarity:
+			return 1
+		}
+		return 0
+	})
+	return matches, nil
+}
+
+// Add creates b if it has no duplicates. Otherwise, the best match is handled
+// according to the given action:
+//
+//   - Ask returns an *Error with all matches
+//   - Skip returns the existing book with dusk.ErrSkipped
+//   - Merge returns the existing book after merging b into it, or
+//     dusk.ErrSkipped if there was nothing to merge
+//   - Create adds b anyway
+func Add(db dusk.Store, b *dusk.Book, action Action) (*dusk.Book, error) {
+	if action == Create {
+		return db.CreateBook(b)
+	}
+
+	matches, err := Find(db, b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicates: %w", err)
+	}
+	if len(matches) == 0 {
+		return db.CreateBook(b)
+	}
+
+	if action == Ask {
+		return nil, &Error{matches}
+	}
+
+	// candidates do not hold all the details of a book, which would be lost
+	// when merging
+	existing, err := db.GetBook(matches[0].Book.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve book %d: %w", matches[0].Book.Id, err)
+	}
+
+	switch action {
+	case Skip:
+		return existing, dusk.ErrSkipped
+	case Merge:
+		if !existing.MergeFrom(b) {
+			return existing, dusk.ErrSkipped
+		}
+
+		result, err := db.UpdateBook(existing.Id, existing)
+		if errors.Is(err, dusk.ErrNoChange) {
+			return existing, dusk.ErrSkipped
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge into book %d: %w", existing.Id, err)
+		}
+		return result, nil
+	default:
+		return nil, &Error{matches}
+	}
+}
+
+// isbnSet returns the ISBNs of b together with their converted ISBN-10 or
+// ISBN-13
+func isbnSet(b *dusk.Book) []string {
+	var result []string
+	add := func(isbn string) {
+		if isbn != "" && !slices.Contains(result, isbn) {
+			result = append(result, isbn)
+		}
+	}
+
+	for _, i := range b.Isbn10 {
+		add(i)
+		add(util.Isbn10To13(i))
+	}
+	for _, i := range b.Isbn13 {
+		add(i)
+		add(util.Isbn13To10(i))
+	}
+	return result
+}
+
+func sharesIsbn(isbns []string, b *dusk.Book) bool {
+	for _, i := range append(slices.Clone(b.Isbn10), b.Isbn13...) {
+		if slices.Contains(isbns, i) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorNames returns the names in both "First Last" and "Last, First" forms
+// so that candidates can be queried by name
+func authorNames(authors []string) []string {
+	var result []string
+	for _, a := range authors {
+		a = strings.TrimSpace(a)
+		result = append(result, a)
+
+		if last, first, ok := strings.Cut(a, ","); ok {
+			result = append(result, strings.TrimSpace(first)+" "+strings.TrimSpace(last))
+			continue
+		}
+
+		if sortName := dusk.AuthorSortName(a); sortName != a {
+			result = append(result, sortName)
+		}
+	}
+	return result
+}
+
+func sharesAuthor(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			ax := dusk.Author{Name: strings.ToLower(strings.TrimSpace(x))}
+			ay := dusk.Author{Name: strings.ToLower(strings.TrimSpace(y))}
+			if ax.Equal(ay) || ay.Equal(ax) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normaliseTitle lower cases the title and removes any punctuation,
+// parenthesised text such as series information and leading articles.
+func normaliseTitle(title string) string {
+	var sb strings.Builder
+	var depth int
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r == '(' || r == '[':
+			depth++
+		case r == ')' || r == ']':
+			depth = max(depth-1, 0)
+		case depth > 0:
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(r)
+		case r == '\'' || r == '’':
+		default:
+			sb.WriteRune(' ')
+		}
+	}
+
+	fields := strings.Fields(sb.String())
+	if len(fields) > 1 && slices.Contains([]string{"the", "a", "an"}, fields[0]) {
+		fields = fields[1:]
+	}
+	return strings.Join(fields, " ")
+}
+
+// similarity returns the normalised Levenshtein similarity of a and b, from 0
+// to 1
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
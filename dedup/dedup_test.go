@@ -0,0 +1,171 @@
+package dedup
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
+)
+
+// memStore returns all of its books as duplicate candidates. Only the methods
+// used by Add are implemented.
+type memStore struct {
+	dusk.Store
+	books dusk.Books
+}
+
+func (m *memStore) GetDuplicateCandidates(isbns, authors []string) (dusk.Books, error) {
+	var result dusk.Books
+	for _, b := range m.books {
+		c := *b
+		result = append(result, &c)
+	}
+	return result, nil
+}
+
+func (m *memStore) GetBook(id int64) (*dusk.Book, error) {
+	c := *m.books[id-1]
+	return &c, nil
+}
+
+func (m *memStore) CreateBook(b *dusk.Book) (*dusk.Book, error) {
+	b.Id = int64(len(m.books) + 1)
+	m.books = append(m.books, b)
+	return b, nil
+}
+
+func (m *memStore) UpdateBook(id int64, b *dusk.Book) (*dusk.Book, error) {
+	m.books[id-1] = b
+	return b, nil
+}
+
+func newMemStore() *memStore {
+	return &memStore{books: dusk.Books{
+		{Id: 1, Title: "The Left Hand of Darkness", Author: []string{"Ursula K. Le Guin"}, Isbn13: []string{"9780441478125"}},
+		{Id: 2, Title: "Leviathan Wakes", Author: []string{"James S. A. Corey"}},
+		{Id: 3, Title: "Caliban's War", Author: []string{"James S. A. Corey"}},
+	}}
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name   string
+		book   *dusk.Book
+		id     int64
+		reason Reason
+	}{{
+		name:   "isbn13",
+		book:   &dusk.Book{Title: "Foo", Author: []string{"Bar"}, Isbn13: []string{"9780441478125"}},
+		id:     1,
+		reason: SameIsbn,
+	}, {
+		name:   "converted isbn10",
+		book:   &dusk.Book{Title: "Foo", Author: []string{"Bar"}, Isbn10: []string{"0441478123"}},
+		id:     1,
+		reason: SameIsbn,
+	}, {
+		name:   "title and last first author",
+		book:   &dusk.Book{Title: "leviathan wakes (The Expanse, #1)", Author: []string{"Corey, James S. A."}},
+		id:     2,
+		reason: SameTitleAuthor,
+	}, {
+		name:   "similar title",
+		book:   &dusk.Book{Title: "Calibans Wars", Author: []string{"James S. A. Corey"}},
+		id:     3,
+		reason: SimilarTitle,
+	}}
+
+	db := newMemStore()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := Find(db, tt.book)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(matches) != 1 {
+				t.Fatalf("got %d matches, want 1", len(matches))
+			}
+			if matches[0].Book.Id != tt.id || matches[0].Reason != tt.reason {
+				t.Errorf("got book %d (%s), want book %d (%s)", matches[0].Book.Id, matches[0].Reason, tt.id, tt.reason)
+			}
+		})
+	}
+
+	// similar title by a different author
+	matches, err := Find(db, &dusk.Book{Title: "Leviathan Wakes", Author: []string{"John Doe"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("got %d matches, want 0", len(matches))
+	}
+}
+
+func TestAdd(t *testing.T) {
+	dup := func() *dusk.Book {
+		return &dusk.Book{Title: "Leviathan Wakes", Author: []string{"James S. A. Corey"}, NumOfPages: 592}
+	}
+
+	db := newMemStore()
+	_, err := Add(db, dup(), Ask)
+	var dupErr *Error
+	if !errors.As(err, &dupErr) || len(dupErr.Matches) != 1 {
+		t.Fatalf("got err %v, want duplicate error", err)
+	}
+
+	b, err := Add(db, dup(), Skip)
+	if !errors.Is(err, dusk.ErrSkipped) || b.Id != 2 || b.NumOfPages != 0 {
+		t.Errorf("got book %d, err %v, want skipped book 2", b.Id, err)
+	}
+
+	b, err = Add(db, dup(), Merge)
+	if err != nil || b.Id != 2 || b.NumOfPages != 592 {
+		t.Errorf("got book %d, err %v, want merged book 2", b.Id, err)
+	}
+	if _, err = Add(db, dup(), Merge); !errors.Is(err, dusk.ErrSkipped) {
+		t.Errorf("got err %v, want %v", err, dusk.ErrSkipped)
+	}
+
+	b, err = Add(db, dup(), Create)
+	if err != nil || b.Id != 4 {
+		t.Errorf("got book %d, err %v, want new book 4", b.Id, err)
+	}
+
+	b, err = Add(db, &dusk.Book{Title: "Foo", Author: []string{"Bar"}, Series: null.StringFrom("Baz")}, Ask)
+	if err != nil || b.Id != 5 {
+		t.Errorf("got book %d, err %v, want new book 5", b.Id, err)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"foo", "foo", 1},
+		{"", "", 1},
+		{"abcd", "abce", 0.75},
+		{"abc", "xyz", 0},
+	}
+
+	for _, tt := range tests {
+		if got := similarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestAuthorNames(t *testing.T) {
+	got := authorNames([]string{"James S. A. Corey", "Le Guin, Ursula K.", "Homer", "Ludwig van Beethoven"})
+	want := []string{
+		"James S. A. Corey", "Corey, James S. A.",
+		"Le Guin, Ursula K.", "Ursula K. Le Guin",
+		"Homer",
+		"Ludwig van Beethoven", "van Beethoven, Ludwig",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
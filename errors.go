@@ -9,5 +9,9 @@ var (
 	ErrNoRows           = errors.New("no items found")
 	ErrUniqueConstraint = errors.New("the item already exists")
 	ErrIsbnExists       = errors.New("isbn already exists")
+	ErrIdentifierExists = errors.New("identifier already exists")
 	ErrNoChange         = errors.New("no change executed")
+	ErrHasBooks         = errors.New("the item is still linked to existing books")
+	ErrTagCycle         = errors.New("tag cannot be nested under itself")
+	ErrCopyLent         = errors.New("copy is already lent out")
 )
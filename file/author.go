@@ -0,0 +1,83 @@
+package file
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
+)
+
+const authorDirectory = "authors"
+
+// Upload author photo from URL for existing author. Photos are stored in a
+// shared authors directory alongside the book directories, named after the
+// author's slug. Any existing photo is replaced once the new one is written,
+// so a failed download keeps the current photo.
+func (s *Service) UploadAuthorPhotoFromUrl(url string, author *dusk.Author) error {
+	resp, err := s.fetch(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	ext := path.Ext(path.Base(resp.Request.URL.Path))
+	if ext == "" {
+		ext = jpegExt
+	}
+
+	dir := filepath.Join(s.Directory, authorDirectory)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create author directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".photo-*")
+	if err != nil {
+		return fmt.Errorf("file: failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, resp.Body)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("file: failed to copy file to dest: %w", err)
+	}
+
+	// replaces any photo with the same name, including one left behind by a
+	// previous author with the same slug
+	fullPath := filepath.Join(dir, fmt.Sprintf("%s%s", author.Slugify(), ext))
+	if err := os.Rename(tmp.Name(), fullPath); err != nil {
+		return fmt.Errorf("file: failed to replace photo: %w", err)
+	}
+	slog.Info("[file] New file uploaded", slog.String("path", fullPath))
+
+	photo := getRelativePath(fullPath)
+	if author.Photo.Valid && author.Photo.String != photo {
+		if err := s.DeleteAuthorPhoto(author); err != nil {
+			return err
+		}
+	}
+
+	author.Photo = null.StringFrom(photo)
+	return nil
+}
+
+// DeleteAuthorPhoto removes the photo of the author, if any
+func (s *Service) DeleteAuthorPhoto(author *dusk.Author) error {
+	if !author.Photo.Valid {
+		return nil
+	}
+
+	err := os.Remove(filepath.Join(s.Directory, author.Photo.String))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("file: failed to delete author photo: %w", err)
+	}
+	author.Photo = null.String{}
+	return nil
+}
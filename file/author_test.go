@@ -0,0 +1,123 @@
+package file
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
+	"github.com/matryer/is"
+)
+
+func newPhotoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/photo.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("new"))
+	})
+	mux.HandleFunc("/slow.png", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("slow"))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// writeAuthorPhoto creates an existing photo for the author
+func writeAuthorPhoto(t *testing.T, dir, name string) string {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Join(dir, authorDirectory), 0755); err != nil {
+		t.Fatal(err)
+	}
+	photo := filepath.Join(authorDirectory, name)
+	if err := os.WriteFile(filepath.Join(dir, photo), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return photo
+}
+
+func TestUploadAuthorPhotoFromUrl(t *testing.T) {
+	srv := newPhotoServer(t)
+	author := dusk.Author{Id: 1, Name: "Ursula K. Le Guin"}
+	want := filepath.Join(authorDirectory, author.Slugify()+".png")
+
+	tests := []struct {
+		name string
+		old  string
+	}{
+		{"no photo", ""},
+		{"different photo", "old.jpeg"},
+		{"same photo", author.Slugify() + ".png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+			dir := t.TempDir()
+			s, err := NewService(dir)
+			is.NoErr(err)
+
+			a := author
+			if tt.old != "" {
+				a.Photo = null.StringFrom(writeAuthorPhoto(t, dir, tt.old))
+			}
+
+			is.NoErr(s.UploadAuthorPhotoFromUrl(srv.URL+"/photo.png", &a))
+			is.Equal(a.Photo, null.StringFrom(want))
+
+			got, err := os.ReadFile(filepath.Join(dir, want))
+			is.NoErr(err)
+			is.Equal(string(got), "new")
+
+			// only the new photo is left
+			entries, err := os.ReadDir(filepath.Join(dir, authorDirectory))
+			is.NoErr(err)
+			is.Equal(len(entries), 1)
+		})
+	}
+}
+
+func TestUploadAuthorPhotoFromUrlFailed(t *testing.T) {
+	srv := newPhotoServer(t)
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"not found", srv.URL + "/missing.png"},
+		{"timeout", srv.URL + "/slow.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+			dir := t.TempDir()
+			s, err := NewService(dir)
+			is.NoErr(err)
+			s.Client = &http.Client{Timeout: 10 * time.Millisecond}
+
+			photo := writeAuthorPhoto(t, dir, "old.jpeg")
+			a := &dusk.Author{Id: 1, Name: "Ursula K. Le Guin", Photo: null.StringFrom(photo)}
+
+			err = s.UploadAuthorPhotoFromUrl(tt.url, a)
+			is.True(err != nil)
+
+			// the current photo is kept
+			is.Equal(a.Photo, null.StringFrom(photo))
+			got, err := os.ReadFile(filepath.Join(dir, photo))
+			is.NoErr(err)
+			is.Equal(string(got), "old")
+
+			entries, err := os.ReadDir(filepath.Join(dir, authorDirectory))
+			is.NoErr(err)
+			is.Equal(len(entries), 1)
+		})
+	}
+}
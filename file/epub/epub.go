@@ -68,16 +68,37 @@ type contentPackage struct {
 }
 
 type metadata struct {
-	Title       string   `xml:"title"`
-	Creator     []string `xml:"creator"`
-	Identifiers []string `xml:"identifier"`
-	Language    string   `xml:"language"`
-	Description string   `xml:"description,omitempty"`
-	Date        string   `xml:"date,omitempty"`
-	Publisher   string   `xml:"publisher,omitempty"`
+	Title       string       `xml:"title"`
+	Creator     []string     `xml:"creator"`
+	Identifiers []identifier `xml:"identifier"`
+	Language    string       `xml:"language"`
+	Description string       `xml:"description,omitempty"`
+	Date        string       `xml:"date,omitempty"`
+	Publisher   string       `xml:"publisher,omitempty"`
 }
 
-type identifiers struct{}
+// identifier is a dc:identifier with an optional opf:scheme, e.g.
+// <dc:identifier opf:scheme="GOODREADS">12345</dc:identifier>
+type identifier struct {
+	Scheme string `xml:"scheme,attr,omitempty"`
+	Value  string `xml:",chardata"`
+}
+
+// parse returns the scheme and value of the identifier. Without an opf:scheme,
+// the scheme is taken from a known prefix of the value, e.g. goodreads:12345
+// or urn:asin:B00ABC.
+func (id identifier) parse() (string, string, bool) {
+	if id.Scheme != "" {
+		return id.Scheme, strings.TrimSpace(id.Value), true
+	}
+
+	value := strings.TrimPrefix(strings.TrimSpace(id.Value), "urn:")
+	scheme, value, err := dusk.ParseIdentifier(value)
+	if err != nil || !dusk.KnownIdentifierScheme(scheme) {
+		return "", "", false
+	}
+	return scheme, value, true
+}
 
 type manifest struct {
 	Item []struct {
@@ -140,13 +161,17 @@ func (e *Epub) ToBook() *dusk.Book {
 	var (
 		isbn10        []string
 		isbn13        []string
+		identifiers   dusk.Identifiers
 		datePublished time.Time
 		err           error
 	)
 
 	for _, id := range e.Identifiers {
-		i, err := util.IsbnExtract(id)
+		i, err := util.IsbnExtract(id.Value)
 		if err != nil {
+			if scheme, value, ok := id.parse(); ok {
+				identifiers.Add(scheme, value)
+			}
 			continue
 		}
 
@@ -162,7 +187,7 @@ func (e *Epub) ToBook() *dusk.Book {
 		datePublished = time.Time{}
 	}
 
-	return dusk.NewBook(
+	b := dusk.NewBook(
 		e.Title, "",
 		e.Creator, nil, nil,
 		isbn10, isbn13,
@@ -170,6 +195,8 @@ func (e *Epub) ToBook() *dusk.Book {
 		e.Publisher, "", e.Description, "", "",
 		datePublished, time.Time{}, time.Time{}, time.Time{},
 	)
+	b.Identifiers = identifiers
+	return b
 }
 
 func (e *Epub) getRootFile() error {
@@ -28,7 +28,7 @@ func TestNew(t *testing.T) {
 			Title:       "EPUB 3.0 Specification",
 			Creator:     []string{"EPUB 3 Working Group"},
 			Language:    "en",
-			Identifiers: []string{"code.google.com.epub-samples.epub30-spec"},
+			Identifiers: []identifier{{Value: "code.google.com.epub-samples.epub30-spec"}},
 		},
 
 		RootFile:  "EPUB/package.opf",
@@ -101,7 +101,7 @@ func TestGetMetadata(t *testing.T) {
 	want := metadata{
 		Title:       "EPUB 3.0 Specification",
 		Creator:     []string{"EPUB 3 Working Group"},
-		Identifiers: []string{"code.google.com.epub-samples.epub30-spec"},
+		Identifiers: []identifier{{Value: "code.google.com.epub-samples.epub30-spec"}},
 		Language:    "en",
 	}
 
@@ -10,6 +10,7 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/kencx/dusk"
 	"github.com/kencx/dusk/file/epub"
@@ -25,11 +26,17 @@ const (
 	jpegExt       = ".jpeg"
 	pngExt        = ".png"
 	djvuExt       = ".djvu"
+
+	fetchTimeout = 30 * time.Second
 )
 
 type Service struct {
 	Directory string
 	Archive   string
+
+	// Client fetches covers and photos from URLs. A client with a default
+	// timeout is used when nil.
+	Client *http.Client
 }
 
 func NewService(path string) (*Service, error) {
@@ -38,7 +45,11 @@ func NewService(path string) (*Service, error) {
 		return nil, err
 	}
 
-	return &Service{path, "archive"}, nil
+	return &Service{
+		Directory: path,
+		Archive:   "archive",
+		Client:    &http.Client{Timeout: fetchTimeout},
+	}, nil
 }
 
 // Book format and cover files should not be uploaded to the filesystem directly if they
@@ -90,9 +101,9 @@ func (s *Service) UploadCoverFromPayload(payload *Payload, book *dusk.Book) erro
 
 // Upload book cover from URL for existing book
 func (s *Service) UploadCoverFromUrl(url string, book *dusk.Book) error {
-	resp, err := http.Get(url)
+	resp, err := s.fetch(url)
 	if err != nil {
-		return fmt.Errorf("file: failed to fetch file from url: %w", err)
+		return err
 	}
 	defer resp.Body.Close()
 
@@ -104,6 +115,28 @@ func (s *Service) UploadCoverFromUrl(url string, book *dusk.Book) error {
 	return s.uploadCover(resp.Body, ext, book)
 }
 
+// Copy format file from a local path for existing book
+func (s *Service) UploadBookFormatFromPath(path string, book *dusk.Book) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("file: failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	return s.uploadFormat(f, strings.ToLower(filepath.Ext(path)), book)
+}
+
+// Copy book cover from a local path for existing book
+func (s *Service) UploadCoverFromPath(path string, book *dusk.Book) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("file: failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	return s.uploadCover(f, strings.ToLower(filepath.Ext(path)), book)
+}
+
 func (s *Service) parseEpub(payload *Payload) (*epub.Epub, error) {
 	ep, err := epub.NewFromReader(payload.File, payload.Size)
 	if err != nil && !errors.Is(err, epub.ErrNoCovers) {
@@ -131,14 +164,18 @@ func (s *Service) uploadEpub(payload *Payload, book *dusk.Book) error {
 
 // Upload format file for book
 func (s *Service) uploadFormatFile(payload *Payload, book *dusk.Book) error {
+	return s.uploadFormat(payload.File, payload.Extension, book)
+}
+
+func (s *Service) uploadFormat(f io.Reader, extension string, book *dusk.Book) error {
 	bookDir, err := s.getBookDirectory(book)
 	if err != nil {
 		return err
 	}
 
-	filename := fmt.Sprintf("%s%s", book.SafeTitle(), payload.Extension)
+	filename := fmt.Sprintf("%s%s", book.SafeTitle(), extension)
 	fullPath := filepath.Join(bookDir, filename)
-	if err := s.upload(payload.File, fullPath); err != nil {
+	if err := s.upload(f, fullPath); err != nil {
 		return err
 	}
 
@@ -197,6 +234,24 @@ func (s *Service) upload(file io.Reader, path string) error {
 	return nil
 }
 
+// fetch file from url, failing on any non-OK status
+func (s *Service) fetch(url string) (*http.Response, error) {
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: fetchTimeout}
+	}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("file: failed to fetch file from url: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("file: failed to fetch file from url: status %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
 // create or get book directory
 func (s *Service) getBookDirectory(book *dusk.Book) (string, error) {
 	bookDir := filepath.Join(s.Directory, strings.ToLower(book.SafeTitle()))
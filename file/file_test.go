@@ -25,7 +25,7 @@ func TestUploadFile(t *testing.T) {
 	is.NoErr(err)
 	defer f.Close()
 
-	err = testFileService.UploadFile(f, path.Join(tempDir, "foo.epub"))
+	err = testFileService.upload(f, path.Join(tempDir, "foo.epub"))
 	is.NoErr(err)
 
 	// check uploaded file
@@ -52,11 +52,11 @@ func TestUploadFileAlreadyExists(t *testing.T) {
 	is.NoErr(err)
 
 	// upload file again
-	err = testFileService.UploadFile(f, path.Join(tempDir, "foo.epub"))
+	err = testFileService.upload(f, path.Join(tempDir, "foo.epub"))
 	is.True(errors.Is(err, os.ErrExist))
 }
 
-func TestCreateBookDirectory(t *testing.T) {
+func TestGetBookDirectory(t *testing.T) {
 	is := is.New(t)
 	tempDir := t.TempDir()
 	testFileService, err := NewService(tempDir)
@@ -67,12 +67,12 @@ func TestCreateBookDirectory(t *testing.T) {
 	}
 
 	want := path.Join(tempDir, "foobar-test-0")
-	got, err := testFileService.createBookDirectory(book)
+	got, err := testFileService.getBookDirectory(book)
 	is.NoErr(err)
 	is.Equal(got, want)
 }
 
-func TestCreateBookDirectoryAlreadyExists(t *testing.T) {
+func TestGetBookDirectoryAlreadyExists(t *testing.T) {
 	is := is.New(t)
 
 	// create directory
@@ -89,7 +89,7 @@ func TestCreateBookDirectoryAlreadyExists(t *testing.T) {
 
 	// recreate directory
 	want := path.Join(tempDir, "foobar-test-0")
-	got, err := testFileService.createBookDirectory(book)
+	got, err := testFileService.getBookDirectory(book)
 	is.NoErr(err)
 	is.Equal(got, want)
 }
@@ -0,0 +1,130 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
+)
+
+const backupExt = ".bak"
+
+// Move records the files moved between book directories so that they can be
+// restored if the database cannot be updated.
+//
+// The full flow of merging two books is:
+//  1. Move the files of the merged book with MoveBookFiles
+//  2. Merge the books in the database
+//  3. If step 2 fails, Rollback the moved files. Otherwise, Commit the move
+//     to delete the merged book's directory
+type Move struct {
+	// renamed files as [src, dst] in order
+	renamed [][2]string
+	// directory of the merged book
+	dir string
+}
+
+// MoveBookFiles moves the format and cover files of from into the directory
+// of to, and adds them to to's formats and cover. Formats that to already has
+// are left behind. The cover of from replaces the cover of to only if
+// takeCover is true or to has no cover.
+func (s *Service) MoveBookFiles(from, to *dusk.Book, takeCover bool) (*Move, error) {
+	m := &Move{dir: filepath.Join(s.Directory, strings.ToLower(from.SafeTitle()))}
+
+	bookDir, err := s.getBookDirectory(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var exts []string
+	for _, f := range to.Formats {
+		exts = append(exts, strings.ToLower(filepath.Ext(f)))
+	}
+
+	formats := slices.Clone(to.Formats)
+	for _, f := range from.Formats {
+		ext := strings.ToLower(filepath.Ext(f))
+		if slices.Contains(exts, ext) {
+			continue
+		}
+
+		dst := filepath.Join(bookDir, fmt.Sprintf("%s%s", to.SafeTitle(), ext))
+		if err := m.rename(filepath.Join(s.Directory, f), dst); err != nil {
+			return nil, errors.Join(err, m.Rollback())
+		}
+		formats = append(formats, getRelativePath(dst))
+		exts = append(exts, ext)
+	}
+
+	cover := to.Cover
+	if from.Cover.Valid && (takeCover || !to.Cover.Valid) {
+		dst := filepath.Join(bookDir, fmt.Sprintf("%s%s", coverFilename, filepath.Ext(from.Cover.String)))
+
+		// keep the replaced cover until the move is committed
+		if to.Cover.Valid {
+			current := filepath.Join(s.Directory, to.Cover.String)
+			if err := m.rename(current, current+backupExt); err != nil {
+				return nil, errors.Join(err, m.Rollback())
+			}
+		}
+
+		if err := m.rename(filepath.Join(s.Directory, from.Cover.String), dst); err != nil {
+			return nil, errors.Join(err, m.Rollback())
+		}
+		cover = null.StringFrom(getRelativePath(dst))
+	}
+
+	to.Formats = formats
+	to.Cover = cover
+	return m, nil
+}
+
+// Rollback moves all files back to their original paths.
+func (m *Move) Rollback() error {
+	var errs []error
+	for i := len(m.renamed) - 1; i >= 0; i-- {
+		src, dst := m.renamed[i][0], m.renamed[i][1]
+		if err := os.Rename(dst, src); err != nil {
+			errs = append(errs, fmt.Errorf("file: failed to restore %s: %w", src, err))
+		}
+	}
+	m.renamed = nil
+	return errors.Join(errs...)
+}
+
+// Commit deletes any replaced files and the directory of the merged book,
+// including the formats that were left behind.
+func (m *Move) Commit() error {
+	var errs []error
+	for _, r := range m.renamed {
+		if strings.HasSuffix(r[1], backupExt) {
+			if err := os.Remove(r[1]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if err := os.RemoveAll(m.dir); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// rename moves src to dst without overwriting existing files
+func (m *Move) rename(src, dst string) error {
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("file: failed to move file: %s already exists", dst)
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return fmt.Errorf("file: failed to move file: %w", err)
+	}
+
+	slog.Info("[file] Moved file", slog.String("src", src), slog.String("dst", dst))
+	m.renamed = append(m.renamed, [2]string{src, dst})
+	return nil
+}
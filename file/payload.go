@@ -5,6 +5,7 @@ import (
 	"mime"
 	"mime/multipart"
 	"path/filepath"
+	"strings"
 )
 
 var (
@@ -69,3 +70,19 @@ func extension(filename, mimetype string) (string, error) {
 
 	return defaultMime, nil
 }
+
+// MimeType returns the mimetype of a book or cover file by its extension.
+func MimeType(filename string) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	for m, e := range mimeExtMap {
+		if e == ext {
+			return m
+		}
+	}
+
+	// .jpg and other aliases
+	if m := mime.TypeByExtension(ext); m != "" {
+		return m
+	}
+	return defaultMime
+}
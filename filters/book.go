@@ -1,10 +1,24 @@
 package filters
 
+import (
+	"strings"
+
+	"github.com/kencx/dusk/query"
+	"github.com/kencx/dusk/validator"
+)
+
 type Book struct {
 	Title  string
 	Author string
 	Tag    string
 	Series string
+
+	// Identifier is of the format scheme:value, eg. goodreads:12345
+	Identifier string
+
+	// Ownership is one of query.OwnershipValues, eg. wishlist. A leading "-"
+	// excludes books with the ownership instead.
+	Ownership string
 	Search
 }
 
@@ -13,5 +27,70 @@ func (bf Book) Empty() bool {
 		bf.Title == "" &&
 		bf.Author == "" &&
 		bf.Tag == "" &&
-		bf.Series == ""
+		bf.Series == "" &&
+		bf.Identifier == "" &&
+		bf.Ownership == ""
+}
+
+func (bf Book) Valid() validator.ErrMap {
+	errMap := bf.Base.Valid()
+
+	if bf.Identifier != "" {
+		if _, err := query.NewTerm(query.Identifier, query.Eq, bf.Identifier); err != nil {
+			errMap.Add("identifier", err.Error())
+		}
+	}
+
+	if bf.Ownership != "" {
+		if _, err := query.NewTerm(query.Ownership, query.Eq, strings.TrimPrefix(bf.Ownership, "-")); err != nil {
+			errMap.Add("ownership", err.Error())
+		}
+	}
+
+	if _, err := bf.Query(); err != nil {
+		errMap.Add("q", err.Error())
+	}
+	return errMap
+}
+
+// Query parses the search query and combines it with the ?title, ?author,
+// ?tag, ?series, ?identifier and ?ownership params. It returns a nil node if there is
+// nothing to filter by.
+func (bf Book) Query() (query.Node, error) {
+	var nodes []query.Node
+
+	node, err := query.Parse(bf.Search.Search)
+	if err != nil {
+		return nil, err
+	}
+	if node != nil {
+		nodes = append(nodes, node)
+	}
+
+	for _, t := range []query.Term{
+		{Field: query.Title, Op: query.Eq, Value: bf.Title},
+		{Field: query.Author, Op: query.Eq, Value: bf.Author},
+		{Field: query.Tag, Op: query.Eq, Value: strings.ToLower(bf.Tag)},
+		{Field: query.Series, Op: query.Eq, Value: bf.Series},
+		{Field: query.Identifier, Op: query.Eq, Value: bf.Identifier},
+	} {
+		if t.Value != "" {
+			nodes = append(nodes, t)
+		}
+	}
+
+	if ownership, ok := strings.CutPrefix(bf.Ownership, "-"); ok {
+		nodes = append(nodes, query.Not{Node: query.Term{Field: query.Ownership, Op: query.Eq, Value: strings.ToLower(ownership)}})
+	} else if ownership != "" {
+		nodes = append(nodes, query.Term{Field: query.Ownership, Op: query.Eq, Value: strings.ToLower(ownership)})
+	}
+
+	switch len(nodes) {
+	case 0:
+		return nil, nil
+	case 1:
+		return nodes[0], nil
+	default:
+		return query.And{Nodes: nodes}, nil
+	}
 }
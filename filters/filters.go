@@ -26,6 +26,32 @@ func DefaultSafeList() []string {
 	}
 }
 
+func BookSafeList() []string {
+	return []string{"title", "rating", "numOfPages", "dateAdded", "dateCompleted"}
+}
+
+// SeriesBookSafeList also sorts books by their position in the series
+func SeriesBookSafeList() []string {
+	return append(BookSafeList(), "position")
+}
+
+func AuthorSafeList() []string {
+	return []string{"name", "-name", "sortName"}
+}
+
+func TagSafeList() []string {
+	return []string{"name", "-name", "bookCount"}
+}
+
+func SeriesSafeList() []string {
+	return []string{"name", "-name"}
+}
+
+// HighlightSafeList sorts highlights by their rank in the search results
+func HighlightSafeList() []string {
+	return []string{"rank", "title", "page"}
+}
+
 func (b Base) Valid() validator.ErrMap {
 	errMap := validator.New()
 
@@ -34,6 +60,7 @@ func (b Base) Valid() validator.ErrMap {
 	errMap.Check(b.Limit > 0, "limit", "must be > 0")
 	errMap.Check(b.Limit <= 1000, "limit", "must be <= 1000")
 	errMap.Check(validator.In(b.Sort, b.SortSafeList), "sort", "invalid sort value")
+	errMap.Check(validator.In(b.SortDirection, []string{"ASC", "DESC"}), "sort-direction", "must be ASC or DESC")
 
 	return errMap
 }
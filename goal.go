@@ -0,0 +1,98 @@
+package dusk
+
+import (
+	"time"
+
+	"github.com/kencx/dusk/validator"
+)
+
+// Goal is a target number of books and/or pages to read in a year. A target of 0
+// is not set.
+type Goal struct {
+	Year  int `json:"year" db:"year"`
+	Books int `json:"books" db:"books"`
+	Pages int `json:"pages" db:"pages"`
+
+	Progress *GoalProgress `json:"progress,omitempty"`
+}
+
+// GoalProgress is the progress towards a goal at a point in the year.
+type GoalProgress struct {
+	BooksRead int `json:"books_read"`
+	PagesRead int `json:"pages_read"`
+
+	// totals expected by now to be on pace with the goal
+	ExpectedBooks int `json:"expected_books"`
+	ExpectedPages int `json:"expected_pages"`
+
+	// totals at the end of the year if the current pace is kept
+	ProjectedBooks int `json:"projected_books"`
+	ProjectedPages int `json:"projected_pages"`
+
+	OnPace bool `json:"on_pace"`
+}
+
+func (g Goal) Valid() validator.ErrMap {
+	errMap := validator.New()
+
+	errMap.Check(g.Year >= 1000 && g.Year <= 9999, "year", "must be a 4 digit year")
+	errMap.Check(g.Books >= 0, "books", "must be >= 0")
+	errMap.Check(g.Pages >= 0, "pages", "must be >= 0")
+	errMap.EitherOr(g.Books > 0, g.Pages > 0, "books", "pages", "at least one target must be set")
+
+	return errMap
+}
+
+// Project computes the progress of the goal given the books and pages read in
+// its year as of now.
+func (g *Goal) Project(booksRead, pagesRead int, now time.Time) {
+	elapsed := yearElapsed(g.Year, now)
+
+	p := &GoalProgress{
+		BooksRead:      booksRead,
+		PagesRead:      pagesRead,
+		ExpectedBooks:  int(float64(g.Books) * elapsed),
+		ExpectedPages:  int(float64(g.Pages) * elapsed),
+		ProjectedBooks: booksRead,
+		ProjectedPages: pagesRead,
+	}
+
+	if elapsed > 0 {
+		p.ProjectedBooks = int(float64(booksRead) / elapsed)
+		p.ProjectedPages = int(float64(pagesRead) / elapsed)
+	}
+
+	p.OnPace = booksRead >= p.ExpectedBooks && pagesRead >= p.ExpectedPages
+	g.Progress = p
+}
+
+// BooksPercent returns the percentage of the books target that has been read.
+func (g Goal) BooksPercent() int {
+	if g.Books == 0 || g.Progress == nil {
+		return 0
+	}
+	return min(100, g.Progress.BooksRead*100/g.Books)
+}
+
+// PagesPercent returns the percentage of the pages target that has been read.
+func (g Goal) PagesPercent() int {
+	if g.Pages == 0 || g.Progress == nil {
+		return 0
+	}
+	return min(100, g.Progress.PagesRead*100/g.Pages)
+}
+
+// fraction of the year that has elapsed by now, between 0 and 1
+func yearElapsed(year int, now time.Time) float64 {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, now.Location())
+	end := start.AddDate(1, 0, 0)
+
+	switch {
+	case now.Before(start):
+		return 0
+	case !now.Before(end):
+		return 1
+	default:
+		return float64(now.Sub(start)) / float64(end.Sub(start))
+	}
+}
@@ -0,0 +1,118 @@
+package dusk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGoalProject(t *testing.T) {
+	goal := Goal{Year: 2023, Books: 52, Pages: 10000}
+
+	tests := []struct {
+		name      string
+		booksRead int
+		pagesRead int
+		now       time.Time
+		want      GoalProgress
+	}{{
+		name:      "before year",
+		booksRead: 0,
+		pagesRead: 0,
+		now:       time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC),
+		want: GoalProgress{
+			OnPace: true,
+		},
+	}, {
+		name:      "ahead of pace",
+		booksRead: 30,
+		pagesRead: 6000,
+		now:       time.Date(2023, 7, 2, 12, 0, 0, 0, time.UTC),
+		want: GoalProgress{
+			BooksRead:      30,
+			PagesRead:      6000,
+			ExpectedBooks:  26,
+			ExpectedPages:  5000,
+			ProjectedBooks: 60,
+			ProjectedPages: 12000,
+			OnPace:         true,
+		},
+	}, {
+		name:      "behind pace",
+		booksRead: 20,
+		pagesRead: 6000,
+		now:       time.Date(2023, 7, 2, 12, 0, 0, 0, time.UTC),
+		want: GoalProgress{
+			BooksRead:      20,
+			PagesRead:      6000,
+			ExpectedBooks:  26,
+			ExpectedPages:  5000,
+			ProjectedBooks: 40,
+			ProjectedPages: 12000,
+			OnPace:         false,
+		},
+	}, {
+		name:      "after year",
+		booksRead: 50,
+		pagesRead: 12000,
+		now:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		want: GoalProgress{
+			BooksRead:      50,
+			PagesRead:      12000,
+			ExpectedBooks:  52,
+			ExpectedPages:  10000,
+			ProjectedBooks: 50,
+			ProjectedPages: 12000,
+			OnPace:         false,
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := goal
+			g.Project(tt.booksRead, tt.pagesRead, tt.now)
+
+			if *g.Progress != tt.want {
+				t.Errorf("got %+v, want %+v", *g.Progress, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateGoal(t *testing.T) {
+	tests := []struct {
+		name string
+		goal Goal
+		err  map[string]string
+	}{{
+		name: "books only",
+		goal: Goal{Year: 2024, Books: 12},
+		err:  nil,
+	}, {
+		name: "pages only",
+		goal: Goal{Year: 2024, Pages: 5000},
+		err:  nil,
+	}, {
+		name: "no target",
+		goal: Goal{Year: 2024},
+		err:  map[string]string{"books or pages": "at least one target must be set"},
+	}, {
+		name: "invalid year",
+		goal: Goal{Year: 24, Books: 12},
+		err:  map[string]string{"year": "must be a 4 digit year"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errMap := tt.goal.Valid()
+
+			if len(errMap) != len(tt.err) {
+				t.Fatalf("got %v, want %v", errMap, tt.err)
+			}
+			for k, v := range tt.err {
+				if errMap[k] != v {
+					t.Fatalf("got %v, want %v", errMap, tt.err)
+				}
+			}
+		})
+	}
+}
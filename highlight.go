@@ -0,0 +1,34 @@
+package dusk
+
+import (
+	"github.com/kencx/dusk/null"
+	"github.com/kencx/dusk/validator"
+)
+
+// Highlight is a passage of a book that was highlighted while reading, or a
+// note made at some point in the book.
+type Highlight struct {
+	Id     int64  `json:"id" db:"id"`
+	BookId int64  `json:"book_id" db:"bookId"`
+	Text   string `json:"text" db:"text"`
+
+	// page of the highlight, if known. Location is the position used by
+	// e-readers, e.g. 180-182 for Kindle locations.
+	Page     int         `json:"page,omitempty" db:"page"`
+	Location null.String `json:"location" db:"location"`
+	Chapter  null.String `json:"chapter" db:"chapter"`
+
+	Note            null.String `json:"note" db:"note"`
+	DateHighlighted null.Time   `json:"date_highlighted" db:"dateHighlighted"`
+
+	// book of the highlight, only set when searching across books
+	Book *Book `json:"book,omitempty"`
+}
+
+func (h Highlight) Valid() validator.ErrMap {
+	errMap := validator.New()
+
+	errMap.Check(h.Text != "" || h.Note.ValueOrZero() != "", "text", "value is missing")
+	errMap.Check(h.Page >= 0, "page", "must be >= 0")
+	return errMap
+}
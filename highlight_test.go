@@ -0,0 +1,43 @@
+package dusk
+
+import (
+	"testing"
+
+	"github.com/kencx/dusk/null"
+)
+
+func TestValidateHighlight(t *testing.T) {
+	tests := []struct {
+		name string
+		h    Highlight
+		keys []string
+	}{{
+		name: "success",
+		h:    Highlight{Text: "Doors and corners", Page: 12, Location: null.StringFrom("180-182")},
+	}, {
+		name: "note only",
+		h:    Highlight{Note: null.StringFrom("Miller")},
+	}, {
+		name: "empty",
+		h:    Highlight{},
+		keys: []string{"text"},
+	}, {
+		name: "negative page",
+		h:    Highlight{Text: "Doors and corners", Page: -1},
+		keys: []string{"page"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errMap := tt.h.Valid()
+			if len(errMap) != len(tt.keys) {
+				t.Fatalf("got errs %v, want errs for %v", errMap, tt.keys)
+			}
+			for _, k := range tt.keys {
+				if _, ok := errMap[k]; !ok {
+					t.Errorf("err field missing %q", k)
+				}
+			}
+		})
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/kencx/dusk/file"
 	"github.com/kencx/dusk/http/response"
 	"github.com/kencx/dusk/integration"
+	"github.com/kencx/dusk/jobs"
+	"github.com/kencx/dusk/opds"
 	"github.com/kencx/dusk/ui"
 	"github.com/kencx/dusk/util"
 
@@ -28,10 +30,11 @@ type Server struct {
 	db       dusk.Store
 	fs       *file.Service
 	f        integration.Fetchers
+	runner   *jobs.Runner
 	revision string
 }
 
-func New(revision string, db dusk.Store, fs *file.Service, f integration.Fetchers) *Server {
+func New(revision string, db dusk.Store, fs *file.Service, f integration.Fetchers, runner *jobs.Runner) *Server {
 	s := &Server{
 		Server: &http.Server{
 			IdleTimeout:  idleTimeout,
@@ -42,6 +45,7 @@ func New(revision string, db dusk.Store, fs *file.Service, f integration.Fetcher
 		db:       db,
 		fs:       fs,
 		f:        f,
+		runner:   runner,
 		revision: revision,
 	}
 	s.RegisterRoutes()
@@ -88,8 +92,9 @@ func (s *Server) RegisterRoutes() {
 		}
 		response.OK(w, r, res)
 	})
-	r.Mount("/api", api.Router(s.revision, s.db, s.fs))
-	r.Mount("/", ui.Router(s.revision, s.db, s.fs, s.f))
+	r.Mount("/api", api.Router(s.revision, s.db, s.fs, s.runner))
+	r.Mount("/opds", opds.Router(s.db))
+	r.Mount("/", ui.Router(s.revision, s.db, s.fs, s.f, s.runner))
 }
 
 // middleware to add http.TimeoutHandler.
@@ -17,3 +17,8 @@ func HxRedirect(rw http.ResponseWriter, r *http.Request, path string) {
 func AddHxTriggerAfterSwap(rw http.ResponseWriter, value string) {
 	rw.Header().Add("HX-Trigger-After-Swap", value)
 }
+
+func AddHxRetarget(rw http.ResponseWriter, target, swap string) {
+	rw.Header().Add("HX-Retarget", target)
+	rw.Header().Add("HX-Reswap", swap)
+}
@@ -101,6 +101,12 @@ func NotFound(rw http.ResponseWriter, r *http.Request, err error) {
 	res.write()
 }
 
+func Conflict(rw http.ResponseWriter, r *http.Request, err error) {
+	res := newError(rw, r, err)
+	res.statusCode = http.StatusConflict
+	res.write()
+}
+
 func Unauthorized(rw http.ResponseWriter, r *http.Request, err error) {
 	res := newError(rw, r, err)
 	res.statusCode = http.StatusUnauthorized
@@ -0,0 +1,109 @@
+package dusk
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// identifier schemes of a book in external sources
+const (
+	IdentifierAsin        = "asin"
+	IdentifierGoodreads   = "goodreads"
+	IdentifierGoogle      = "google"
+	IdentifierOpenLibrary = "openlibrary"
+)
+
+// identifierAliases are the names used by other sources for known schemes
+var identifierAliases = map[string]string{
+	"amazon":       IdentifierAsin,
+	"mobi-asin":    IdentifierAsin,
+	"google_books": IdentifierGoogle,
+	"olid":         IdentifierOpenLibrary,
+}
+
+// ignoredIdentifiers are not stored as identifiers. ISBNs have their own
+// fields, calibre ids are only unique within a calibre library and uuids are
+// generated for each file.
+var ignoredIdentifiers = []string{
+	"isbn", "isbn10", "isbn13", "isbn_10", "isbn_13", "calibre", "uuid",
+}
+
+// Identifiers maps an identifier scheme, e.g. goodreads, to the ids of a book
+// in that scheme. An id is unique within its scheme.
+type Identifiers map[string][]string
+
+// IdentifierScheme returns the normalized name of an identifier scheme.
+func IdentifierScheme(scheme string) string {
+	scheme = strings.ToLower(strings.TrimSpace(scheme))
+	if s, ok := identifierAliases[scheme]; ok {
+		return s
+	}
+	return scheme
+}
+
+// KnownIdentifierScheme reports whether the scheme, or its alias, is one of the
+// identifier schemes of dusk.
+func KnownIdentifierScheme(scheme string) bool {
+	switch IdentifierScheme(scheme) {
+	case IdentifierAsin, IdentifierGoodreads, IdentifierGoogle, IdentifierOpenLibrary:
+		return true
+	}
+	return false
+}
+
+// ParseIdentifier splits an identifier of the format scheme:value, e.g.
+// goodreads:12345.
+func ParseIdentifier(s string) (string, string, error) {
+	scheme, value, ok := strings.Cut(s, ":")
+	scheme, value = IdentifierScheme(scheme), strings.TrimSpace(value)
+	if !ok || scheme == "" || value == "" {
+		return "", "", fmt.Errorf("invalid identifier %q: must be scheme:value", s)
+	}
+	return scheme, value, nil
+}
+
+// Add adds the id to the given scheme if it is not ignored or already present.
+func (i *Identifiers) Add(scheme, value string) {
+	scheme, value = IdentifierScheme(scheme), strings.TrimSpace(value)
+	if scheme == "" || value == "" || slices.Contains(ignoredIdentifiers, scheme) {
+		return
+	}
+	if *i == nil {
+		*i = make(Identifiers)
+	}
+	if !slices.Contains((*i)[scheme], value) {
+		(*i)[scheme] = append((*i)[scheme], value)
+	}
+}
+
+// Normalize returns a copy of i with normalized schemes and without ignored or
+// duplicate ids.
+func (i Identifiers) Normalize() Identifiers {
+	return i.Merge(nil)
+}
+
+// Merge returns a copy of i with the missing ids of other added.
+func (i Identifiers) Merge(other Identifiers) Identifiers {
+	var result Identifiers
+	for _, ids := range []Identifiers{i, other} {
+		for scheme, values := range ids {
+			for _, v := range values {
+				result.Add(scheme, v)
+			}
+		}
+	}
+	return result
+}
+
+func (i Identifiers) Equal(other Identifiers) bool {
+	if len(i) != len(other) {
+		return false
+	}
+	for scheme, values := range i {
+		if !slices.Equal(values, other[scheme]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package dusk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIdentifiersAdd(t *testing.T) {
+	var got Identifiers
+	got.Add("Goodreads", " 12345 ")
+	got.Add("goodreads", "12345")
+	got.Add("amazon", "B00ABC")
+	got.Add("ISBN", "9780316129084")
+	got.Add("google", "")
+
+	want := Identifiers{
+		IdentifierGoodreads: {"12345"},
+		IdentifierAsin:      {"B00ABC"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseIdentifier(t *testing.T) {
+	tests := []struct {
+		input  string
+		scheme string
+		value  string
+		err    bool
+	}{
+		{"goodreads:12345", IdentifierGoodreads, "12345", false},
+		{"OLID:OL7353617M", IdentifierOpenLibrary, "OL7353617M", false},
+		{"asin:B00ABC:1", IdentifierAsin, "B00ABC:1", false},
+		{"12345", "", "", true},
+		{"goodreads:", "", "", true},
+		{":12345", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			scheme, value, err := ParseIdentifier(tt.input)
+			if (err != nil) != tt.err {
+				t.Fatalf("got err %v, want err %v", err, tt.err)
+			}
+			if scheme != tt.scheme || value != tt.value {
+				t.Errorf("got %s:%s, want %s:%s", scheme, value, tt.scheme, tt.value)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package integration
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
+)
+
+type AuthorFetcher interface {
+	FetchAuthor(a *dusk.Author) (*AuthorMetadata, error)
+}
+
+type AuthorMetadata struct {
+	Name        string
+	Bio         string
+	BirthDate   string
+	DeathDate   string
+	PhotoUrl    string
+	Identifiers map[string]string
+	Links       []dusk.AuthorLink
+}
+
+// FillAuthor fills in the missing profile of a. The photo is left to the
+// caller to download from PhotoUrl.
+func (m AuthorMetadata) FillAuthor(a *dusk.Author) {
+	if !a.Bio.Valid {
+		a.Bio = null.StringFrom(m.Bio)
+	}
+	if !a.BirthDate.Valid {
+		a.BirthDate = null.StringFrom(m.BirthDate)
+	}
+	if !a.DeathDate.Valid {
+		a.DeathDate = null.StringFrom(m.DeathDate)
+	}
+
+	for k, v := range m.Identifiers {
+		if _, ok := a.Identifiers[k]; ok || v == "" {
+			continue
+		}
+		if a.Identifiers == nil {
+			a.Identifiers = make(map[string]string)
+		}
+		a.Identifiers[k] = v
+	}
+
+	for _, l := range m.Links {
+		var exists bool
+		for _, existing := range a.Links {
+			if existing.Url == l.Url {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			a.Links = append(a.Links, l)
+		}
+	}
+}
+
+// FetchAuthor fetches the author profile from the first fetcher that supports
+// authors and knows the author.
+func (fs Fetchers) FetchAuthor(a *dusk.Author) (*AuthorMetadata, error) {
+	for _, f := range fs {
+		af, ok := f.(AuthorFetcher)
+		if !ok {
+			continue
+		}
+
+		m, err := af.FetchAuthor(a)
+		if err == nil {
+			return m, nil
+		}
+		slog.Warn("", slog.String("fetcher", f.GetName()), slog.Any("err", err))
+	}
+	return nil, errors.New("failed to fetch author from list of given fetchers")
+}
@@ -0,0 +1,208 @@
+package calibre
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
+	"github.com/kencx/dusk/util"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	// Source identifies books imported from Calibre
+	Source = "calibre"
+
+	metadataFilename = "metadata.db"
+	coverFilename    = "cover.jpg"
+)
+
+var ErrNotLibrary = errors.New("not a calibre library: metadata.db not found")
+
+// Book is a book read from a Calibre library.
+type Book struct {
+	*dusk.Book
+
+	// Calibre's unique id of the book
+	Uuid string `json:"uuid"`
+
+	// absolute paths of the format files and cover in the library
+	Files     []string `json:"files"`
+	CoverFile string   `json:"cover_file"`
+}
+
+type bookRow struct {
+	Id          int64       `db:"id"`
+	Uuid        null.String `db:"uuid"`
+	Title       string      `db:"title"`
+	Timestamp   null.String `db:"timestamp"`
+	Pubdate     null.String `db:"pubdate"`
+	SeriesIndex float64     `db:"series_index"`
+	Path        string      `db:"path"`
+	HasCover    bool        `db:"has_cover"`
+}
+
+// ReadLibrary reads all books in the Calibre library at dir from its
+// metadata.db. The database is opened read-only.
+func ReadLibrary(dir string) ([]*Book, error) {
+	dbPath := filepath.Join(dir, metadataFilename)
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, ErrNotLibrary
+	}
+
+	db, err := sqlx.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", dbPath))
+	if err != nil {
+		return nil, fmt.Errorf("calibre: failed to open %s: %w", dbPath, err)
+	}
+	defer db.Close()
+
+	var rows []bookRow
+	stmt := `SELECT id, uuid, title, timestamp, pubdate, series_index, path, has_cover
+		FROM books
+		ORDER BY id;`
+	if err := db.Select(&rows, stmt); err != nil {
+		return nil, fmt.Errorf("calibre: failed to read books: %w", err)
+	}
+
+	links := make(map[string]map[int64][]string)
+	for name, stmt := range map[string]string{
+		"authors": `SELECT l.book, a.name FROM books_authors_link l
+			INNER JOIN authors a ON a.id=l.author ORDER BY l.id;`,
+		"tags": `SELECT l.book, t.name FROM books_tags_link l
+			INNER JOIN tags t ON t.id=l.tag ORDER BY l.id;`,
+		"series": `SELECT l.book, s.name FROM books_series_link l
+			INNER JOIN series s ON s.id=l.series;`,
+		"publishers": `SELECT l.book, p.name FROM books_publishers_link l
+			INNER JOIN publishers p ON p.id=l.publisher;`,
+		"ratings": `SELECT l.book, r.rating FROM books_ratings_link l
+			INNER JOIN ratings r ON r.id=l.rating WHERE r.rating IS NOT NULL;`,
+		"comments": `SELECT book, text FROM comments;`,
+		"isbn":     `SELECT book, val FROM identifiers WHERE lower(type)='isbn';`,
+		"identifiers": `SELECT book, type || ':' || val FROM identifiers
+			WHERE lower(type)!='isbn' ORDER BY id;`,
+		"formats": `SELECT book, name || '.' || lower(format) FROM data ORDER BY id;`,
+	} {
+		links[name], err = readLinks(db, stmt)
+		if err != nil {
+			return nil, fmt.Errorf("calibre: failed to read %s: %w", name, err)
+		}
+	}
+
+	var books []*Book
+	for _, row := range rows {
+		bookDir := filepath.Join(dir, filepath.FromSlash(row.Path))
+
+		rating, _ := strconv.Atoi(first(links["ratings"][row.Id]))
+		isbn10, isbn13 := splitIsbn(links["isbn"][row.Id])
+
+		b := dusk.NewBook(
+			row.Title, "",
+			links["authors"][row.Id], links["tags"][row.Id], nil,
+			isbn10, isbn13,
+			0, 0, rating, dusk.Unread,
+			first(links["publishers"][row.Id]), first(links["series"][row.Id]),
+			first(links["comments"][row.Id]), "", "",
+			parseDate(row.Pubdate), parseDate(row.Timestamp), time.Time{}, time.Time{},
+		)
+		if b.Series.Valid && b.Series.String != "" {
+			b.SeriesPosition = null.NewFloat(row.SeriesIndex, true)
+		}
+		for _, id := range links["identifiers"][row.Id] {
+			if scheme, value, err := dusk.ParseIdentifier(id); err == nil {
+				b.Identifiers.Add(scheme, value)
+			}
+		}
+		if !b.DateAdded.Valid {
+			b.DateAdded = null.TimeFrom(time.Now())
+		}
+
+		book := &Book{Book: b, Uuid: row.Uuid.ValueOrZero()}
+		if book.Uuid == "" {
+			book.Uuid = strconv.FormatInt(row.Id, 10)
+		}
+		for _, f := range links["formats"][row.Id] {
+			book.Files = append(book.Files, filepath.Join(bookDir, f))
+		}
+		if row.HasCover {
+			book.CoverFile = filepath.Join(bookDir, coverFilename)
+		}
+		books = append(books, book)
+	}
+
+	slog.Info("[calibre] read library completed",
+		slog.Int("books", len(books)),
+		slog.String("path", dir),
+	)
+	return books, nil
+}
+
+// readLinks reads (book, value) pairs into a map of values by book id
+func readLinks(db *sqlx.DB, stmt string) (map[int64][]string, error) {
+	rows, err := db.Query(stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int64][]string)
+	for rows.Next() {
+		var (
+			id    int64
+			value null.String
+		)
+		if err := rows.Scan(&id, &value); err != nil {
+			return nil, err
+		}
+		if value.Valid && value.String != "" {
+			result[id] = append(result[id], value.String)
+		}
+	}
+	return result, rows.Err()
+}
+
+func splitIsbn(values []string) ([]string, []string) {
+	var isbn10, isbn13 []string
+	for _, v := range values {
+		isbn, err := util.IsbnExtract(v)
+		if err != nil || isbn == "" {
+			continue
+		}
+
+		if len(isbn) == 10 {
+			isbn10 = append(isbn10, isbn)
+		} else {
+			isbn13 = append(isbn13, isbn)
+		}
+	}
+	return isbn10, isbn13
+}
+
+// Calibre stores unknown dates as 0101-01-01
+func parseDate(value null.String) time.Time {
+	if !value.Valid {
+		return time.Time{}
+	}
+
+	t, err := dateparse.ParseAny(strings.TrimSpace(value.String))
+	if err != nil || t.Year() <= 101 {
+		return time.Time{}
+	}
+	return t
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
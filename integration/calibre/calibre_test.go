@@ -0,0 +1,148 @@
+package calibre
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kencx/dusk"
+)
+
+// subset of the Calibre schema that is read by the importer
+const testSchema = `
+CREATE TABLE books (id INTEGER PRIMARY KEY, title TEXT, timestamp TIMESTAMP, pubdate TIMESTAMP,
+	series_index REAL NOT NULL DEFAULT 1.0, path TEXT NOT NULL DEFAULT '', uuid TEXT, has_cover BOOL DEFAULT 0);
+CREATE TABLE authors (id INTEGER PRIMARY KEY, name TEXT);
+CREATE TABLE books_authors_link (id INTEGER PRIMARY KEY, book INTEGER, author INTEGER);
+CREATE TABLE tags (id INTEGER PRIMARY KEY, name TEXT);
+CREATE TABLE books_tags_link (id INTEGER PRIMARY KEY, book INTEGER, tag INTEGER);
+CREATE TABLE series (id INTEGER PRIMARY KEY, name TEXT);
+CREATE TABLE books_series_link (id INTEGER PRIMARY KEY, book INTEGER, series INTEGER);
+CREATE TABLE publishers (id INTEGER PRIMARY KEY, name TEXT);
+CREATE TABLE books_publishers_link (id INTEGER PRIMARY KEY, book INTEGER, publisher INTEGER);
+CREATE TABLE ratings (id INTEGER PRIMARY KEY, rating INTEGER);
+CREATE TABLE books_ratings_link (id INTEGER PRIMARY KEY, book INTEGER, rating INTEGER);
+CREATE TABLE comments (id INTEGER PRIMARY KEY, book INTEGER, text TEXT);
+CREATE TABLE identifiers (id INTEGER PRIMARY KEY, book INTEGER, type TEXT, val TEXT);
+CREATE TABLE data (id INTEGER PRIMARY KEY, book INTEGER, format TEXT, uncompressed_size INTEGER, name TEXT);
+
+INSERT INTO books VALUES
+	(1, 'Leviathan Wakes', '2023-01-02 10:00:00+00:00', '2011-06-15 00:00:00+00:00', 1.0, 'James S. A. Corey/Leviathan Wakes (1)', 'uuid-1', 1),
+	(2, 'Untitled', '2023-01-03 10:00:00+00:00', '0101-01-01 00:00:00+00:00', 1.0, 'Unknown/Untitled (2)', NULL, 0);
+INSERT INTO authors VALUES (1, 'James S. A. Corey'), (2, 'Unknown');
+INSERT INTO books_authors_link VALUES (1, 1, 1), (2, 2, 2);
+INSERT INTO tags VALUES (1, 'Science Fiction'), (2, 'Space Opera');
+INSERT INTO books_tags_link VALUES (1, 1, 1), (2, 1, 2);
+INSERT INTO series VALUES (1, 'The Expanse');
+INSERT INTO books_series_link VALUES (1, 1, 1);
+INSERT INTO publishers VALUES (1, 'Orbit');
+INSERT INTO books_publishers_link VALUES (1, 1, 1);
+INSERT INTO ratings VALUES (1, 8);
+INSERT INTO books_ratings_link VALUES (1, 1, 1);
+INSERT INTO comments VALUES (1, 1, '<p>Humanity has colonized the solar system.</p>');
+INSERT INTO identifiers VALUES (1, 1, 'isbn', '9780316129084'), (2, 1, 'goodreads', '8855321');
+INSERT INTO data VALUES
+	(1, 1, 'EPUB', 100, 'Leviathan Wakes - James S. A. Corey'),
+	(2, 1, 'PDF', 100, 'Leviathan Wakes - James S. A. Corey');
+`
+
+func newTestLibrary(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	db, err := sqlx.Open("sqlite3", filepath.Join(dir, metadataFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(testSchema); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestReadLibrary(t *testing.T) {
+	dir := newTestLibrary(t)
+
+	books, err := ReadLibrary(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(books) != 2 {
+		t.Fatalf("got %d books, want 2", len(books))
+	}
+
+	b := books[0]
+	if b.Uuid != "uuid-1" {
+		t.Errorf("got uuid %q, want %q", b.Uuid, "uuid-1")
+	}
+	if b.Title != "Leviathan Wakes" {
+		t.Errorf("got title %q, want %q", b.Title, "Leviathan Wakes")
+	}
+	if !reflect.DeepEqual(b.Tag, []string{"science fiction", "space opera"}) {
+		t.Errorf("got tags %v", b.Tag)
+	}
+	if b.Series.String != "The Expanse" || b.SeriesPosition.Float64 != 1 {
+		t.Errorf("got series %v #%v", b.Series, b.SeriesPosition)
+	}
+	if b.Publisher.String != "Orbit" {
+		t.Errorf("got publisher %v", b.Publisher)
+	}
+	if b.Rating != 8 {
+		t.Errorf("got rating %d, want 8", b.Rating)
+	}
+	if !reflect.DeepEqual(b.Isbn13, []string{"9780316129084"}) || len(b.Isbn10) != 0 {
+		t.Errorf("got isbn %v, isbn13 %v", b.Isbn10, b.Isbn13)
+	}
+	if !reflect.DeepEqual(b.Identifiers, dusk.Identifiers{dusk.IdentifierGoodreads: {"8855321"}}) {
+		t.Errorf("got identifiers %v", b.Identifiers)
+	}
+	if b.Description.String != "Humanity has colonized the solar system." {
+		t.Errorf("got description %q", b.Description.String)
+	}
+	if !b.DatePublished.Valid || b.DatePublished.Time.Year() != 2011 {
+		t.Errorf("got date published %v", b.DatePublished)
+	}
+
+	bookDir := filepath.Join(dir, "James S. A. Corey", "Leviathan Wakes (1)")
+	wantFiles := []string{
+		filepath.Join(bookDir, "Leviathan Wakes - James S. A. Corey.epub"),
+		filepath.Join(bookDir, "Leviathan Wakes - James S. A. Corey.pdf"),
+	}
+	if !reflect.DeepEqual(b.Files, wantFiles) {
+		t.Errorf("got files %v, want %v", b.Files, wantFiles)
+	}
+	if b.CoverFile != filepath.Join(bookDir, "cover.jpg") {
+		t.Errorf("got cover %q", b.CoverFile)
+	}
+
+	// missing uuid, unknown publication date and no files
+	b = books[1]
+	if b.Uuid != "2" {
+		t.Errorf("got uuid %q, want %q", b.Uuid, "2")
+	}
+	if b.DatePublished.Valid {
+		t.Errorf("got date published %v, want none", b.DatePublished)
+	}
+	if b.Series.String != "" || b.SeriesPosition.Valid {
+		t.Errorf("got series %v #%v, want none", b.Series, b.SeriesPosition)
+	}
+	if len(b.Files) != 0 || b.CoverFile != "" {
+		t.Errorf("got files %v and cover %q, want none", b.Files, b.CoverFile)
+	}
+}
+
+func TestReadLibraryNotLibrary(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "foo.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadLibrary(dir); !errors.Is(err, ErrNotLibrary) {
+		t.Errorf("got err %v, want %v", err, ErrNotLibrary)
+	}
+}
@@ -0,0 +1,120 @@
+package calibre
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/dedup"
+	"github.com/kencx/dusk/file"
+	"github.com/kencx/dusk/jobs"
+)
+
+const JobKind = "calibre"
+
+// JobItems encodes books as the items of an import job.
+func JobItems(books []*Book) ([]dusk.JobItem, error) {
+	var items []dusk.JobItem
+	for _, b := range books {
+		payload, err := json.Marshal(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode book %q: %w", b.Title, err)
+		}
+		items = append(items, dusk.JobItem{Name: b.Title, Payload: payload})
+	}
+	return items, nil
+}
+
+// ImportHandler creates the book of each item of an import job and copies its
+// format and cover files. Books that were imported before are skipped, so an
+// import can be re-run on the same library.
+func ImportHandler(db dusk.Store, fs *file.Service) jobs.HandlerFunc {
+	return func(ctx context.Context, item *dusk.JobItem) error {
+		var book Book
+		if err := json.Unmarshal(item.Payload, &book); err != nil {
+			return fmt.Errorf("failed to decode book: %w", err)
+		}
+
+		b, err := importBook(&book, db, fs)
+		if b != nil {
+			item.BookId = b.Id
+		}
+		return err
+	}
+}
+
+func importBook(book *Book, db dusk.Store, fs *file.Service) (*dusk.Book, error) {
+	existing, err := db.GetImportedBook(Source, book.Uuid)
+	if err == nil {
+		return existing, dusk.ErrSkipped
+	}
+	if !errors.Is(err, dusk.ErrDoesNotExist) {
+		return nil, err
+	}
+
+	if errMap := book.Valid(); len(errMap) > 0 {
+		return nil, errMap
+	}
+
+	// books that are already in the library, but were not imported from
+	// calibre, are merged instead
+	b, err := dedup.Add(db, book.Book, dedup.Merge)
+	merged := errors.Is(err, dusk.ErrSkipped)
+	if err != nil && !merged {
+		return nil, err
+	}
+
+	// the book is kept even if some of its files cannot be copied
+	var errs []error
+	for _, f := range book.Files {
+		if hasFormat(b, f) {
+			continue
+		}
+		if err := fs.UploadBookFormatFromPath(f, b); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if book.CoverFile != "" && !b.Cover.Valid {
+		if err := fs.UploadCoverFromPath(book.CoverFile, b); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(b.Formats) > 0 || b.Cover.Valid {
+		// update format and cover file paths
+		updated, err := db.UpdateBook(b.Id, b)
+		if err != nil && !errors.Is(err, dusk.ErrNoChange) {
+			errs = append(errs, err)
+		} else if err == nil {
+			b = updated
+		}
+	}
+
+	// the import is only recorded once all files are copied, so that
+	// re-running the import retries the missing files
+	if len(errs) > 0 {
+		return b, errors.Join(errs...)
+	}
+	if err := db.SetImportedBook(Source, book.Uuid, b.Id); err != nil {
+		return b, fmt.Errorf("failed to record import: %w", err)
+	}
+	if merged {
+		return b, dusk.ErrSkipped
+	}
+	return b, nil
+}
+
+// hasFormat reports whether b already has a file of the same format as path
+func hasFormat(b *dusk.Book, path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, f := range b.Formats {
+		if strings.ToLower(filepath.Ext(f)) == ext {
+			return true
+		}
+	}
+	return false
+}
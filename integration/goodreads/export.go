@@ -0,0 +1,223 @@
+package goodreads
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/filters"
+)
+
+const (
+	ExportFilename = "goodreads_library_export.csv"
+
+	// books are read from the store in pages of the max page size
+	exportPageLimit = 1000
+	dateFormat      = "2006/01/02"
+)
+
+// BookToRecord converts a book to a record with the columns of a Goodreads
+// export. The read count is taken from the book's reading sessions and the
+// owned copies from the number of its physical copies.
+func BookToRecord(b *dusk.Book) []string {
+	record := make([]string, len(headers))
+
+	title := b.Title
+	if b.Subtitle.Valid && b.Subtitle.String != "" {
+		title = fmt.Sprintf("%s: %s", title, b.Subtitle.String)
+	}
+	if b.Series.Valid && b.Series.String != "" {
+		if b.SeriesPosition.Valid {
+			title = fmt.Sprintf("%s (%s, #%s)", title, b.Series.String, strconv.FormatFloat(b.SeriesPosition.Float64, 'f', -1, 64))
+		} else {
+			title = fmt.Sprintf("%s (%s)", title, b.Series.String)
+		}
+	}
+
+	// the book id is the id of the book on Goodreads, if it is known
+	if ids := b.Identifiers[dusk.IdentifierGoodreads]; len(ids) > 0 {
+		record[0] = ids[0]
+	}
+	record[1] = title
+	if len(b.Author) > 0 {
+		record[2] = b.Author[0]
+		record[3] = lastFirst(b.Author[0])
+		record[4] = strings.Join(b.Author[1:], ", ")
+	}
+	record[5] = isbnValue(b.Isbn10)
+	record[6] = isbnValue(b.Isbn13)
+
+	// Goodreads ratings are whole stars
+	record[7] = strconv.Itoa((b.Rating + 1) / 2)
+	record[9] = b.Publisher.ValueOrZero()
+	if b.NumOfPages > 0 {
+		record[11] = strconv.Itoa(b.NumOfPages)
+	}
+	if b.DatePublished.Valid {
+		record[12] = strconv.Itoa(b.DatePublished.Time.Year())
+		record[13] = record[12]
+	}
+	if b.DateCompleted.Valid {
+		record[14] = b.DateCompleted.Time.Format(dateFormat)
+	}
+	if b.DateAdded.Valid {
+		record[15] = b.DateAdded.Time.Format(dateFormat)
+	}
+
+	var shelves []string
+	for _, t := range b.Tag {
+		if t != "" {
+			shelves = append(shelves, t)
+		}
+	}
+	record[16] = strings.Join(shelves, ", ")
+
+	switch b.Status {
+	case dusk.Read:
+		record[18] = "read"
+	case dusk.Reading:
+		record[18] = "currently-reading"
+	default:
+		record[18] = "to-read"
+	}
+
+	record[21] = b.Notes.ValueOrZero()
+
+	readCount := dusk.ReadCount(b.Sessions)
+	switch {
+	case readCount == 0 && b.Status == dusk.Read:
+		readCount = 1
+	case b.Status == dusk.Reading:
+		readCount++
+	}
+	record[22] = strconv.Itoa(readCount)
+	record[23] = strconv.Itoa(len(b.Copies))
+	return record
+}
+
+// WriteCSV writes the books in the Goodreads export format. With copies, the
+// details of each book's copies are written to an extra Copies column that
+// only dusk reads.
+func WriteCSV(w io.Writer, books dusk.Books, copies bool) error {
+	cw := csv.NewWriter(w)
+
+	header := headers
+	if copies {
+		header = append(slices.Clone(headers), copiesHeader)
+	}
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	for _, b := range books {
+		record := BookToRecord(b)
+		if copies {
+			record = append(record, copiesValue(b.Copies))
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv: %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// Export writes all books that match the filters, with their reading
+// sessions and copies, in the Goodreads export format. See WriteCSV for
+// copies.
+func Export(w io.Writer, db dusk.Store, f *filters.Book, copies bool) error {
+	f.AfterId = 0
+	f.Limit = exportPageLimit
+
+	var books dusk.Books
+	for {
+		p, err := db.GetAllBooks(f)
+		if err != nil && !errors.Is(err, dusk.ErrNoRows) {
+			return err
+		}
+		if p == nil || p.Info == nil {
+			break
+		}
+
+		for i := range p.Items {
+			b := &p.Items[i]
+			sessions, err := db.GetReadingSessions(b.Id)
+			if err != nil && !errors.Is(err, dusk.ErrDoesNotExist) {
+				return err
+			}
+			b.Sessions = sessions
+
+			copies, err := db.GetCopies(b.Id)
+			if err != nil {
+				return err
+			}
+			b.Copies = copies
+			books = append(books, b)
+		}
+
+		if p.IsLast() {
+			break
+		}
+		f.AfterId = p.LastRowNo
+	}
+
+	if err := WriteCSV(w, books, copies); err != nil {
+		return err
+	}
+
+	slog.Info("[csv] export csv completed", slog.Int("books", len(books)))
+	return nil
+}
+
+// Goodreads wraps isbns in ="..." to prevent spreadsheets from dropping
+// leading zeros
+func isbnValue(isbns []string) string {
+	for _, isbn := range isbns {
+		if isbn != "" {
+			return fmt.Sprintf(`="%s"`, isbn)
+		}
+	}
+	return `=""`
+}
+
+// copiesValue encodes the copies of a book and their loans as JSON, without
+// their ids
+func copiesValue(copies []dusk.Copy) string {
+	if len(copies) == 0 {
+		return ""
+	}
+
+	result := make([]dusk.Copy, len(copies))
+	for i, c := range copies {
+		c.Id, c.BookId = 0, 0
+		c.Loans = slices.Clone(c.Loans)
+		for j := range c.Loans {
+			c.Loans[j].Id, c.Loans[j].CopyId, c.Loans[j].Book = 0, 0, nil
+		}
+		result[i] = c
+	}
+
+	// a slice of copies with valid fields cannot fail to encode
+	value, _ := json.Marshal(result)
+	return string(value)
+}
+
+// lastFirst formats a name as "Last, First"
+func lastFirst(name string) string {
+	name = strings.TrimSpace(name)
+	i := strings.LastIndex(name, " ")
+	if i == -1 {
+		return name
+	}
+	return fmt.Sprintf("%s, %s", name[i+1:], name[:i])
+}
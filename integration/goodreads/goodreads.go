@@ -1,6 +1,7 @@
 package goodreads
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/araddon/dateparse"
 	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
 	"github.com/kencx/dusk/util"
 )
 
@@ -39,11 +41,23 @@ var headers = []string{
 	"Owned Copies",
 }
 
-func RecordToBook(record []string) (*dusk.Book, error) {
-	title, subtitle := extractSubtitle(record[1])
-	title, series := extractSeries(title)
+// copiesHeader is not part of a Goodreads export. The details of copies are
+// only written by dusk, as a JSON list of copies and their loans, when asked
+// for.
+const copiesHeader = "Copies"
 
-	authors := []string{record[2]}
+func RecordToBook(record []string) (*dusk.Book, error) {
+	// series must be extracted first as it is always at the end of the title
+	title, series, position := extractSeries(record[1])
+	title, subtitle := extractSubtitle(title)
+
+	// names are normalised on insert, so "Author l-f" is only needed when
+	// "Author" is missing
+	author := record[2]
+	if author == "" {
+		author = record[3]
+	}
+	authors := []string{author}
 	if record[4] != "" {
 		authors = append(authors, strings.Split(record[4], ",")...)
 	}
@@ -80,6 +94,20 @@ func RecordToBook(record []string) (*dusk.Book, error) {
 		record[9], series, "", record[21], "",
 		datePublished, dateAdded, time.Time{}, dateRead,
 	)
+	b.SeriesPosition = position
+	b.Identifiers.Add(dusk.IdentifierGoodreads, record[0])
+
+	readCount, _ := strconv.Atoi(record[22])
+	b.Sessions = readingSessions(status, readCount, b.DateCompleted)
+
+	// the number of owned copies is not imported without their details
+	if len(record) > 24 && record[24] != "" {
+		copies, err := recordToCopies(record[24])
+		if err != nil {
+			return nil, err
+		}
+		b.Copies = copies
+	}
 
 	errMap := b.Valid()
 	if len(errMap) > 0 {
@@ -88,6 +116,46 @@ func RecordToBook(record []string) (*dusk.Book, error) {
 	return b, nil
 }
 
+// recordToCopies reads the copies of a book exported by dusk
+func recordToCopies(value string) ([]dusk.Copy, error) {
+	var copies []dusk.Copy
+	if err := json.Unmarshal([]byte(value), &copies); err != nil {
+		return nil, fmt.Errorf("invalid copies: %w", err)
+	}
+	for i := range copies {
+		copies[i].Id, copies[i].BookId = 0, 0
+		for j := range copies[i].Loans {
+			copies[i].Loans[j].Id, copies[i].Loans[j].CopyId = 0, 0
+		}
+	}
+	return copies, nil
+}
+
+// readingSessions seeds a book's reading history from its read count. Only the
+// last read has a known date. Goodreads includes the current read in the read
+// count of a book that is currently being read.
+func readingSessions(status dusk.ReadStatus, readCount int, dateRead null.Time) []dusk.ReadingSession {
+	switch status {
+	case dusk.Read:
+		readCount = max(readCount, 1)
+	case dusk.Reading:
+		readCount = max(readCount-1, 0)
+	}
+
+	var sessions []dusk.ReadingSession
+	for range readCount {
+		sessions = append(sessions, dusk.ReadingSession{State: dusk.SessionFinished})
+	}
+	if readCount > 0 {
+		sessions[readCount-1].DateCompleted = dateRead
+	}
+
+	if status == dusk.Reading {
+		sessions = append(sessions, dusk.ReadingSession{State: dusk.SessionReading})
+	}
+	return sessions
+}
+
 func extractSubtitle(full string) (string, string) {
 	var title, subtitle string
 
@@ -101,17 +169,24 @@ func extractSubtitle(full string) (string, string) {
 	return title, subtitle
 }
 
-func extractSeries(full string) (string, string) {
-	var title, series string
+var seriesRx = regexp.MustCompile(`([a-zA-Z0-9 ',’:.?-@#$%&\!*()]+)[(]([a-zA-Z0-9 :?.'#,]+)[,]?[ ]#(\d+(?:\.\d+)?)[)]$`)
+
+// extractSeries extracts the series name and position from a title of the format
+// "Title (Series, #3)"
+func extractSeries(full string) (string, string, null.Float) {
+	var (
+		title, series string
+		position      null.Float
+	)
 
-	rx := regexp.MustCompile(`([a-zA-Z0-9 ',’:.?-@#$%&\!*()]+)[(]([a-zA-Z0-9 :?.'#,]+)[,]?[ ](#\d+)[)]$`)
-	for _, match := range rx.FindAllStringSubmatch(full, -1) {
+	for _, match := range seriesRx.FindAllStringSubmatch(full, -1) {
 		if len(match) > 1 {
-			title = match[1]
+			title = strings.TrimSpace(match[1])
+			series = strings.TrimRight(match[2], ", ")
 
-			series = match[2]
-			num := match[3]
-			series = fmt.Sprintf("%s, %s", series, num)
+			if num, err := strconv.ParseFloat(match[3], 64); err == nil {
+				position = null.NewFloat(num, true)
+			}
 		}
 	}
 
@@ -119,5 +194,5 @@ func extractSeries(full string) (string, string) {
 		title = full
 		series = ""
 	}
-	return title, series
+	return title, series, position
 }
@@ -0,0 +1,273 @@
+package goodreads
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
+	"github.com/matryer/is"
+)
+
+func TestExtractSeries(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		title    string
+		series   string
+		position null.Float
+	}{{
+		name:     "no series",
+		input:    "The Left Hand of Darkness",
+		title:    "The Left Hand of Darkness",
+		series:   "",
+		position: null.Float{},
+	}, {
+		name:     "series",
+		input:    "Leviathan Wakes (The Expanse, #1)",
+		title:    "Leviathan Wakes",
+		series:   "The Expanse",
+		position: null.NewFloat(1, true),
+	}, {
+		name:     "fractional position",
+		input:    "The Churn (The Expanse, #2.5)",
+		title:    "The Churn",
+		series:   "The Expanse",
+		position: null.NewFloat(2.5, true),
+	}, {
+		name:     "zero position",
+		input:    "New Spring (The Wheel of Time, #0)",
+		title:    "New Spring",
+		series:   "The Wheel of Time",
+		position: null.NewFloat(0, true),
+	}, {
+		name:     "subtitle",
+		input:    "Dune: Deluxe Edition (Dune, #1)",
+		title:    "Dune: Deluxe Edition",
+		series:   "Dune",
+		position: null.NewFloat(1, true),
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+
+			title, series, position := extractSeries(tt.input)
+			is.Equal(title, tt.title)
+			is.Equal(series, tt.series)
+			is.True(position.Equal(tt.position))
+		})
+	}
+}
+
+func TestReadingSessions(t *testing.T) {
+	dateRead := null.TimeFrom(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name      string
+		status    dusk.ReadStatus
+		readCount int
+		want      []dusk.ReadingSession
+	}{{
+		name:      "unread",
+		status:    dusk.Unread,
+		readCount: 0,
+		want:      nil,
+	}, {
+		name:      "read without read count",
+		status:    dusk.Read,
+		readCount: 0,
+		want: []dusk.ReadingSession{
+			{State: dusk.SessionFinished, DateCompleted: dateRead},
+		},
+	}, {
+		name:      "reread",
+		status:    dusk.Read,
+		readCount: 2,
+		want: []dusk.ReadingSession{
+			{State: dusk.SessionFinished},
+			{State: dusk.SessionFinished, DateCompleted: dateRead},
+		},
+	}, {
+		name:      "reading",
+		status:    dusk.Reading,
+		readCount: 1,
+		want: []dusk.ReadingSession{
+			{State: dusk.SessionReading},
+		},
+	}, {
+		name:      "rereading",
+		status:    dusk.Reading,
+		readCount: 2,
+		want: []dusk.ReadingSession{
+			{State: dusk.SessionFinished, DateCompleted: dateRead},
+			{State: dusk.SessionReading},
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+
+			got := readingSessions(tt.status, tt.readCount, dateRead)
+			is.Equal(got, tt.want)
+		})
+	}
+}
+
+func TestBookToRecord(t *testing.T) {
+	is := is.New(t)
+
+	b := &dusk.Book{
+		Id:             1,
+		Title:          "Leviathan Wakes",
+		Subtitle:       null.StringFrom("Deluxe Edition"),
+		Author:         []string{"James S. A. Corey", "Ty Franck"},
+		Tag:            []string{"scifi", "space opera"},
+		Isbn13:         []string{"9780316129084"},
+		Identifiers:    dusk.Identifiers{dusk.IdentifierGoodreads: {"8855321"}},
+		NumOfPages:     592,
+		Rating:         7,
+		Status:         dusk.Read,
+		Publisher:      null.StringFrom("Orbit"),
+		DatePublished:  null.TimeFrom(time.Date(2011, 6, 15, 0, 0, 0, 0, time.UTC)),
+		Series:         null.StringFrom("The Expanse"),
+		SeriesPosition: null.NewFloat(1, true),
+		Notes:          null.StringFrom("foo"),
+		DateAdded:      null.TimeFrom(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+		DateCompleted:  null.TimeFrom(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)),
+		Sessions: []dusk.ReadingSession{
+			{State: dusk.SessionFinished},
+			{State: dusk.SessionFinished},
+		},
+		Copies: []dusk.Copy{{
+			Id:           3,
+			Condition:    null.StringFrom("good"),
+			Location:     null.StringFrom("shelf"),
+			DateAcquired: null.TimeFrom(time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)),
+			Price:        null.NewFloat(9.99, true),
+			Loans: []dusk.Loan{{
+				Id:       5,
+				Borrower: "Alex",
+				DateLent: null.TimeFrom(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)),
+			}},
+		}},
+	}
+
+	record := BookToRecord(b)
+	is.Equal(len(record), len(headers))
+	is.Equal(record[0], "8855321")
+	is.Equal(record[1], "Leviathan Wakes: Deluxe Edition (The Expanse, #1)")
+	is.Equal(record[3], "Corey, James S. A.")
+	is.Equal(record[4], "Ty Franck")
+	is.Equal(record[5], `=""`)
+	is.Equal(record[6], `="9780316129084"`)
+	is.Equal(record[7], "4")
+	is.Equal(record[14], "2024/03/01")
+	is.Equal(record[16], "scifi, space opera")
+	is.Equal(record[18], "read")
+	is.Equal(record[22], "2")
+	is.Equal(record[23], "1")
+
+	// round trip
+	got, err := RecordToBook(record)
+	is.NoErr(err)
+	is.Equal(got.Title, b.Title)
+	is.Equal(got.Subtitle.String, b.Subtitle.String)
+	is.Equal(got.Author, b.Author)
+	is.Equal(got.Tag, b.Tag)
+	is.Equal(got.Isbn13, b.Isbn13)
+	is.Equal(got.Identifiers, b.Identifiers)
+	is.Equal(got.NumOfPages, b.NumOfPages)
+	is.Equal(got.Rating, 8)
+	is.Equal(got.Status, b.Status)
+	is.Equal(got.Publisher.String, b.Publisher.String)
+	is.Equal(got.DatePublished.Time.Year(), 2011)
+	is.Equal(got.Series.String, b.Series.String)
+	is.True(got.SeriesPosition.Equal(b.SeriesPosition))
+	is.Equal(got.Notes.String, b.Notes.String)
+	is.Equal(got.DateCompleted.Time, b.DateCompleted.Time)
+	is.Equal(dusk.ReadCount(got.Sessions), 2)
+
+	// only the copies column written by dusk has their details
+	is.Equal(len(got.Copies), 0)
+	got, err = RecordToBook(append(record, copiesValue(b.Copies)))
+	is.NoErr(err)
+	is.Equal(len(got.Copies), 1)
+	c := got.Copies[0]
+	is.Equal(c.Id, int64(0))
+	is.Equal(c.Condition, b.Copies[0].Condition)
+	is.Equal(c.Location, b.Copies[0].Location)
+	is.True(c.DateAcquired.Time.Equal(b.Copies[0].DateAcquired.Time))
+	is.True(c.Price.Equal(b.Copies[0].Price))
+	is.Equal(len(c.Loans), 1)
+	is.Equal(c.Loans[0].Id, int64(0))
+	is.Equal(c.Loans[0].Borrower, "Alex")
+	is.True(c.Loans[0].DateLent.Time.Equal(b.Copies[0].Loans[0].DateLent.Time))
+}
+
+func TestRecordToBookOwnedCopies(t *testing.T) {
+	is := is.New(t)
+
+	// a Goodreads export only has the number of owned copies
+	record := make([]string, len(headers))
+	record[1] = "Dune"
+	record[2] = "Frank Herbert"
+	record[23] = "2"
+
+	got, err := RecordToBook(record)
+	is.NoErr(err)
+	is.Equal(len(got.Copies), 0)
+}
+
+func TestWriteCSV(t *testing.T) {
+	books := dusk.Books{{
+		Title:  "Dune",
+		Author: []string{"Frank Herbert"},
+		Copies: []dusk.Copy{{Location: null.StringFrom("shelf")}},
+	}}
+
+	tests := []struct {
+		name    string
+		copies  bool
+		columns int
+	}{
+		{"goodreads", false, 24},
+		{"with copies", true, 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+
+			var buf bytes.Buffer
+			is.NoErr(WriteCSV(&buf, books, tt.copies))
+
+			records, err := csv.NewReader(&buf).ReadAll()
+			is.NoErr(err)
+			is.Equal(len(records), 2)
+			is.Equal(records[0][:len(headers)], headers)
+			is.Equal(len(records[0]), tt.columns)
+			is.Equal(len(records[1]), tt.columns)
+			is.Equal(records[1][23], "1")
+
+			got, err := RecordToBook(records[1])
+			is.NoErr(err)
+			if tt.copies {
+				is.Equal(records[0][24], copiesHeader)
+				is.Equal(got.Copies[0].Location, null.StringFrom("shelf"))
+			} else {
+				is.Equal(len(got.Copies), 0)
+			}
+		})
+	}
+}
+
+func TestLastFirst(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(lastFirst("Homer"), "Homer")
+	is.Equal(lastFirst(" Frank Herbert "), "Herbert, Frank")
+}
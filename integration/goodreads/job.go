@@ -0,0 +1,44 @@
+package goodreads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/dedup"
+	"github.com/kencx/dusk/jobs"
+)
+
+const JobKind = "goodreads"
+
+// JobItems encodes books as the items of an import job.
+func JobItems(books dusk.Books) ([]dusk.JobItem, error) {
+	var items []dusk.JobItem
+	for _, b := range books {
+		payload, err := json.Marshal(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode book %q: %w", b.Title, err)
+		}
+		items = append(items, dusk.JobItem{Name: b.Title, Payload: payload})
+	}
+	return items, nil
+}
+
+// ImportHandler creates the book of each item of an import job. Books that
+// already exist are merged into the existing book instead, so the same csv
+// can be imported again.
+func ImportHandler(db dusk.Store) jobs.HandlerFunc {
+	return func(ctx context.Context, item *dusk.JobItem) error {
+		var book dusk.Book
+		if err := json.Unmarshal(item.Payload, &book); err != nil {
+			return fmt.Errorf("failed to decode book: %w", err)
+		}
+
+		b, err := dedup.Add(db, &book, dedup.Merge)
+		if b != nil {
+			item.BookId = b.Id
+		}
+		return err
+	}
+}
@@ -67,7 +67,7 @@ func (m *GbMetadata) UnmarshalJSON(buf []byte) error {
 	m.NumberOfPages = vol.NumberOfPages
 	m.Publishers = append(m.Publishers, vol.Publisher)
 	m.PublishDate = vol.PublishDate
-	m.Identifiers = make(map[string][]string)
+	m.Identifiers = map[string][]string{dusk.IdentifierGoogle: {im.Items[0].Id}}
 
 	cover, err := FetchCover(im.Items[0].SelfLink)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/kencx/dusk"
 	"github.com/kencx/dusk/integration"
 )
 
@@ -39,7 +40,7 @@ func (q *GbQueryResults) UnmarshalJSON(buf []byte) error {
 				NumberOfPages: vol.NumberOfPages,
 				Publishers:    []string{vol.Publisher},
 				PublishDate:   vol.PublishDate,
-				Identifiers:   make(map[string][]string),
+				Identifiers:   map[string][]string{dusk.IdentifierGoogle: {item.Id}},
 			},
 		}
 
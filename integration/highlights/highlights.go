@@ -0,0 +1,66 @@
+// Package highlights imports the highlights and notes exported by e-readers,
+// such as Kindle's My Clippings.txt and KOReader's JSON export, into the books
+// of the library.
+package highlights
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/file"
+)
+
+// Clippings are the highlights made in a book, which is only known by its
+// title and authors.
+type Clippings struct {
+	Title      string           `json:"title"`
+	Author     []string         `json:"author"`
+	Highlights []dusk.Highlight `json:"highlights"`
+}
+
+// Read parses an export of highlights by its file extension. KOReader exports
+// are .json files, anything else is read as a Kindle My Clippings.txt file.
+func Read(payload *file.Payload) ([]Clippings, error) {
+	var clippings []Clippings
+	var err error
+
+	if strings.ToLower(filepath.Ext(payload.Filename)) == ".json" {
+		clippings, err = ReadKOReader(payload.File)
+	} else {
+		clippings, err = ReadKindle(payload.File)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(clippings) == 0 {
+		return nil, fmt.Errorf("no highlights found in %s", payload.Filename)
+	}
+	return clippings, nil
+}
+
+// add appends the highlight to the clippings of its book
+func add(clippings []Clippings, title string, author []string, h dusk.Highlight) []Clippings {
+	for i := range clippings {
+		if clippings[i].Title == title && slicesEqual(clippings[i].Author, author) {
+			clippings[i].Highlights = append(clippings[i].Highlights, h)
+			return clippings
+		}
+	}
+	return append(clippings, Clippings{title, author, []dusk.Highlight{h}})
+}
+
+func slicesEqual(a, b []string) bool {
+	return strings.Join(a, "\n") == strings.Join(b, "\n")
+}
+
+// firstLast formats a name of the format "Last, First" as "First Last"
+func firstLast(name string) string {
+	name = strings.TrimSpace(name)
+	last, first, ok := strings.Cut(name, ",")
+	if !ok || strings.Contains(first, ",") {
+		return name
+	}
+	return strings.TrimSpace(first) + " " + strings.TrimSpace(last)
+}
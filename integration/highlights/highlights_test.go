@@ -0,0 +1,130 @@
+package highlights
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+const clippings = "\ufeffLeviathan Wakes (Corey, James S. A.)\r\n" +
+	"- Your Highlight on page 12 | Location 180-182 | Added on Monday, March 4, 2024 9:15:02 PM\r\n" +
+	"\r\n" +
+	"The Scopuli had been taken eight days ago.\r\n" +
+	"==========\r\n" +
+	"Leviathan Wakes (Corey, James S. A.)\r\n" +
+	"- Your Note on page 12 | Location 182 | Added on Monday, March 4, 2024 9:16:40 PM\r\n" +
+	"\r\n" +
+	"Great opening\r\n" +
+	"==========\r\n" +
+	"Leviathan Wakes (Corey, James S. A.)\r\n" +
+	"- Your Bookmark on page 20 | Location 301 | Added on Monday, March 4, 2024 9:20:00 PM\r\n" +
+	"\r\n" +
+	"\r\n" +
+	"==========\r\n" +
+	"The Left Hand of Darkness (Ursula K. Le Guin)\r\n" +
+	"- Your Highlight at location 95-96 | Added on Tuesday, March 5, 2024 7:01:00 AM\r\n" +
+	"\r\n" +
+	"Light is the left hand of darkness\r\n" +
+	"==========\r\n" +
+	"Leviathan Wakes (Corey, James S. A.)\r\n" +
+	"- Your Note on page 40 | Location 610 | Added on Wednesday, March 6, 2024 10:00:00 PM\r\n" +
+	"\r\n" +
+	"Miller again\r\n" +
+	"==========\r\n"
+
+func TestReadKindle(t *testing.T) {
+	is := is.New(t)
+
+	// dates are in the local time of the device
+	defer func(loc *time.Location) { time.Local = loc }(time.Local)
+	time.Local = time.FixedZone("UTC+8", 8*60*60)
+
+	got, err := ReadKindle(strings.NewReader(clippings))
+	is.NoErr(err)
+	is.Equal(len(got), 2)
+
+	is.Equal(got[0].Title, "Leviathan Wakes")
+	is.Equal(got[0].Author, []string{"James S. A. Corey"})
+	is.Equal(len(got[0].Highlights), 2)
+
+	h := got[0].Highlights[0]
+	is.Equal(h.Text, "The Scopuli had been taken eight days ago.")
+	is.Equal(h.Page, 12)
+	is.Equal(h.Location.ValueOrZero(), "180-182")
+	is.Equal(h.Note.ValueOrZero(), "Great opening")
+	is.True(h.DateHighlighted.ValueOrZero().Equal(time.Date(2024, 3, 4, 21, 15, 2, 0, time.Local)))
+
+	// note without a highlight
+	h = got[0].Highlights[1]
+	is.Equal(h.Text, "")
+	is.Equal(h.Note.ValueOrZero(), "Miller again")
+	is.Equal(h.Page, 40)
+
+	is.Equal(got[1].Title, "The Left Hand of Darkness")
+	is.Equal(got[1].Author, []string{"Ursula K. Le Guin"})
+	is.Equal(got[1].Highlights[0].Location.ValueOrZero(), "95-96")
+	is.Equal(got[1].Highlights[0].Page, 0)
+}
+
+func TestReadKindleInvalid(t *testing.T) {
+	is := is.New(t)
+
+	// malformed entries are skipped
+	got, err := ReadKindle(strings.NewReader("Leviathan Wakes\n==========\n" + clippings))
+	is.NoErr(err)
+	is.Equal(len(got), 2)
+	is.Equal(len(got[0].Highlights), 2)
+}
+
+func TestReadKOReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{{
+		name: "single document",
+		input: `{"title": "Leviathan Wakes", "author": "James S. A. Corey", "entries": [
+			{"text": "The Scopuli had been taken eight days ago.", "chapter": "Prologue: Julie", "page": 12, "time": 1709586902},
+			{"text": "Doors and corners, kid.", "note": "Miller", "page": "xii", "time": 1709586960}
+		]}`,
+	}, {
+		name: "all documents",
+		input: `{"documents": [{"title": "Leviathan Wakes", "author": "James S. A. Corey", "entries": [
+			{"text": "The Scopuli had been taken eight days ago.", "chapter": "Prologue: Julie", "page": 12, "time": 1709586902},
+			{"text": "Doors and corners, kid.", "note": "Miller", "page": "xii", "time": 1709586960}
+		]}]}`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+
+			got, err := ReadKOReader(strings.NewReader(tt.input))
+			is.NoErr(err)
+			is.Equal(len(got), 1)
+			is.Equal(got[0].Title, "Leviathan Wakes")
+			is.Equal(got[0].Author, []string{"James S. A. Corey"})
+			is.Equal(len(got[0].Highlights), 2)
+
+			h := got[0].Highlights[0]
+			is.Equal(h.Page, 12)
+			is.Equal(h.Chapter.ValueOrZero(), "Prologue: Julie")
+			is.True(!h.Note.Valid)
+			is.True(h.DateHighlighted.ValueOrZero().Equal(time.Unix(1709586902, 0)))
+
+			h = got[0].Highlights[1]
+			is.Equal(h.Page, 0)
+			is.Equal(h.Location.ValueOrZero(), "xii")
+			is.Equal(h.Note.ValueOrZero(), "Miller")
+		})
+	}
+}
+
+func TestFirstLast(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(firstLast("Corey, James S. A."), "James S. A. Corey")
+	is.Equal(firstLast(" Ursula K. Le Guin "), "Ursula K. Le Guin")
+	is.Equal(firstLast("A, B, C"), "A, B, C")
+}
@@ -0,0 +1,58 @@
+package highlights
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/dedup"
+	"github.com/kencx/dusk/jobs"
+)
+
+const JobKind = "highlights"
+
+// JobItems encodes the clippings of each book as the items of an import job.
+func JobItems(clippings []Clippings) ([]dusk.JobItem, error) {
+	var items []dusk.JobItem
+	for _, c := range clippings {
+		payload, err := json.Marshal(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode highlights of %q: %w", c.Title, err)
+		}
+		items = append(items, dusk.JobItem{Name: c.Title, Payload: payload})
+	}
+	return items, nil
+}
+
+// ImportHandler adds the highlights of each item of an import job to the
+// best matching book in the library. Highlights that were imported before are
+// skipped, so the same file can be imported again.
+func ImportHandler(db dusk.Store) jobs.HandlerFunc {
+	return func(ctx context.Context, item *dusk.JobItem) error {
+		var c Clippings
+		if err := json.Unmarshal(item.Payload, &c); err != nil {
+			return fmt.Errorf("failed to decode highlights: %w", err)
+		}
+
+		matches, err := dedup.Find(db, &dusk.Book{Title: c.Title, Author: c.Author})
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("no book matches %q", c.Title)
+		}
+
+		book := matches[0].Book
+		item.BookId = book.Id
+
+		n, err := db.AddHighlights(book.Id, c.Highlights)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return dusk.ErrSkipped
+		}
+		return nil
+	}
+}
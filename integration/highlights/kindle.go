@@ -0,0 +1,196 @@
+package highlights
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
+)
+
+const (
+	kindleSeparator  = "=========="
+	kindleDateLayout = "Monday, January 2, 2006 3:04:05 PM"
+)
+
+// ReadKindle parses the highlights and notes of a Kindle My Clippings.txt
+// file. Bookmarks are ignored and notes are attached to the highlight they
+// were made on, if any. Malformed entries are skipped.
+func ReadKindle(r io.Reader) ([]Clippings, error) {
+	var (
+		clippings []Clippings
+		entry     []string
+		n         int
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) != kindleSeparator {
+			entry = append(entry, line)
+			continue
+		}
+
+		n++
+		added, err := addKindleEntry(clippings, entry)
+		if err != nil {
+			slog.Warn("[highlights] skipped malformed kindle entry", slog.Int("entry", n), slog.Any("err", err))
+		} else {
+			clippings = added
+		}
+		entry = nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read clippings: %w", err)
+	}
+	return clippings, nil
+}
+
+func addKindleEntry(clippings []Clippings, entry []string) ([]Clippings, error) {
+	// drop blank lines before the title
+	for len(entry) > 0 && strings.TrimSpace(strings.TrimPrefix(entry[0], "\ufeff")) == "" {
+		entry = entry[1:]
+	}
+	if len(entry) < 2 {
+		return nil, fmt.Errorf("missing title or metadata")
+	}
+
+	title, author := parseKindleTitle(strings.TrimPrefix(entry[0], "\ufeff"))
+	if title == "" {
+		return nil, fmt.Errorf("missing title")
+	}
+
+	kind, h := parseKindleMetadata(entry[1])
+	text := strings.TrimSpace(strings.Join(entry[2:], "\n"))
+
+	switch kind {
+	case "highlight":
+		if text == "" {
+			return clippings, nil
+		}
+		h.Text = text
+	case "note":
+		if text == "" {
+			return clippings, nil
+		}
+		if prev := lastHighlight(clippings, title, author); prev != nil && notedOn(prev, &h) {
+			prev.Note = null.StringFrom(text)
+			return clippings, nil
+		}
+		h.Note = null.StringFrom(text)
+	default:
+		// bookmarks and clippings of unknown kind
+		return clippings, nil
+	}
+	return add(clippings, title, author, h), nil
+}
+
+// parseKindleTitle splits the title line "Title (Last, First; Other Author)"
+// into the title and its authors.
+func parseKindleTitle(line string) (string, []string) {
+	line = strings.TrimSpace(line)
+	if !strings.HasSuffix(line, ")") {
+		return line, nil
+	}
+
+	start := strings.LastIndex(line, "(")
+	if start <= 0 {
+		return line, nil
+	}
+
+	var author []string
+	for _, a := range strings.Split(line[start+1:len(line)-1], ";") {
+		if a = firstLast(a); a != "" {
+			author = append(author, a)
+		}
+	}
+	return strings.TrimSpace(line[:start]), author
+}
+
+// parseKindleMetadata parses the line
+// "- Your Highlight on page 12 | Location 180-182 | Added on Monday, ..."
+// into the kind of clipping and its position.
+func parseKindleMetadata(line string) (string, dusk.Highlight) {
+	var (
+		kind string
+		h    dusk.Highlight
+	)
+
+	for i, segment := range strings.Split(strings.TrimPrefix(line, "-"), "|") {
+		segment = strings.TrimSpace(segment)
+		lower := strings.ToLower(segment)
+
+		if i == 0 {
+			for _, k := range []string{"highlight", "note", "bookmark"} {
+				if strings.Contains(lower, k) {
+					kind = k
+					break
+				}
+			}
+		}
+
+		switch {
+		case strings.Contains(lower, "page "):
+			fields := strings.Fields(lower[strings.Index(lower, "page ")+len("page "):])
+			if len(fields) > 0 {
+				if page, err := strconv.Atoi(fields[0]); err == nil && page >= 0 {
+					h.Page = page
+				}
+			}
+		case strings.Contains(lower, "location "):
+			fields := strings.Fields(segment[strings.Index(lower, "location ")+len("location "):])
+			if len(fields) > 0 {
+				h.Location = null.StringFrom(fields[0])
+			}
+		case strings.HasPrefix(lower, "added on "):
+			h.DateHighlighted = parseKindleDate(segment[len("added on "):])
+		}
+	}
+	return kind, h
+}
+
+// parseKindleDate parses the date of a clipping, which the Kindle records in
+// its local time without a time zone.
+func parseKindleDate(s string) null.Time {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation(kindleDateLayout, s, time.Local); err == nil {
+		return null.TimeFrom(t)
+	}
+	if t, err := dateparse.ParseLocal(s); err == nil {
+		return null.TimeFrom(t)
+	}
+	return null.Time{}
+}
+
+func lastHighlight(clippings []Clippings, title string, author []string) *dusk.Highlight {
+	for i := range clippings {
+		c := &clippings[i]
+		if c.Title == title && slicesEqual(c.Author, author) && len(c.Highlights) > 0 {
+			return &c.Highlights[len(c.Highlights)-1]
+		}
+	}
+	return nil
+}
+
+// notedOn reports whether the note was made on highlight h. Kindle places a
+// note at the end of the highlighted passage.
+func notedOn(h, note *dusk.Highlight) bool {
+	if h.Text == "" || h.Note.Valid {
+		return false
+	}
+	if h.Location.Valid && note.Location.Valid {
+		_, end, _ := strings.Cut(h.Location.String, "-")
+		if end == "" {
+			end = h.Location.String
+		}
+		return note.Location.String == end || note.Location.String == h.Location.String
+	}
+	return note.Page != 0 && h.Page == note.Page
+}
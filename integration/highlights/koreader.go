@@ -0,0 +1,84 @@
+package highlights
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
+)
+
+type koreaderEntry struct {
+	Text    string          `json:"text"`
+	Note    string          `json:"note"`
+	Chapter string          `json:"chapter"`
+	Page    json.RawMessage `json:"page"`
+	Time    int64           `json:"time"`
+}
+
+type koreaderDocument struct {
+	Title   string          `json:"title"`
+	Author  string          `json:"author"`
+	Entries []koreaderEntry `json:"entries"`
+}
+
+// ReadKOReader parses the JSON export of KOReader's highlight exporter, which
+// is either a single document or all documents under "documents".
+func ReadKOReader(r io.Reader) ([]Clippings, error) {
+	var export struct {
+		koreaderDocument
+		Documents []koreaderDocument `json:"documents"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("failed to decode KOReader export: %w", err)
+	}
+
+	documents := export.Documents
+	if export.Title != "" {
+		documents = append(documents, export.koreaderDocument)
+	}
+
+	var clippings []Clippings
+	for _, d := range documents {
+		title := strings.TrimSpace(d.Title)
+		if title == "" {
+			continue
+		}
+
+		var author []string
+		for _, a := range strings.Split(d.Author, "\n") {
+			if a = firstLast(a); a != "" {
+				author = append(author, a)
+			}
+		}
+
+		for _, e := range d.Entries {
+			h := dusk.Highlight{
+				Text:    strings.TrimSpace(e.Text),
+				Chapter: null.StringFrom(strings.TrimSpace(e.Chapter)),
+				Note:    null.StringFrom(strings.TrimSpace(e.Note)),
+			}
+			if h.Text == "" && !h.Note.Valid {
+				continue
+			}
+
+			// page is a string for documents with reference pages
+			page := strings.Trim(string(e.Page), `"`)
+			if n, err := strconv.Atoi(page); err == nil && n >= 0 {
+				h.Page = n
+			} else if page != "null" {
+				h.Location = null.StringFrom(page)
+			}
+			if e.Time > 0 {
+				h.DateHighlighted = null.TimeFrom(time.Unix(e.Time, 0).UTC())
+			}
+			clippings = add(clippings, title, author, h)
+		}
+	}
+	return clippings, nil
+}
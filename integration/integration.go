@@ -7,6 +7,7 @@ import (
 
 	"github.com/araddon/dateparse"
 	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
 )
 
 var ErrInvalidMetadata = errors.New("invalid metadata")
@@ -23,13 +24,15 @@ type Metadata struct {
 	PublishDate   string
 	Publishers    []string
 	CoverUrl      string
+
+	// Open Library work key of the edition
+	Work string
 }
 
 func (m Metadata) ToBook() *dusk.Book {
 	var (
 		isbn, isbn13      []string
 		publisher, series string
-		identifiers       = make(map[string]string)
 		tags              []string
 		datePublished     time.Time
 	)
@@ -42,20 +45,12 @@ func (m Metadata) ToBook() *dusk.Book {
 		series = m.Series[0]
 	}
 
-	if len(m.Identifiers) > 0 {
-		for k, v := range m.Identifiers {
-			if len(v) > 0 {
-				identifiers[k] = v[0]
-			}
-		}
-	}
-
 	datePublished, err := dateparse.ParseAny(m.PublishDate)
 	if err != nil {
 		slog.Warn("failed to parse publish date", slog.Any("err", err))
 	}
 
-	return dusk.NewBook(
+	b := dusk.NewBook(
 		m.Title, m.Subtitle,
 		m.Authors, tags, nil,
 		isbn, isbn13,
@@ -63,6 +58,15 @@ func (m Metadata) ToBook() *dusk.Book {
 		publisher, series, "", "", m.CoverUrl,
 		datePublished, time.Time{}, time.Time{}, time.Time{},
 	)
+	for scheme, values := range m.Identifiers {
+		for _, v := range values {
+			b.Identifiers.Add(scheme, v)
+		}
+	}
+	if m.Work != "" {
+		b.Work = &dusk.Work{Title: b.Title, OpenLibrary: null.StringFrom(m.Work)}
+	}
+	return b
 }
 
 func GetFirst(sl []string) string {
@@ -0,0 +1,156 @@
+package openlibrary
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/integration"
+)
+
+const (
+	authorEndpoint       = "%s/authors/%s.json"
+	authorSearchEndpoint = "%s/search/authors.json?q=%s&limit=10"
+	authorPhotoEndpoint  = "%s/a/id/%d-L.jpg"
+)
+
+type authorJson struct {
+	Key       string            `json:"key"`
+	Name      string            `json:"name"`
+	BirthDate string            `json:"birth_date"`
+	DeathDate string            `json:"death_date"`
+	Wikipedia string            `json:"wikipedia"`
+	Photos    []int             `json:"photos"`
+	RemoteIds map[string]string `json:"remote_ids"`
+	Links     []struct {
+		Title string `json:"title"`
+		Url   string `json:"url"`
+	} `json:"links"`
+
+	// bio is either a string or a text object
+	Bio json.RawMessage `json:"bio"`
+}
+
+type authorSearchJson struct {
+	Docs []struct {
+		Key            string   `json:"key"`
+		Name           string   `json:"name"`
+		AlternateNames []string `json:"alternate_names"`
+	} `json:"docs"`
+}
+
+// FetchAuthor fetches the author by its openlibrary identifier or, if it has
+// none, by searching for its name.
+func (f *Fetcher) FetchAuthor(a *dusk.Author) (*integration.AuthorMetadata, error) {
+	c := f.client()
+
+	key := a.Identifiers["openlibrary"]
+	if key == "" {
+		var err error
+		key, err = c.SearchAuthor(a)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search author: %w", err)
+		}
+	}
+
+	m, err := c.FetchAuthor(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch author: %w", err)
+	}
+	return m, nil
+}
+
+// SearchAuthor returns the key of the first result whose name or alternate
+// names match the name or aliases of a.
+func (c *Client) SearchAuthor(a *dusk.Author) (string, error) {
+	name := url.QueryEscape(a.Name)
+	url := fmt.Sprintf(authorSearchEndpoint, c.Host, name)
+	var results authorSearchJson
+
+	slog.Debug("[openlibrary] Searching author", slog.String("url", url))
+
+	if err := c.get(url, &results); err != nil {
+		return "", err
+	}
+
+	names := append([]string{a.Name}, a.Aliases...)
+	for _, doc := range results.Docs {
+		for _, n := range append([]string{doc.Name}, doc.AlternateNames...) {
+			if matchesName(n, names) {
+				return doc.Key, nil
+			}
+		}
+	}
+	return "", dusk.ErrDoesNotExist
+}
+
+// FetchAuthor fetches the author with the given key, e.g. OL23919A
+func (c *Client) FetchAuthor(key string) (*integration.AuthorMetadata, error) {
+	key = strings.TrimPrefix(key, "/authors/")
+	url := fmt.Sprintf(authorEndpoint, c.Host, key)
+	var aj authorJson
+
+	slog.Debug("[openlibrary] Fetching author", slog.String("url", url))
+
+	if err := c.get(url, &aj); err != nil {
+		return nil, err
+	}
+
+	m := &integration.AuthorMetadata{
+		Name:        aj.Name,
+		Bio:         parseText(aj.Bio),
+		BirthDate:   aj.BirthDate,
+		DeathDate:   aj.DeathDate,
+		Identifiers: map[string]string{"openlibrary": key},
+	}
+
+	for k, v := range aj.RemoteIds {
+		m.Identifiers[k] = v
+	}
+	for _, l := range aj.Links {
+		m.Links = append(m.Links, dusk.AuthorLink{Title: l.Title, Url: l.Url})
+	}
+	if aj.Wikipedia != "" {
+		m.Links = append(m.Links, dusk.AuthorLink{Title: "Wikipedia", Url: aj.Wikipedia})
+	}
+
+	// missing photos are -1
+	if len(aj.Photos) > 0 && aj.Photos[0] > 0 {
+		m.PhotoUrl = fmt.Sprintf(authorPhotoEndpoint, c.CoversHost, aj.Photos[0])
+	}
+	return m, nil
+}
+
+// parseText parses a field that is either a string or an object of the form
+// {"type": "/type/text", "value": "..."}
+func parseText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	var text struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text.Value
+	}
+	return ""
+}
+
+func matchesName(name string, names []string) bool {
+	name = dusk.NormaliseAuthorName(name)
+	for _, n := range names {
+		if strings.EqualFold(name, dusk.NormaliseAuthorName(n)) {
+			return true
+		}
+	}
+	return false
+}
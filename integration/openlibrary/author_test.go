@@ -0,0 +1,79 @@
+package openlibrary
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kencx/dusk"
+	"github.com/matryer/is"
+)
+
+func newTestClient(t *testing.T) *Client {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search/authors.json", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(`{"numFound": 2, "docs": [
+			{"key": "OL1A", "name": "Ursula Le Guin"},
+			{"key": "OL26320A", "name": "Ursula K. Le Guin", "alternate_names": ["Le Guin, Ursula K."]}
+		]}`))
+	})
+	mux.HandleFunc("/authors/OL26320A.json", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(`{
+			"key": "/authors/OL26320A",
+			"name": "Ursula K. Le Guin",
+			"birth_date": "21 October 1929",
+			"death_date": "22 January 2018",
+			"bio": {"type": "/type/text", "value": "American author."},
+			"photos": [6295259, -1],
+			"remote_ids": {"wikidata": "Q181659"},
+			"links": [{"title": "Official Site", "url": "https://www.ursulakleguin.com"}],
+			"wikipedia": "https://en.wikipedia.org/wiki/Ursula_K._Le_Guin"
+		}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return &Client{Http: srv.Client(), Host: srv.URL, CoversHost: "https://covers.test"}
+}
+
+func TestFetchAuthor(t *testing.T) {
+	is := is.New(t)
+	f := &Fetcher{Client: newTestClient(t)}
+
+	got, err := f.FetchAuthor(&dusk.Author{Name: "Ursula K. Le Guin"})
+	is.NoErr(err)
+
+	is.Equal(got.Name, "Ursula K. Le Guin")
+	is.Equal(got.Bio, "American author.")
+	is.Equal(got.BirthDate, "21 October 1929")
+	is.Equal(got.DeathDate, "22 January 2018")
+	is.Equal(got.PhotoUrl, "https://covers.test/a/id/6295259-L.jpg")
+	is.Equal(got.Identifiers, map[string]string{"openlibrary": "OL26320A", "wikidata": "Q181659"})
+	is.Equal(len(got.Links), 2)
+	is.Equal(got.Links[1], dusk.AuthorLink{Title: "Wikipedia", Url: "https://en.wikipedia.org/wiki/Ursula_K._Le_Guin"})
+}
+
+func TestFetchAuthorByIdentifier(t *testing.T) {
+	is := is.New(t)
+	f := &Fetcher{Client: newTestClient(t)}
+
+	got, err := f.FetchAuthor(&dusk.Author{Name: "Someone Else", Identifiers: map[string]string{"openlibrary": "OL26320A"}})
+	is.NoErr(err)
+	is.Equal(got.Name, "Ursula K. Le Guin")
+
+	_, err = f.FetchAuthor(&dusk.Author{Name: "Someone Else", Identifiers: map[string]string{"openlibrary": "OL0A"}})
+	is.True(errors.Is(err, dusk.ErrDoesNotExist))
+}
+
+func TestSearchAuthorNoMatch(t *testing.T) {
+	is := is.New(t)
+	c := newTestClient(t)
+
+	_, err := c.SearchAuthor(&dusk.Author{Name: "John Doe"})
+	is.True(errors.Is(err, dusk.ErrDoesNotExist))
+
+	key, err := c.SearchAuthor(&dusk.Author{Name: "Le Guin, Ursula K."})
+	is.NoErr(err)
+	is.Equal(key, "OL26320A")
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
+	"github.com/kencx/dusk"
 	"github.com/kencx/dusk/integration"
 )
 
@@ -14,6 +16,7 @@ type OlMetadata struct {
 
 func (m *OlMetadata) UnmarshalJSON(buf []byte) error {
 	var im struct {
+		Key           string `json:"key"`
 		Title         string `json:"title"`
 		Subtitle      string `json:"subtitle,omitempty"`
 		NumberOfPages int    `json:"number_of_pages,omitempty"`
@@ -75,6 +78,15 @@ func (m *OlMetadata) UnmarshalJSON(buf []byte) error {
 	m.Isbn10 = im.Isbn10
 	m.Isbn13 = im.Isbn13
 	m.Identifiers = im.Identifiers
+	if key := strings.TrimPrefix(im.Key, "/books/"); key != "" {
+		if m.Identifiers == nil {
+			m.Identifiers = make(map[string][]string)
+		}
+		m.Identifiers[dusk.IdentifierOpenLibrary] = append(m.Identifiers[dusk.IdentifierOpenLibrary], key)
+	}
+	if len(im.Works) > 0 {
+		m.Work = strings.TrimPrefix(im.Works[0].Key, "/works/")
+	}
 	m.NumberOfPages = im.NumberOfPages
 	m.Series = im.Series
 	m.Publishers = im.Publishers
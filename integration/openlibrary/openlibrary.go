@@ -9,12 +9,16 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/kencx/dusk"
 	"github.com/kencx/dusk/filters"
 	"github.com/kencx/dusk/integration"
 	"github.com/kencx/dusk/page"
 )
 
 const (
+	olHost     = "https://openlibrary.org"
+	coversHost = "https://covers.openlibrary.org"
+
 	olEndpoint   = "https://openlibrary.org%s.json"
 	isbnEndpoint = "https://openlibrary.org/isbn/%s.json"
 
@@ -28,7 +32,17 @@ const (
 	clientTimeout = 5 * time.Second
 )
 
-type Fetcher struct{}
+type Fetcher struct {
+	// Client is used to fetch authors. It defaults to NewClient if nil.
+	Client *Client
+}
+
+func (f *Fetcher) client() *Client {
+	if f.Client == nil {
+		return NewClient()
+	}
+	return f.Client
+}
 
 func (f *Fetcher) GetName() string {
 	return "Openlibrary"
@@ -74,17 +88,36 @@ func (f *Fetcher) FetchByWork() (*page.Page[integration.Metadata], error) {
 	return nil, nil
 }
 
-func fetch(url string, dest interface{}) error {
-	client := http.Client{
-		Timeout: clientTimeout,
+// Client fetches JSON from Open Library. Its hosts and HTTP client can be
+// replaced to fetch from a test server instead.
+type Client struct {
+	Http       *http.Client
+	Host       string
+	CoversHost string
+}
+
+func NewClient() *Client {
+	return &Client{
+		Http:       &http.Client{Timeout: clientTimeout},
+		Host:       olHost,
+		CoversHost: coversHost,
 	}
+}
 
-	resp, err := client.Get(url)
+func (c *Client) get(url string, dest interface{}) error {
+	resp, err := c.Http.Get(url)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return dusk.ErrDoesNotExist
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return err
@@ -95,3 +128,7 @@ func fetch(url string, dest interface{}) error {
 	}
 	return nil
 }
+
+func fetch(url string, dest interface{}) error {
+	return NewClient().get(url, dest)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strconv"
+	"strings"
 
 	"github.com/kencx/dusk"
 	"github.com/kencx/dusk/integration"
@@ -67,6 +68,7 @@ func (q *OlQueryResults) UnmarshalJSON(buf []byte) error {
 				Publishers:    r.Publishers,
 				NumberOfPages: r.NumberOfPages,
 				CoverUrl:      fmt.Sprintf(coverIdEndpoint, strconv.Itoa(r.CoverId), "M"),
+				Work:          strings.TrimPrefix(work.Key, "/works/"),
 			}
 
 			m.PublishDate = integration.GetFirst(r.PublishDate)
@@ -0,0 +1,80 @@
+package dusk
+
+import (
+	"errors"
+
+	"github.com/kencx/dusk/null"
+)
+
+type JobState int
+
+const (
+	JobQueued JobState = iota
+	JobRunning
+	JobCompleted
+	JobCancelled
+)
+
+type JobItemState int
+
+const (
+	ItemPending JobItemState = iota
+	ItemDone
+	ItemFailed
+	ItemSkipped
+)
+
+// ErrSkipped is returned by a job handler when an item was already processed
+// before, eg. a book that was imported in a previous run.
+var ErrSkipped = errors.New("item skipped")
+
+// Job is a long running task, such as an import, that is processed in the
+// background item by item.
+type Job struct {
+	Id            int64       `json:"id" db:"id"`
+	Kind          string      `json:"kind" db:"kind"`
+	State         JobState    `json:"state" db:"state"`
+	Error         null.String `json:"error,omitempty" db:"error"`
+	DateCreated   null.Time   `json:"date_created" db:"dateCreated"`
+	DateStarted   null.Time   `json:"date_started" db:"dateStarted"`
+	DateCompleted null.Time   `json:"date_completed" db:"dateCompleted"`
+
+	// item counts by state
+	Total   int `json:"total" db:"total"`
+	Done    int `json:"done" db:"done"`
+	Failed  int `json:"failed" db:"failed"`
+	Skipped int `json:"skipped" db:"skipped"`
+
+	Items []JobItem `json:"items,omitempty"`
+}
+
+// JobItem is a single unit of work of a job. Its payload is decoded by the
+// handler of the job's kind.
+type JobItem struct {
+	Id     int64        `json:"id" db:"id"`
+	JobId  int64        `json:"job_id" db:"jobId"`
+	Name   string       `json:"name" db:"name"`
+	State  JobItemState `json:"state" db:"state"`
+	Error  null.String  `json:"error,omitempty" db:"error"`
+	BookId int64        `json:"book_id,omitempty" db:"bookId"`
+
+	Payload []byte `json:"-" db:"payload"`
+}
+
+// Finished reports if the job will not process any more items.
+func (j Job) Finished() bool {
+	return j.State == JobCompleted || j.State == JobCancelled
+}
+
+// Processed returns the number of items that are no longer pending.
+func (j Job) Processed() int {
+	return j.Done + j.Failed + j.Skipped
+}
+
+// Percent returns the percentage of processed items.
+func (j Job) Percent() int {
+	if j.Total == 0 {
+		return 100
+	}
+	return j.Processed() * 100 / j.Total
+}
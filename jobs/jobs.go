@@ -0,0 +1,332 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
+)
+
+var ErrUnknownKind = errors.New("unknown job kind")
+
+// HandlerFunc processes a single item of a job. It may set the item's BookId.
+// Returning dusk.ErrSkipped marks the item as skipped instead of done.
+type HandlerFunc func(ctx context.Context, item *dusk.JobItem) error
+
+// Runner processes queued jobs in the background with a pool of workers. Each
+// job is run by a single worker, item by item, and its progress is persisted
+// after every item.
+type Runner struct {
+	db       dusk.Store
+	workers  int
+	handlers map[string]HandlerFunc
+
+	mu      sync.Mutex
+	queue   []int64
+	notify  chan struct{}
+	running map[int64]*runningJob
+
+	ctx  context.Context
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+type runningJob struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+func New(db dusk.Store, workers int) *Runner {
+	ctx, stop := context.WithCancel(context.Background())
+	return &Runner{
+		db:       db,
+		workers:  max(workers, 1),
+		handlers: make(map[string]HandlerFunc),
+		notify:   make(chan struct{}, 1),
+		running:  make(map[int64]*runningJob),
+		ctx:      ctx,
+		stop:     stop,
+	}
+}
+
+// Register sets the handler of a kind of job. Handlers must be registered
+// before the runner is started.
+func (r *Runner) Register(kind string, h HandlerFunc) {
+	r.handlers[kind] = h
+}
+
+// Start queues any jobs left unfinished by a previous run and starts the
+// workers.
+func (r *Runner) Start() error {
+	jobs, err := r.db.GetAllJobs()
+	if err != nil {
+		return fmt.Errorf("jobs: failed to resume jobs: %w", err)
+	}
+
+	// oldest first
+	for i := len(jobs) - 1; i >= 0; i-- {
+		if !jobs[i].Finished() {
+			slog.Info("[jobs] Resuming job", slog.Int64("id", jobs[i].Id), slog.String("kind", jobs[i].Kind))
+			r.enqueue(jobs[i].Id)
+		}
+	}
+
+	for range r.workers {
+		r.wg.Add(1)
+		go r.work()
+	}
+	return nil
+}
+
+// Close stops the workers. Running jobs are interrupted and resumed on the
+// next start.
+func (r *Runner) Close() {
+	r.stop()
+	r.wg.Wait()
+}
+
+// Submit creates a job with the given items and queues it.
+func (r *Runner) Submit(kind string, items []dusk.JobItem) (*dusk.Job, error) {
+	if _, ok := r.handlers[kind]; !ok {
+		return nil, ErrUnknownKind
+	}
+
+	job, err := r.db.CreateJob(&dusk.Job{Kind: kind, Items: items})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("[jobs] New job queued",
+		slog.Int64("id", job.Id),
+		slog.String("kind", kind),
+		slog.Int("items", len(items)),
+	)
+	r.enqueue(job.Id)
+	return job, nil
+}
+
+// Cancel stops a queued or running job. Items that were already processed
+// are kept. It returns dusk.ErrNoChange if the job is already finished.
+func (r *Runner) Cancel(id int64) (*dusk.Job, error) {
+	// the job is checked and cancelled under the lock, so it cannot finish
+	// or start running in between
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, err := r.db.GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Finished() {
+		return nil, dusk.ErrNoChange
+	}
+
+	if rj, ok := r.running[id]; ok {
+		rj.cancelled = true
+		rj.cancel()
+		return job, nil
+	}
+	r.queue = slices.DeleteFunc(r.queue, func(q int64) bool { return q == id })
+
+	job.State = dusk.JobCancelled
+	job.DateCompleted = null.TimeFrom(time.Now())
+	return r.db.UpdateJob(id, job)
+}
+
+// Retry queues the failed items of a finished job again. It returns
+// dusk.ErrNoChange if there are no failed items.
+func (r *Runner) Retry(id int64) (*dusk.Job, error) {
+	job, err := r.db.RetryJob(id)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("[jobs] Retrying job", slog.Int64("id", id))
+	r.enqueue(id)
+	return job, nil
+}
+
+func (r *Runner) enqueue(id int64) {
+	r.mu.Lock()
+	r.queue = append(r.queue, id)
+	r.mu.Unlock()
+
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// next pops the next queued job and marks it as running
+func (r *Runner) next() (int64, *runningJob, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.queue) == 0 {
+		return 0, nil, false
+	}
+	id := r.queue[0]
+	r.queue = r.queue[1:]
+
+	ctx, cancel := context.WithCancel(r.ctx)
+	rj := &runningJob{ctx: ctx, cancel: cancel}
+	r.running[id] = rj
+	return id, rj, true
+}
+
+func (r *Runner) work() {
+	defer r.wg.Done()
+
+	for {
+		if r.ctx.Err() != nil {
+			return
+		}
+
+		id, rj, ok := r.next()
+		if !ok {
+			select {
+			case <-r.notify:
+			case <-r.ctx.Done():
+				return
+			}
+			continue
+		}
+
+		// wake up another worker if there are more jobs
+		r.mu.Lock()
+		if len(r.queue) > 0 {
+			select {
+			case r.notify <- struct{}{}:
+			default:
+			}
+		}
+		r.mu.Unlock()
+
+		if err := r.run(id, rj); err != nil {
+			slog.Error("[jobs] Failed to run job", slog.Int64("id", id), slog.Any("err", err))
+		}
+	}
+}
+
+func (r *Runner) run(id int64, rj *runningJob) error {
+	ctx := rj.ctx
+	defer rj.cancel()
+	defer r.done(id)
+
+	job, err := r.db.GetJob(id)
+	if err != nil {
+		return err
+	}
+	if job.Finished() {
+		return nil
+	}
+
+	h, ok := r.handlers[job.Kind]
+	if !ok {
+		job.State = dusk.JobCompleted
+		job.Error = null.StringFrom(fmt.Sprintf("%s: %s", ErrUnknownKind, job.Kind))
+		job.DateCompleted = null.TimeFrom(time.Now())
+		_, err := r.db.UpdateJob(id, job)
+		return err
+	}
+
+	job.State = dusk.JobRunning
+	if !job.DateStarted.Valid {
+		job.DateStarted = null.TimeFrom(time.Now())
+	}
+	if _, err := r.db.UpdateJob(id, job); err != nil {
+		return err
+	}
+
+	for i := range job.Items {
+		item := &job.Items[i]
+		if item.State != dusk.ItemPending {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		err := runItem(ctx, h, item)
+		if errors.Is(err, context.Canceled) {
+			// interrupted items are processed again when the job is resumed
+			break
+		}
+
+		switch {
+		case err == nil:
+			item.State = dusk.ItemDone
+			item.Error = null.String{}
+		case errors.Is(err, dusk.ErrSkipped):
+			item.State = dusk.ItemSkipped
+			item.Error = null.String{}
+		default:
+			slog.Warn("[jobs] Failed to process item",
+				slog.Int64("job", id),
+				slog.String("item", item.Name),
+				slog.Any("err", err),
+			)
+			item.State = dusk.ItemFailed
+			item.Error = null.StringFrom(err.Error())
+		}
+
+		if err := r.db.UpdateJobItem(item.Id, item); err != nil {
+			return err
+		}
+	}
+
+	// the job is finished under the lock, so a concurrent Cancel either
+	// cancels it or sees it finished
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case rj.cancelled:
+		job.State = dusk.JobCancelled
+	case r.ctx.Err() != nil:
+		// shutting down, the job is resumed on the next start
+		return nil
+	default:
+		job.State = dusk.JobCompleted
+	}
+	job.DateCompleted = null.TimeFrom(time.Now())
+
+	job, err = r.db.UpdateJob(id, job)
+	if err != nil {
+		return err
+	}
+	delete(r.running, id)
+
+	slog.Info("[jobs] Job finished",
+		slog.Int64("id", id),
+		slog.Int("done", job.Done),
+		slog.Int("skipped", job.Skipped),
+		slog.Int("failed", job.Failed),
+	)
+	return nil
+}
+
+// done removes a job from the running jobs
+func (r *Runner) done(id int64) {
+	r.mu.Lock()
+	delete(r.running, id)
+	r.mu.Unlock()
+}
+
+// runItem runs the handler and recovers from any panics so that a single bad
+// item does not stop the job
+func runItem(ctx context.Context, h HandlerFunc, item *dusk.JobItem) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return h(ctx, item)
+}
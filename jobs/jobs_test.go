@@ -0,0 +1,333 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kencx/dusk"
+)
+
+// memStore keeps jobs in memory. Only the job methods of dusk.Store are
+// implemented.
+type memStore struct {
+	dusk.Store
+
+	mu     sync.Mutex
+	jobs   map[int64]*dusk.Job
+	nextId int64
+}
+
+func newMemStore() *memStore {
+	return &memStore{jobs: make(map[int64]*dusk.Job)}
+}
+
+func (m *memStore) copyJob(j *dusk.Job) *dusk.Job {
+	c := *j
+	c.Items = append([]dusk.JobItem(nil), j.Items...)
+	c.Total, c.Done, c.Failed, c.Skipped = len(c.Items), 0, 0, 0
+	for _, i := range c.Items {
+		switch i.State {
+		case dusk.ItemDone:
+			c.Done++
+		case dusk.ItemFailed:
+			c.Failed++
+		case dusk.ItemSkipped:
+			c.Skipped++
+		}
+	}
+	return &c
+}
+
+func (m *memStore) GetJob(id int64) (*dusk.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, ok := m.jobs[id]
+	if !ok {
+		return nil, dusk.ErrDoesNotExist
+	}
+	return m.copyJob(j), nil
+}
+
+func (m *memStore) GetAllJobs() ([]dusk.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var jobs []dusk.Job
+	for id := m.nextId; id > 0; id-- {
+		if j, ok := m.jobs[id]; ok {
+			jobs = append(jobs, *m.copyJob(j))
+		}
+	}
+	return jobs, nil
+}
+
+func (m *memStore) CreateJob(j *dusk.Job) (*dusk.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextId++
+	job := &dusk.Job{Id: m.nextId, Kind: j.Kind, State: j.State}
+	for i, item := range j.Items {
+		item.Id = int64(i + 1)
+		item.JobId = job.Id
+		job.Items = append(job.Items, item)
+	}
+	m.jobs[job.Id] = job
+	return m.copyJob(job), nil
+}
+
+func (m *memStore) UpdateJob(id int64, j *dusk.Job) (*dusk.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job := m.jobs[id]
+	job.State, job.Error, job.DateStarted, job.DateCompleted = j.State, j.Error, j.DateStarted, j.DateCompleted
+	return m.copyJob(job), nil
+}
+
+func (m *memStore) UpdateJobItem(id int64, item *dusk.JobItem) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job := m.jobs[item.JobId]
+	for i := range job.Items {
+		if job.Items[i].Id == id {
+			job.Items[i].State, job.Items[i].Error, job.Items[i].BookId = item.State, item.Error, item.BookId
+			return nil
+		}
+	}
+	return dusk.ErrDoesNotExist
+}
+
+func (m *memStore) RetryJob(id int64) (*dusk.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job := m.jobs[id]
+	if !job.Finished() || m.copyJob(job).Failed == 0 {
+		return nil, dusk.ErrNoChange
+	}
+	for i := range job.Items {
+		if job.Items[i].State == dusk.ItemFailed {
+			job.Items[i].State = dusk.ItemPending
+		}
+	}
+	job.State = dusk.JobQueued
+	return m.copyJob(job), nil
+}
+
+// wait until the job is finished
+func waitFor(t *testing.T, db *memStore, id int64) *dusk.Job {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := db.GetJob(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Finished() {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %d did not finish", id)
+	return nil
+}
+
+func items(names ...string) []dusk.JobItem {
+	var result []dusk.JobItem
+	for _, n := range names {
+		result = append(result, dusk.JobItem{Name: n, Payload: []byte(n)})
+	}
+	return result
+}
+
+func TestRunner(t *testing.T) {
+	db := newMemStore()
+	r := New(db, 2)
+
+	var mu sync.Mutex
+	fail := map[string]bool{"bad": true}
+	r.Register("test", func(ctx context.Context, item *dusk.JobItem) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch string(item.Payload) {
+		case "skip":
+			return dusk.ErrSkipped
+		case "panic":
+			panic("oops")
+		}
+		if fail[item.Name] {
+			return errors.New("failed")
+		}
+		item.BookId = item.Id
+		return nil
+	})
+
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if _, err := r.Submit("foo", nil); !errors.Is(err, ErrUnknownKind) {
+		t.Errorf("got err %v, want %v", err, ErrUnknownKind)
+	}
+
+	job, err := r.Submit("test", items("a", "bad", "skip", "panic", "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job = waitFor(t, db, job.Id)
+	if job.State != dusk.JobCompleted {
+		t.Errorf("got state %v, want completed", job.State)
+	}
+	if job.Done != 2 || job.Failed != 2 || job.Skipped != 1 {
+		t.Errorf("got done %d, failed %d, skipped %d", job.Done, job.Failed, job.Skipped)
+	}
+	if job.Items[0].BookId != 1 {
+		t.Errorf("got book id %d, want 1", job.Items[0].BookId)
+	}
+	if job.Items[3].Error.String != "panic: oops" {
+		t.Errorf("got error %q", job.Items[3].Error.String)
+	}
+
+	// only failed items are retried
+	mu.Lock()
+	fail["bad"] = false
+	mu.Unlock()
+
+	if _, err := r.Retry(job.Id); err != nil {
+		t.Fatal(err)
+	}
+	job = waitFor(t, db, job.Id)
+	if job.Done != 3 || job.Failed != 1 || job.Skipped != 1 {
+		t.Errorf("got done %d, failed %d, skipped %d", job.Done, job.Failed, job.Skipped)
+	}
+
+	if _, err := r.Cancel(job.Id); !errors.Is(err, dusk.ErrNoChange) {
+		t.Errorf("got err %v, want %v", err, dusk.ErrNoChange)
+	}
+}
+
+func TestRunnerCancel(t *testing.T) {
+	db := newMemStore()
+	r := New(db, 1)
+
+	started := make(chan struct{})
+	r.Register("test", func(ctx context.Context, item *dusk.JobItem) error {
+		if item.Name == "block" {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	running, err := r.Submit("test", items("a", "block", "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	queued, err := r.Submit("test", items("c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	<-started
+
+	// the single worker is busy so the second job is still queued
+	job, err := r.Cancel(queued.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.State != dusk.JobCancelled || job.Done != 0 {
+		t.Errorf("got state %v with %d done, want cancelled with 0 done", job.State, job.Done)
+	}
+
+	if _, err := r.Cancel(running.Id); err != nil {
+		t.Fatal(err)
+	}
+	job = waitFor(t, db, running.Id)
+	if job.State != dusk.JobCancelled {
+		t.Errorf("got state %v, want cancelled", job.State)
+	}
+
+	// the interrupted item is left pending
+	if job.Done != 1 || job.Items[1].State != dusk.ItemPending || job.Items[2].State != dusk.ItemPending {
+		t.Errorf("got done %d, items %v", job.Done, job.Items)
+	}
+}
+
+func TestRunnerCancelRace(t *testing.T) {
+	db := newMemStore()
+	r := New(db, 4)
+	r.Register("test", func(ctx context.Context, item *dusk.JobItem) error {
+		return nil
+	})
+
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// a job that is cancelled must not be completed afterwards, and a job
+	// that cannot be cancelled must already be finished
+	for range 100 {
+		job, err := r.Submit("test", items("a"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = r.Cancel(job.Id)
+		got := waitFor(t, db, job.Id)
+
+		switch {
+		case err == nil && got.State != dusk.JobCancelled:
+			t.Fatalf("job %d: got state %v after cancel, want cancelled", job.Id, got.State)
+		case errors.Is(err, dusk.ErrNoChange) && got.State != dusk.JobCompleted:
+			t.Fatalf("job %d: got state %v, want completed", job.Id, got.State)
+		case err != nil && !errors.Is(err, dusk.ErrNoChange):
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRunnerResume(t *testing.T) {
+	db := newMemStore()
+
+	// a job interrupted by a shutdown
+	job, _ := db.CreateJob(&dusk.Job{Kind: "test", State: dusk.JobRunning, Items: items("a", "b")})
+	job.Items[0].State = dusk.ItemDone
+	db.UpdateJobItem(job.Items[0].Id, &job.Items[0])
+
+	var count int
+	r := New(db, 1)
+	r.Register("test", func(ctx context.Context, item *dusk.JobItem) error {
+		count++
+		return nil
+	})
+
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	job = waitFor(t, db, job.Id)
+	if job.State != dusk.JobCompleted || job.Done != 2 {
+		t.Errorf("got state %v with %d done, want completed with 2 done", job.State, job.Done)
+	}
+	if count != 1 {
+		t.Errorf("got %d items processed, want 1", count)
+	}
+}
@@ -1,24 +1,34 @@
 package mock
 
-import "github.com/kencx/dusk"
-
+import (
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/filters"
+	"github.com/kencx/dusk/page"
+)
+
+// Store mocks the methods of dusk.Store used in handler tests. Calling any
+// other method panics.
 type Store struct {
+	dusk.Store
+
 	GetBookFn     func(id int64) (*dusk.Book, error)
-	GetAllBooksFn func() (dusk.Books, error)
+	GetAllBooksFn func(f *filters.Book) (*page.Page[dusk.Book], error)
 	CreateBookFn  func(b *dusk.Book) (*dusk.Book, error)
 	UpdateBookFn  func(id int64, b *dusk.Book) (*dusk.Book, error)
 	DeleteBookFn  func(id int64) error
 
+	GetDuplicateCandidatesFn func(isbns, authors []string) (dusk.Books, error)
+
 	GetAuthorFn             func(id int64) (*dusk.Author, error)
-	GetAllAuthorsFn         func() (dusk.Authors, error)
-	GetAllBooksFromAuthorFn func(id int64) (dusk.Books, error)
+	GetAllAuthorsFn         func(f *filters.Search) (*page.Page[dusk.Author], error)
+	GetAllBooksFromAuthorFn func(id int64, f *filters.Book) (*page.Page[dusk.Book], error)
 	CreateAuthorFn          func(a *dusk.Author) (*dusk.Author, error)
 	UpdateAuthorFn          func(id int64, a *dusk.Author) (*dusk.Author, error)
 	DeleteAuthorFn          func(id int64) error
 
 	GetTagFn             func(id int64) (*dusk.Tag, error)
-	GetAllTagsFn         func() (dusk.Tags, error)
-	GetAllBooksFromTagFn func(id int64) (dusk.Books, error)
+	GetAllTagsFn         func(f *filters.Search) (*page.Page[dusk.Tag], error)
+	GetAllBooksFromTagFn func(id int64, f *filters.Book) (*page.Page[dusk.Book], error)
 	CreateTagFn          func(a *dusk.Tag) (*dusk.Tag, error)
 	UpdateTagFn          func(id int64, a *dusk.Tag) (*dusk.Tag, error)
 	DeleteTagFn          func(id int64) error
@@ -28,8 +38,8 @@ func (s *Store) GetBook(id int64) (*dusk.Book, error) {
 	return s.GetBookFn(id)
 }
 
-func (s *Store) GetAllBooks() (dusk.Books, error) {
-	return s.GetAllBooksFn()
+func (s *Store) GetAllBooks(f *filters.Book) (*page.Page[dusk.Book], error) {
+	return s.GetAllBooksFn(f)
 }
 
 func (s *Store) CreateBook(b *dusk.Book) (*dusk.Book, error) {
@@ -44,16 +54,20 @@ func (s *Store) DeleteBook(id int64) error {
 	return s.DeleteBookFn(id)
 }
 
+func (s *Store) GetDuplicateCandidates(isbns, authors []string) (dusk.Books, error) {
+	return s.GetDuplicateCandidatesFn(isbns, authors)
+}
+
 func (s *Store) GetAuthor(id int64) (*dusk.Author, error) {
 	return s.GetAuthorFn(id)
 }
 
-func (s *Store) GetAllAuthors() (dusk.Authors, error) {
-	return s.GetAllAuthorsFn()
+func (s *Store) GetAllAuthors(f *filters.Search) (*page.Page[dusk.Author], error) {
+	return s.GetAllAuthorsFn(f)
 }
 
-func (s *Store) GetAllBooksFromAuthor(id int64) (dusk.Books, error) {
-	return s.GetAllBooksFromAuthorFn(id)
+func (s *Store) GetAllBooksFromAuthor(id int64, f *filters.Book) (*page.Page[dusk.Book], error) {
+	return s.GetAllBooksFromAuthorFn(id, f)
 }
 
 func (s *Store) CreateAuthor(b *dusk.Author) (*dusk.Author, error) {
@@ -72,12 +86,12 @@ func (s *Store) GetTag(id int64) (*dusk.Tag, error) {
 	return s.GetTagFn(id)
 }
 
-func (s *Store) GetAllTags() (dusk.Tags, error) {
-	return s.GetAllTagsFn()
+func (s *Store) GetAllTags(f *filters.Search) (*page.Page[dusk.Tag], error) {
+	return s.GetAllTagsFn(f)
 }
 
-func (s *Store) GetAllBooksFromTag(id int64) (dusk.Books, error) {
-	return s.GetAllBooksFromTagFn(id)
+func (s *Store) GetAllBooksFromTag(id int64, f *filters.Book) (*page.Page[dusk.Book], error) {
+	return s.GetAllBooksFromTagFn(id, f)
 }
 
 func (s *Store) CreateTag(b *dusk.Tag) (*dusk.Tag, error) {
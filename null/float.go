@@ -0,0 +1,76 @@
+package null
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+type Float struct {
+	sql.NullFloat64
+}
+
+func NewFloat(f float64, valid bool) Float {
+	return Float{
+		NullFloat64: sql.NullFloat64{
+			Float64: f,
+			Valid:   valid,
+		},
+	}
+}
+
+func FloatFrom(f float64) Float {
+	if f == 0 {
+		return NewFloat(f, false)
+	}
+	return NewFloat(f, true)
+}
+
+func FloatFromPtr(f *float64) Float {
+	if f == nil {
+		return NewFloat(0, false)
+	}
+	return NewFloat(*f, true)
+}
+
+func (n Float) ValueOrZero() float64 {
+	if !n.Valid {
+		return 0
+	}
+	return n.Float64
+}
+
+func (n *Float) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == 'n' {
+		n.Valid = false
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &n.Float64); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	n.Valid = true
+	return nil
+}
+
+func (n Float) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Float64)
+}
+
+// String formats the float with the minimum number of digits
+// necessary, or returns an empty string if invalid.
+func (n Float) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return strconv.FormatFloat(n.Float64, 'f', -1, 64)
+}
+
+func (n Float) Equal(b Float) bool {
+	return (n.Valid == b.Valid && n.ValueOrZero() == b.ValueOrZero())
+}
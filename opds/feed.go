@@ -0,0 +1,123 @@
+package opds
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const (
+	navigationType  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	acquisitionType = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	openSearchType  = "application/opensearchdescription+xml"
+
+	relStart       = "start"
+	relSelf        = "self"
+	relUp          = "up"
+	relNext        = "next"
+	relPrevious    = "previous"
+	relSearch      = "search"
+	relSubsection  = "subsection"
+	relAcquisition = "http://opds-spec.org/acquisition"
+	relImage       = "http://opds-spec.org/image"
+	relThumbnail   = "http://opds-spec.org/image/thumbnail"
+	relSortNew     = "http://opds-spec.org/sort/new"
+)
+
+// Feed is an OPDS 1.2 catalog feed. A navigation feed links to other feeds
+// while an acquisition feed lists books.
+type Feed struct {
+	XMLName         xml.Name `xml:"feed"`
+	Xmlns           string   `xml:"xmlns,attr"`
+	XmlnsDc         string   `xml:"xmlns:dc,attr"`
+	XmlnsOpds       string   `xml:"xmlns:opds,attr"`
+	XmlnsOpenSearch string   `xml:"xmlns:opensearch,attr"`
+
+	Id      string  `xml:"id"`
+	Title   string  `xml:"title"`
+	Updated string  `xml:"updated"`
+	Author  *Author `xml:"author,omitempty"`
+	Links   []Link  `xml:"link"`
+
+	TotalResults int `xml:"opensearch:totalResults,omitempty"`
+	ItemsPerPage int `xml:"opensearch:itemsPerPage,omitempty"`
+	StartIndex   int `xml:"opensearch:startIndex,omitempty"`
+
+	Entries []Entry `xml:"entry"`
+}
+
+type Entry struct {
+	Id        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Updated   string     `xml:"updated"`
+	Authors   []Author   `xml:"author,omitempty"`
+	Published string     `xml:"published,omitempty"`
+	Issued    string     `xml:"dc:issued,omitempty"`
+	Publisher string     `xml:"dc:publisher,omitempty"`
+	Isbn      []string   `xml:"dc:identifier,omitempty"`
+	Category  []Category `xml:"category,omitempty"`
+	Content   *Content   `xml:"content,omitempty"`
+	Links     []Link     `xml:"link"`
+}
+
+type Author struct {
+	Name string `xml:"name"`
+	Uri  string `xml:"uri,omitempty"`
+}
+
+type Link struct {
+	Rel   string `xml:"rel,attr,omitempty"`
+	Href  string `xml:"href,attr"`
+	Type  string `xml:"type,attr,omitempty"`
+	Title string `xml:"title,attr,omitempty"`
+}
+
+type Category struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr,omitempty"`
+}
+
+type Content struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// OpenSearch is an OpenSearch 1.1 description document that points clients
+// to the search feed.
+type OpenSearch struct {
+	XMLName     xml.Name `xml:"OpenSearchDescription"`
+	Xmlns       string   `xml:"xmlns,attr"`
+	ShortName   string   `xml:"ShortName"`
+	Description string   `xml:"Description"`
+	Url         struct {
+		Type     string `xml:"type,attr"`
+		Template string `xml:"template,attr"`
+	} `xml:"Url"`
+}
+
+func newFeed(id, title string, links ...Link) *Feed {
+	return &Feed{
+		Xmlns:           "http://www.w3.org/2005/Atom",
+		XmlnsDc:         "http://purl.org/dc/terms/",
+		XmlnsOpds:       "http://opds-spec.org/2010/catalog",
+		XmlnsOpenSearch: "http://a9.com/-/spec/opensearch/1.1/",
+		Id:              "urn:dusk:" + id,
+		Title:           title,
+		Updated:         timestamp(time.Now()),
+		Author:          &Author{Name: "dusk"},
+		Links:           links,
+	}
+}
+
+// linkType returns the type of the feed from its self link.
+func (f *Feed) linkType() string {
+	for _, l := range f.Links {
+		if l.Rel == relSelf {
+			return l.Type
+		}
+	}
+	return acquisitionType
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,527 @@
+package opds
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/file"
+	"github.com/kencx/dusk/filters"
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/http/response"
+	"github.com/kencx/dusk/page"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// root of the catalog and the static files router, relative to the server
+	root      = "/opds"
+	filesRoot = "/files"
+
+	defaultLimit = 30
+)
+
+type Handler struct {
+	db dusk.Store
+}
+
+// statuses are the read status navigation feeds and their search query
+var statuses = []struct {
+	slug, title, query string
+}{
+	{"reading", "Currently reading", "status:reading"},
+	{"unread", "Unread", "status:unread"},
+	{"read", "Read", "status:read"},
+}
+
+func Router(db dusk.Store) chi.Router {
+	s := Handler{db}
+	opds := chi.NewRouter()
+
+	opds.Use(response.NoCache)
+
+	opds.Get("/", s.root)
+	opds.Get("/opensearch.xml", s.openSearch)
+	opds.Get("/search", s.search)
+	opds.Get("/books", s.allBooks)
+	opds.Get("/recent", s.recentBooks)
+	opds.Get("/status", s.statusList)
+	opds.Get("/status/{status:[a-z]+}", s.statusBooks)
+
+	opds.Get("/authors", s.authorList)
+	opds.Get("/authors/{id:[0-9]+}", s.authorBooks)
+	opds.Get("/tags", s.tagList)
+	opds.Get("/tags/{id:[0-9]+}", s.tagBooks)
+	opds.Get("/series", s.seriesList)
+	opds.Get("/series/{id:[0-9]+}", s.seriesBooks)
+	opds.Get("/smart", s.smartCollectionList)
+	opds.Get("/smart/{id:[0-9]+}", s.smartCollectionBooks)
+	return opds
+}
+
+func (s *Handler) root(rw http.ResponseWriter, r *http.Request) {
+	feed := newNavigationFeed("root", "dusk", root)
+	feed.Entries = []Entry{
+		navigationEntry("recent", "Recently added", "Books in order of date added", root+"/recent", acquisitionType, relSortNew),
+		navigationEntry("books", "All books", "All books by title", root+"/books", acquisitionType, relSubsection),
+		navigationEntry("authors", "Authors", "Books by author", root+"/authors", navigationType, relSubsection),
+		navigationEntry("tags", "Tags", "Books by tag", root+"/tags", navigationType, relSubsection),
+		navigationEntry("series", "Series", "Books by series", root+"/series", navigationType, relSubsection),
+		navigationEntry("status", "Read status", "Books by read status", root+"/status", navigationType, relSubsection),
+		navigationEntry("smart", "Smart collections", "Books by saved query", root+"/smart", navigationType, relSubsection),
+	}
+	writeFeed(rw, r, feed, navigationType)
+}
+
+func (s *Handler) openSearch(rw http.ResponseWriter, r *http.Request) {
+	desc := OpenSearch{
+		Xmlns:       "http://a9.com/-/spec/opensearch/1.1/",
+		ShortName:   "dusk",
+		Description: "Search the dusk library",
+	}
+	desc.Url.Type = acquisitionType
+	desc.Url.Template = root + "/search?q={searchTerms}"
+
+	res, err := xmlBody(desc)
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.Custom(rw, r, http.StatusOK, map[string]string{"Content-Type": openSearchType}, res)
+}
+
+func (s *Handler) search(rw http.ResponseWriter, r *http.Request) {
+	q := request.QueryString(r.URL.Query(), "q", "")
+	f := bookFilters(r, "title", "ASC")
+	f.Search.Search = q
+
+	s.bookFeed(rw, r, "search", fmt.Sprintf("Search: %s", q), func() (*page.Page[dusk.Book], error) {
+		return s.db.GetAllBooks(f)
+	}, f)
+}
+
+func (s *Handler) allBooks(rw http.ResponseWriter, r *http.Request) {
+	f := bookFilters(r, "title", "ASC")
+	s.bookFeed(rw, r, "books", "All books", func() (*page.Page[dusk.Book], error) {
+		return s.db.GetAllBooks(f)
+	}, f)
+}
+
+func (s *Handler) recentBooks(rw http.ResponseWriter, r *http.Request) {
+	f := bookFilters(r, "dateAdded", "DESC")
+	s.bookFeed(rw, r, "recent", "Recently added", func() (*page.Page[dusk.Book], error) {
+		return s.db.GetAllBooks(f)
+	}, f)
+}
+
+func (s *Handler) statusList(rw http.ResponseWriter, r *http.Request) {
+	feed := newNavigationFeed("status", "Read status", root+"/status")
+	for _, st := range statuses {
+		feed.Entries = append(feed.Entries, navigationEntry(
+			"status:"+st.slug, st.title, st.title+" books",
+			root+"/status/"+st.slug, acquisitionType, relSubsection,
+		))
+	}
+	writeFeed(rw, r, feed, navigationType)
+}
+
+func (s *Handler) statusBooks(rw http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "status")
+
+	for _, st := range statuses {
+		if st.slug != slug {
+			continue
+		}
+
+		f := bookFilters(r, "title", "ASC")
+		f.Search.Search = st.query
+		s.bookFeed(rw, r, "status:"+slug, st.title, func() (*page.Page[dusk.Book], error) {
+			return s.db.GetAllBooks(f)
+		}, f)
+		return
+	}
+	response.NotFound(rw, r, dusk.ErrDoesNotExist)
+}
+
+func (s *Handler) authorList(rw http.ResponseWriter, r *http.Request) {
+	f := searchFilters(r, filters.AuthorSafeList())
+	if errMap := f.Valid(); len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	authors, err := s.db.GetAllAuthors(f)
+	if err != nil && !errors.Is(err, dusk.ErrNoRows) {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	feed := newNavigationFeed("authors", "Authors", root+"/authors")
+	if authors != nil {
+		for _, a := range authors.Items {
+			href := fmt.Sprintf("%s/authors/%d", root, a.Id)
+			feed.Entries = append(feed.Entries, navigationEntry(
+				fmt.Sprintf("author:%d", a.Id), a.Name, "Books by "+a.Name, href, acquisitionType, relSubsection,
+			))
+		}
+		paginate(feed, root+"/authors", authors)
+	}
+	writeFeed(rw, r, feed, navigationType)
+}
+
+func (s *Handler) authorBooks(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	a, err := s.db.GetAuthor(id)
+	if err != nil {
+		notFoundOrError(rw, r, err)
+		return
+	}
+
+	f := bookFilters(r, "title", "ASC")
+	s.bookFeed(rw, r, fmt.Sprintf("author:%d", id), a.Name, func() (*page.Page[dusk.Book], error) {
+		return s.db.GetAllBooksFromAuthor(id, f)
+	}, f)
+}
+
+func (s *Handler) tagList(rw http.ResponseWriter, r *http.Request) {
+	f := searchFilters(r, filters.TagSafeList())
+	if errMap := f.Valid(); len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	tags, err := s.db.GetAllTags(f)
+	if err != nil && !errors.Is(err, dusk.ErrNoRows) {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	feed := newNavigationFeed("tags", "Tags", root+"/tags")
+	if tags != nil {
+		for _, t := range tags.Items {
+			href := fmt.Sprintf("%s/tags/%d", root, t.Id)
+			feed.Entries = append(feed.Entries, navigationEntry(
+				fmt.Sprintf("tag:%d", t.Id), t.Name, "Books tagged "+t.Name, href, acquisitionType, relSubsection,
+			))
+		}
+		paginate(feed, root+"/tags", tags)
+	}
+	writeFeed(rw, r, feed, navigationType)
+}
+
+func (s *Handler) tagBooks(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	t, err := s.db.GetTag(id)
+	if err != nil {
+		notFoundOrError(rw, r, err)
+		return
+	}
+
+	f := bookFilters(r, "title", "ASC")
+	s.bookFeed(rw, r, fmt.Sprintf("tag:%d", id), t.Name, func() (*page.Page[dusk.Book], error) {
+		return s.db.GetAllBooksFromTag(id, f)
+	}, f)
+}
+
+func (s *Handler) seriesList(rw http.ResponseWriter, r *http.Request) {
+	f := searchFilters(r, filters.SeriesSafeList())
+	if errMap := f.Valid(); len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	series, err := s.db.GetAllSeries(f)
+	if err != nil && !errors.Is(err, dusk.ErrNoRows) {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	feed := newNavigationFeed("series", "Series", root+"/series")
+	if series != nil {
+		for _, se := range series.Items {
+			href := fmt.Sprintf("%s/series/%d", root, se.Id)
+			feed.Entries = append(feed.Entries, navigationEntry(
+				fmt.Sprintf("series:%d", se.Id), se.Name, "Books in "+se.Name, href, acquisitionType, relSubsection,
+			))
+		}
+		paginate(feed, root+"/series", series)
+	}
+	writeFeed(rw, r, feed, navigationType)
+}
+
+func (s *Handler) seriesBooks(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	se, err := s.db.GetSeries(id)
+	if err != nil {
+		notFoundOrError(rw, r, err)
+		return
+	}
+
+	// books in a series are returned in reading order
+	f := bookFilters(r, "position", "ASC")
+	f.SortSafeList = filters.SeriesBookSafeList()
+	s.bookFeed(rw, r, fmt.Sprintf("series:%d", id), se.Name, func() (*page.Page[dusk.Book], error) {
+		return s.db.GetAllBooksFromSeries(id, f)
+	}, f)
+}
+
+func (s *Handler) smartCollectionList(rw http.ResponseWriter, r *http.Request) {
+	collections, err := s.db.GetAllSmartCollections()
+	if err != nil && !errors.Is(err, dusk.ErrNoRows) {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	feed := newNavigationFeed("smart", "Smart collections", root+"/smart")
+	for _, c := range collections {
+		href := fmt.Sprintf("%s/smart/%d", root, c.Id)
+		feed.Entries = append(feed.Entries, navigationEntry(
+			fmt.Sprintf("smart:%d", c.Id), c.Name, c.Query, href, acquisitionType, relSubsection,
+		))
+	}
+	writeFeed(rw, r, feed, navigationType)
+}
+
+func (s *Handler) smartCollectionBooks(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	c, err := s.db.GetSmartCollection(id)
+	if err != nil {
+		notFoundOrError(rw, r, err)
+		return
+	}
+
+	f := bookFilters(r, "title", "ASC")
+	s.bookFeed(rw, r, fmt.Sprintf("smart:%d", id), c.Name, func() (*page.Page[dusk.Book], error) {
+		return s.db.GetAllBooksFromSmartCollection(id, f)
+	}, f)
+}
+
+// bookFeed writes an acquisition feed of the books returned by get.
+func (s *Handler) bookFeed(
+	rw http.ResponseWriter,
+	r *http.Request,
+	id, title string,
+	get func() (*page.Page[dusk.Book], error),
+	f *filters.Book,
+) {
+	if errMap := f.Valid(); len(errMap) > 0 {
+		response.ValidationError(rw, r, errMap)
+		return
+	}
+
+	books, err := get()
+	if err != nil && !errors.Is(err, dusk.ErrNoRows) {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+
+	feed := newFeed(id, title,
+		Link{Rel: relStart, Href: root, Type: navigationType},
+		Link{Rel: relUp, Href: root, Type: navigationType},
+		Link{Rel: relSelf, Href: r.URL.RequestURI(), Type: acquisitionType},
+		Link{Rel: relSearch, Href: root + "/opensearch.xml", Type: openSearchType},
+	)
+	if books != nil {
+		for _, b := range books.Items {
+			feed.Entries = append(feed.Entries, bookEntry(b))
+		}
+		paginate(feed, r.URL.Path, books)
+	}
+	writeFeed(rw, r, feed, acquisitionType)
+}
+
+func newNavigationFeed(id, title, self string) *Feed {
+	return newFeed(id, title,
+		Link{Rel: relStart, Href: root, Type: navigationType},
+		Link{Rel: relSelf, Href: self, Type: navigationType},
+		Link{Rel: relSearch, Href: root + "/opensearch.xml", Type: openSearchType},
+	)
+}
+
+func navigationEntry(id, title, content, href, linkType, rel string) Entry {
+	return Entry{
+		Id:      "urn:dusk:" + id,
+		Title:   title,
+		Updated: feedUpdated(),
+		Content: &Content{Type: "text", Body: content},
+		Links:   []Link{{Rel: rel, Href: href, Type: linkType}},
+	}
+}
+
+// bookEntry returns an acquisition entry with a link for each format of
+// the book and its cover, if any.
+func bookEntry(b dusk.Book) Entry {
+	e := Entry{
+		Id:        fmt.Sprintf("urn:dusk:book:%d", b.Id),
+		Title:     b.Title,
+		Updated:   feedUpdated(),
+		Publisher: b.Publisher.ValueOrZero(),
+	}
+	if b.Subtitle.Valid && b.Subtitle.String != "" {
+		e.Title = fmt.Sprintf("%s: %s", b.Title, b.Subtitle.String)
+	}
+	if b.DateAdded.Valid {
+		e.Updated = timestamp(b.DateAdded.Time)
+		e.Published = timestamp(b.DateAdded.Time)
+	}
+	if b.DatePublished.Valid {
+		e.Issued = b.DatePublished.Time.Format("2006-01-02")
+	}
+
+	for _, a := range b.Author {
+		if a != "" {
+			e.Authors = append(e.Authors, Author{Name: a})
+		}
+	}
+	for _, t := range b.Tag {
+		if t != "" {
+			e.Category = append(e.Category, Category{Term: t, Label: t})
+		}
+	}
+	for _, isbn := range append(b.Isbn13, b.Isbn10...) {
+		if isbn != "" {
+			e.Isbn = append(e.Isbn, "urn:isbn:"+isbn)
+		}
+	}
+
+	if b.Description.Valid && b.Description.String != "" {
+		e.Content = &Content{Type: "html", Body: b.Description.String}
+	}
+
+	for _, f := range b.Formats {
+		if f == "" {
+			continue
+		}
+		e.Links = append(e.Links, Link{
+			Rel:   relAcquisition,
+			Href:  fileUrl(f),
+			Type:  file.MimeType(f),
+			Title: strings.TrimPrefix(path.Ext(f), "."),
+		})
+	}
+
+	if b.Cover.Valid && b.Cover.String != "" {
+		cover := fileUrl(b.Cover.String)
+		coverType := file.MimeType(b.Cover.String)
+		e.Links = append(e.Links,
+			Link{Rel: relImage, Href: cover, Type: coverType},
+			Link{Rel: relThumbnail, Href: cover, Type: coverType},
+		)
+	}
+	return e
+}
+
+// fileUrl returns the url of a file that is served by the static files router.
+// Remote covers are returned as is.
+func fileUrl(p string) string {
+	if strings.HasPrefix(p, "http://") || strings.HasPrefix(p, "https://") {
+		return p
+	}
+	u := url.URL{Path: path.Join(filesRoot, p)}
+	return u.EscapedPath()
+}
+
+// paginate adds the next and previous links and OpenSearch result counts of a
+// page to the feed.
+func paginate[T any](feed *Feed, base string, p *page.Page[T]) {
+	if p.Info == nil {
+		return
+	}
+
+	feed.TotalResults = p.TotalCount
+	feed.ItemsPerPage = p.Limit
+	feed.StartIndex = p.FirstRowNo
+
+	links := []struct{ rel, qs string }{
+		{relNext, p.Next()},
+		{relPrevious, p.Previous()},
+	}
+	for _, l := range links {
+		if l.qs == "" {
+			continue
+		}
+		feed.Links = append(feed.Links, Link{Rel: l.rel, Href: base + "?" + l.qs, Type: feed.linkType()})
+	}
+}
+
+func bookFilters(r *http.Request, sort, direction string) *filters.Book {
+	qs := r.URL.Query()
+
+	return &filters.Book{
+		Search: filters.Search{
+			Base: filters.Base{
+				AfterId:       request.QueryInt(qs, page.After, 0),
+				Limit:         request.QueryInt(qs, page.Limit, defaultLimit),
+				Sort:          request.QueryString(qs, page.Sort, sort),
+				SortDirection: request.QueryString(qs, page.SortDirection, direction),
+				SortSafeList:  filters.BookSafeList(),
+			},
+		},
+	}
+}
+
+func searchFilters(r *http.Request, safeList []string) *filters.Search {
+	qs := r.URL.Query()
+
+	return &filters.Search{
+		Base: filters.Base{
+			AfterId:       request.QueryInt(qs, page.After, 0),
+			Limit:         request.QueryInt(qs, page.Limit, defaultLimit),
+			Sort:          "name",
+			SortDirection: "ASC",
+			SortSafeList:  safeList,
+		},
+	}
+}
+
+func notFoundOrError(rw http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, dusk.ErrDoesNotExist) {
+		response.NotFound(rw, r, err)
+		return
+	}
+	response.InternalServerError(rw, r, err)
+}
+
+func writeFeed(rw http.ResponseWriter, r *http.Request, feed *Feed, feedType string) {
+	res, err := xmlBody(feed)
+	if err != nil {
+		response.InternalServerError(rw, r, err)
+		return
+	}
+	response.Custom(rw, r, http.StatusOK, map[string]string{"Content-Type": feedType}, res)
+}
+
+func feedUpdated() string {
+	return timestamp(time.Now())
+}
+
+func xmlBody(v any) ([]byte, error) {
+	res, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), res...), nil
+}
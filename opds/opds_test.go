@@ -0,0 +1,106 @@
+package opds
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
+)
+
+func TestBookEntry(t *testing.T) {
+	tests := []struct {
+		name string
+		book dusk.Book
+		want []Link
+	}{{
+		name: "no files",
+		book: dusk.Book{Id: 1, Title: "Foo"},
+		want: nil,
+	}, {
+		name: "formats",
+		book: dusk.Book{
+			Id:      1,
+			Title:   "Foo",
+			Formats: []string{"foo/foo.epub", "foo/foo.PDF", "foo/foo.azw", "foo/foo.xyzzy"},
+		},
+		want: []Link{
+			{Rel: relAcquisition, Href: "/files/foo/foo.epub", Type: "application/epub+zip", Title: "epub"},
+			{Rel: relAcquisition, Href: "/files/foo/foo.PDF", Type: "application/pdf", Title: "PDF"},
+			{Rel: relAcquisition, Href: "/files/foo/foo.azw", Type: "application/vnd.amazon.ebook", Title: "azw"},
+			{Rel: relAcquisition, Href: "/files/foo/foo.xyzzy", Type: "application/octet-stream", Title: "xyzzy"},
+		},
+	}, {
+		name: "escaped path",
+		book: dusk.Book{
+			Id:      1,
+			Title:   "Foo Bar",
+			Formats: []string{"foo bar/foo bar.mobi"},
+			Cover:   null.StringFrom("foo bar/cover.jpg"),
+		},
+		want: []Link{
+			{Rel: relAcquisition, Href: "/files/foo%20bar/foo%20bar.mobi", Type: "application/x-mobipocket-ebook", Title: "mobi"},
+			{Rel: relImage, Href: "/files/foo%20bar/cover.jpg", Type: "image/jpeg"},
+			{Rel: relThumbnail, Href: "/files/foo%20bar/cover.jpg", Type: "image/jpeg"},
+		},
+	}, {
+		name: "remote cover",
+		book: dusk.Book{
+			Id:    1,
+			Title: "Foo",
+			Cover: null.StringFrom("https://example.com/cover.png"),
+		},
+		want: []Link{
+			{Rel: relImage, Href: "https://example.com/cover.png", Type: "image/png"},
+			{Rel: relThumbnail, Href: "https://example.com/cover.png", Type: "image/png"},
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := bookEntry(tt.book)
+			if !reflect.DeepEqual(got.Links, tt.want) {
+				t.Errorf("got %v, want %v", got.Links, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeedMarshal(t *testing.T) {
+	feed := newFeed("test", "Test")
+	feed.Entries = []Entry{bookEntry(dusk.Book{
+		Id:          1,
+		Title:       "Foo",
+		Subtitle:    null.StringFrom("Bar"),
+		Author:      []string{"John Doe"},
+		Isbn13:      []string{"9780316129084"},
+		Description: null.StringFrom("<p>foo & bar</p>"),
+	})}
+
+	res, err := xmlBody(feed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom"`,
+		`<title>Foo: Bar</title>`,
+		`<name>John Doe</name>`,
+		`<dc:identifier>urn:isbn:9780316129084</dc:identifier>`,
+		`<content type="html">&lt;p&gt;foo &amp; bar&lt;/p&gt;</content>`,
+	} {
+		if !strings.Contains(string(res), want) {
+			t.Errorf("feed does not contain %q", want)
+		}
+	}
+
+	var got Feed
+	if err := xml.Unmarshal(res, &got); err != nil {
+		t.Fatalf("failed to unmarshal feed: %v", err)
+	}
+	if len(got.Entries) != 1 {
+		t.Errorf("got %d entries, want 1", len(got.Entries))
+	}
+}
@@ -36,11 +36,26 @@ type Page[T any] struct {
 	Items []T
 }
 
+// Metadata is the JSON representation of a page's Info. Next and Previous
+// are the encoded query parameters of the adjacent pages and are empty when
+// there is no such page.
+type Metadata struct {
+	Limit      int    `json:"limit"`
+	TotalCount int    `json:"total_count"`
+	FirstRowNo int    `json:"first_row_no"`
+	LastRowNo  int    `json:"last_row_no"`
+	Next       string `json:"next,omitempty"`
+	Previous   string `json:"previous,omitempty"`
+}
+
 func New[T any](total, first, last int, filters *filters.Base, items []T) *Page[T] {
 	qp := make(url.Values)
 	qp.Add(After, strconv.Itoa(filters.AfterId))
 	qp.Add(Limit, strconv.Itoa(filters.Limit))
 	qp.Add(Sort, filters.Sort)
+	if filters.SortDirection != "" {
+		qp.Add(SortDirection, filters.SortDirection)
+	}
 
 	return &Page[T]{
 		Info: &Info{
@@ -78,6 +93,21 @@ func (p *Page[T]) Empty() bool {
 	return len(p.Items) == 0
 }
 
+func (p *Page[T]) Metadata() *Metadata {
+	if p.Info == nil {
+		return &Metadata{}
+	}
+
+	return &Metadata{
+		Limit:      p.Limit,
+		TotalCount: p.TotalCount,
+		FirstRowNo: p.FirstRowNo,
+		LastRowNo:  p.LastRowNo,
+		Next:       p.Next(),
+		Previous:   p.Previous(),
+	}
+}
+
 func (p *Page[T]) Next() string {
 	if p.IsLast() {
 		return ""
@@ -5,12 +5,12 @@ import (
 	"net/url"
 	"testing"
 
-	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/filters"
 	"github.com/matryer/is"
 )
 
 var (
-	testPage = Page[dusk.Book]{
+	testPage = Page[string]{
 		Info: &Info{
 			Limit:      5,
 			TotalCount: 12,
@@ -25,15 +25,15 @@ var (
 
 func TestNew(t *testing.T) {
 	is := is.New(t)
-	filters := &dusk.Filters{
+	filters := &filters.Base{
 		AfterId: 5,
 		Limit:   5,
 		Sort:    "title",
 	}
-	items := []dusk.Book{}
+	items := []string{}
 
 	got := New(30, 1, 5, filters, items)
-	want := &Page[dusk.Book]{
+	want := &Page[string]{
 		Info: &Info{
 			Limit:      5,
 			TotalCount: 30,
@@ -0,0 +1,84 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Node is a node in the abstract syntax tree of a library query.
+type Node interface {
+	String() string
+}
+
+// And matches books that match all of its nodes.
+type And struct {
+	Nodes []Node
+}
+
+// Or matches books that match any of its nodes.
+type Or struct {
+	Nodes []Node
+}
+
+// Not matches books that do not match its node.
+type Not struct {
+	Node Node
+}
+
+// Term matches books with a field that satisfies the comparison, eg. rating>=8.
+type Term struct {
+	Field Field
+	Op    Op
+	Value string
+}
+
+// Text matches books with the given free text in their title, authors or tags.
+type Text struct {
+	Value string
+}
+
+type Op string
+
+const (
+	Eq  Op = ":"
+	Gt  Op = ">"
+	Gte Op = ">="
+	Lt  Op = "<"
+	Lte Op = "<="
+)
+
+func (n And) String() string {
+	return joinNodes(n.Nodes, " ")
+}
+
+func (n Or) String() string {
+	return fmt.Sprintf("(%s)", joinNodes(n.Nodes, " OR "))
+}
+
+func (n Not) String() string {
+	return "-" + n.Node.String()
+}
+
+func (n Term) String() string {
+	return fmt.Sprintf("%s%s%s", n.Field, n.Op, quote(n.Value))
+}
+
+func (n Text) String() string {
+	return quote(n.Value)
+}
+
+func joinNodes(nodes []Node, sep string) string {
+	var s []string
+	for _, n := range nodes {
+		s = append(s, n.String())
+	}
+	return strings.Join(s, sep)
+}
+
+func quote(s string) string {
+	if strings.ContainsAny(s, " \t\"()") || s == "" {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,237 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Field string
+
+const (
+	Title     Field = "title"
+	Author    Field = "author"
+	Tag       Field = "tag"
+	Series    Field = "series"
+	Publisher Field = "publisher"
+	Isbn      Field = "isbn"
+	Format    Field = "format"
+
+	Identifier Field = "identifier"
+
+	Status    Field = "status"
+	Ownership Field = "ownership"
+	Has       Field = "has"
+
+	Rating   Field = "rating"
+	Pages    Field = "pages"
+	Progress Field = "progress"
+
+	Added     Field = "added"
+	Started   Field = "started"
+	Completed Field = "read"
+	Published Field = "published"
+)
+
+type kind int
+
+const (
+	textKind kind = iota
+	statusKind
+	ownershipKind
+	hasKind
+	identifierKind
+	numberKind
+	dateKind
+)
+
+var fields = map[Field]kind{
+	Title:      textKind,
+	Author:     textKind,
+	Tag:        textKind,
+	Series:     textKind,
+	Publisher:  textKind,
+	Isbn:       textKind,
+	Format:     textKind,
+	Status:     statusKind,
+	Ownership:  ownershipKind,
+	Has:        hasKind,
+	Identifier: identifierKind,
+	Rating:     numberKind,
+	Pages:      numberKind,
+	Progress:   numberKind,
+	Added:      dateKind,
+	Started:    dateKind,
+	Completed:  dateKind,
+	Published:  dateKind,
+}
+
+var aliases = map[string]Field{
+	"completed": Completed,
+	"finished":  Completed,
+	"genre":     Tag,
+	"id":        Identifier,
+	"owned":     Ownership,
+}
+
+// StatusValues lists the valid values of the status field in the order of
+// dusk.ReadStatus.
+var StatusValues = []string{"unread", "reading", "read"}
+
+// OwnershipValues lists the valid values of the ownership field in the order
+// of dusk.Ownership.
+var OwnershipValues = []string{"owned", "wishlist", "borrowed", "library"}
+
+// HasValues lists the valid values of the has field, eg. has:cover or
+// -has:format for books without any format.
+var HasValues = []string{"cover", "format", "isbn", "series", "description"}
+
+func lookupField(name string) (Field, bool) {
+	name = strings.ToLower(name)
+	if f, ok := aliases[name]; ok {
+		return f, true
+	}
+	f := Field(name)
+	_, ok := fields[f]
+	return f, ok
+}
+
+// validate checks that the operator and value of a term are valid for its field
+// and returns the normalized value.
+func validate(field Field, op Op, value string) (string, error) {
+	if value == "" {
+		return "", fmt.Errorf("missing value for field %q", field)
+	}
+
+	switch fields[field] {
+	case textKind:
+		if op != Eq {
+			return "", fmt.Errorf("field %q does not support operator %q", field, op)
+		}
+		switch field {
+		case Tag:
+			value = strings.ToLower(value)
+		case Format:
+			value = strings.TrimPrefix(strings.ToLower(value), ".")
+		}
+
+	case statusKind:
+		if op != Eq {
+			return "", fmt.Errorf("field %q does not support operator %q", field, op)
+		}
+		value = strings.ToLower(value)
+		if StatusIndex(value) == -1 {
+			return "", fmt.Errorf("invalid status %q: must be one of %s", value, strings.Join(StatusValues, ", "))
+		}
+
+	case ownershipKind:
+		if op != Eq {
+			return "", fmt.Errorf("field %q does not support operator %q", field, op)
+		}
+		value = strings.ToLower(value)
+		if OwnershipIndex(value) == -1 {
+			return "", fmt.Errorf("invalid ownership %q: must be one of %s", value, strings.Join(OwnershipValues, ", "))
+		}
+
+	case hasKind:
+		if op != Eq {
+			return "", fmt.Errorf("field %q does not support operator %q", field, op)
+		}
+		value = strings.ToLower(value)
+		if !slices.Contains(HasValues, value) {
+			return "", fmt.Errorf("invalid value %q for field %q: must be one of %s", value, field, strings.Join(HasValues, ", "))
+		}
+
+	case identifierKind:
+		if op != Eq {
+			return "", fmt.Errorf("field %q does not support operator %q", field, op)
+		}
+		scheme, id, ok := strings.Cut(value, ":")
+		if !ok || scheme == "" || id == "" {
+			return "", fmt.Errorf("invalid identifier %q: must be scheme:value", value)
+		}
+		value = strings.ToLower(scheme) + ":" + id
+
+	case numberKind:
+		if _, err := strconv.Atoi(value); err != nil {
+			return "", fmt.Errorf("invalid number %q for field %q", value, field)
+		}
+
+	case dateKind:
+		if _, _, err := DateRange(value); err != nil {
+			return "", fmt.Errorf("invalid date %q for field %q: must be YYYY, YYYY-MM or YYYY-MM-DD", value, field)
+		}
+	}
+	return value, nil
+}
+
+// NewTerm returns a term of the field with its value validated and
+// normalized.
+func NewTerm(field Field, op Op, value string) (Term, error) {
+	value, err := validate(field, op, value)
+	if err != nil {
+		return Term{}, err
+	}
+	return Term{Field: field, Op: op, Value: value}, nil
+}
+
+// IsText reports whether the field is matched against text.
+func (f Field) IsText() bool {
+	return fields[f] == textKind
+}
+
+// IsNumber reports whether the field is compared as an integer.
+func (f Field) IsNumber() bool {
+	return fields[f] == numberKind
+}
+
+// IsDate reports whether the field is compared as a date.
+func (f Field) IsDate() bool {
+	return fields[f] == dateKind
+}
+
+// StatusIndex returns the index of the status value in StatusValues or -1 if
+// it is invalid.
+func StatusIndex(value string) int {
+	for i, s := range StatusValues {
+		if s == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// OwnershipIndex returns the index of the ownership value in OwnershipValues
+// or -1 if it is invalid.
+func OwnershipIndex(value string) int {
+	return slices.Index(OwnershipValues, value)
+}
+
+// DateRange returns the half-open range [from, to) covered by a date of the
+// format YYYY, YYYY-MM or YYYY-MM-DD.
+func DateRange(value string) (time.Time, time.Time, error) {
+	layouts := []struct {
+		layout string
+		years  int
+		months int
+		days   int
+	}{
+		{"2006", 1, 0, 0},
+		{"2006-01", 0, 1, 0},
+		{"2006-01-02", 0, 0, 1},
+	}
+
+	for _, l := range layouts {
+		if len(value) != len(l.layout) {
+			continue
+		}
+		from, err := time.Parse(l.layout, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		return from, from.AddDate(l.years, l.months, l.days), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q", value)
+}
@@ -0,0 +1,330 @@
+// Package query parses library search queries such as
+//
+//	author:"le guin" tag:scifi status:reading rating>=8 pages<300 added:2024 -tag:dnf -has:cover
+//
+// into an abstract syntax tree. Terms are implicitly joined with AND, can be
+// joined with OR, grouped with parentheses and negated with a leading "-".
+// Words that are not part of a field term are matched as free text.
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidQuery = errors.New("invalid query")
+
+type tokenType int
+
+const (
+	tokEOF tokenType = iota
+	tokWord
+	tokString
+	tokField
+	tokOp
+	tokLParen
+	tokRParen
+	tokNot
+	tokAnd
+	tokOr
+)
+
+type token struct {
+	typ   tokenType
+	value string
+	pos   int
+}
+
+// Parse parses a query string into its syntax tree. An empty query returns a
+// nil node.
+func Parse(input string) (Node, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidQuery, err)
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().typ == tokEOF {
+		return nil, nil
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidQuery, err)
+	}
+	if t := p.peek(); t.typ != tokEOF {
+		return nil, fmt.Errorf("%w: unexpected %q at position %d", ErrInvalidQuery, t.value, t.pos)
+	}
+	return node, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.typ != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (Node, error) {
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{node}
+	for p.peek().typ == tokOr {
+		p.next()
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return Or{Nodes: nodes}, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	var nodes []Node
+
+	for {
+		t := p.peek()
+		if t.typ == tokEOF || t.typ == tokRParen || t.typ == tokOr {
+			break
+		}
+		if t.typ == tokAnd {
+			p.next()
+			continue
+		}
+
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		// adjacent words are matched as a single phrase
+		if text, ok := node.(Text); ok && len(nodes) > 0 {
+			if prev, ok := nodes[len(nodes)-1].(Text); ok {
+				nodes[len(nodes)-1] = Text{Value: prev.Value + " " + text.Value}
+				continue
+			}
+		}
+		nodes = append(nodes, node)
+	}
+
+	switch len(nodes) {
+	case 0:
+		t := p.peek()
+		if t.typ == tokEOF {
+			return nil, errors.New("unexpected end of query")
+		}
+		return nil, fmt.Errorf("unexpected %q at position %d", t.value, t.pos)
+	case 1:
+		return nodes[0], nil
+	default:
+		return And{Nodes: nodes}, nil
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.peek().typ == tokNot {
+		p.next()
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Node: node}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.next()
+
+	switch t.typ {
+	case tokLParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if r := p.next(); r.typ != tokRParen {
+			return nil, fmt.Errorf("missing closing parenthesis for position %d", t.pos)
+		}
+		return node, nil
+
+	case tokField:
+		field, ok := lookupField(t.value)
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q at position %d, quote the value to search for it as text", t.value, t.pos)
+		}
+
+		op := p.next()
+		v := p.next()
+		if v.typ != tokWord && v.typ != tokString {
+			return nil, fmt.Errorf("missing value for field %q at position %d", t.value, t.pos)
+		}
+
+		value, err := validate(field, Op(op.value), v.value)
+		if err != nil {
+			return nil, err
+		}
+		return Term{Field: field, Op: Op(op.value), Value: value}, nil
+
+	case tokWord, tokString:
+		return Text{Value: t.value}, nil
+
+	case tokEOF:
+		return nil, errors.New("unexpected end of query")
+
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", t.value, t.pos)
+	}
+}
+
+func lex(input string) ([]token, error) {
+	var (
+		tokens []token
+		runes  = []rune(input)
+		i      = 0
+	)
+
+	for i < len(runes) {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+
+		case r == '"':
+			s, n, err := lexString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{tokString, s, i})
+			i = n
+
+		// leading "-" negates the following term
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && runes[i+1] != ')':
+			tokens = append(tokens, token{tokNot, "-", i})
+			i++
+
+		default:
+			start := i
+			for i < len(runes) && unicode.IsLetter(runes[i]) {
+				i++
+			}
+
+			// field term
+			if op := lexOp(runes, i); i > start && op != "" {
+				tokens = append(tokens, token{tokField, string(runes[start:i]), start})
+				tokens = append(tokens, token{tokOp, string(normalizeOp(op)), i})
+				i += len(op)
+
+				if i < len(runes) && runes[i] == '"' {
+					s, n, err := lexString(runes, i)
+					if err != nil {
+						return nil, err
+					}
+					tokens = append(tokens, token{tokString, s, i})
+					i = n
+				} else {
+					valueStart := i
+					i = lexWord(runes, i)
+					tokens = append(tokens, token{tokWord, string(runes[valueStart:i]), valueStart})
+				}
+				continue
+			}
+
+			i = lexWord(runes, i)
+			word := string(runes[start:i])
+			switch word {
+			case "OR":
+				tokens = append(tokens, token{tokOr, word, start})
+			case "AND":
+				tokens = append(tokens, token{tokAnd, word, start})
+			case "NOT":
+				tokens = append(tokens, token{tokNot, word, start})
+			default:
+				tokens = append(tokens, token{tokWord, word, start})
+			}
+		}
+	}
+
+	tokens = append(tokens, token{tokEOF, "", len(runes)})
+	return tokens, nil
+}
+
+// lexString reads a double-quoted string starting at runes[start], returning the
+// unquoted string and the index after the closing quote.
+func lexString(runes []rune, start int) (string, int, error) {
+	var sb strings.Builder
+
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				sb.WriteRune(runes[i])
+			}
+		case '"':
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteRune(runes[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quote at position %d", start)
+}
+
+func lexWord(runes []rune, i int) int {
+	for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' && runes[i] != '"' {
+		i++
+	}
+	return i
+}
+
+func lexOp(runes []rune, i int) string {
+	if i >= len(runes) {
+		return ""
+	}
+
+	switch runes[i] {
+	case ':', '=':
+		return string(runes[i])
+	case '>', '<':
+		if i+1 < len(runes) && runes[i+1] == '=' {
+			return string(runes[i : i+2])
+		}
+		return string(runes[i])
+	}
+	return ""
+}
+
+func normalizeOp(op string) Op {
+	if op == "=" {
+		return Eq
+	}
+	return Op(op)
+}
@@ -0,0 +1,182 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Node
+	}{{
+		name:  "empty",
+		input: "  ",
+		want:  nil,
+	}, {
+		name:  "text",
+		input: "dune",
+		want:  Text{"dune"},
+	}, {
+		name:  "adjacent words",
+		input: "left hand of darkness",
+		want:  Text{"left hand of darkness"},
+	}, {
+		name:  "quoted text",
+		input: `"status:read"`,
+		want:  Text{"status:read"},
+	}, {
+		name:  "term",
+		input: "tag:SciFi",
+		want:  Term{Tag, Eq, "scifi"},
+	}, {
+		name:  "quoted term",
+		input: `author:"le guin"`,
+		want:  Term{Author, Eq, "le guin"},
+	}, {
+		name:  "ownership",
+		input: "ownership:Wishlist",
+		want:  Term{Ownership, Eq, "wishlist"},
+	}, {
+		name:  "ownership alias",
+		input: "-owned:library",
+		want:  Not{Term{Ownership, Eq, "library"}},
+	}, {
+		name:  "alias",
+		input: "finished:2024",
+		want:  Term{Completed, Eq, "2024"},
+	}, {
+		name:  "comparisons",
+		input: "rating>=8 pages<300",
+		want:  And{[]Node{Term{Rating, Gte, "8"}, Term{Pages, Lt, "300"}}},
+	}, {
+		name:  "equals",
+		input: "rating=8",
+		want:  Term{Rating, Eq, "8"},
+	}, {
+		name:  "negation",
+		input: "-tag:dnf NOT status:read",
+		want:  And{[]Node{Not{Term{Tag, Eq, "dnf"}}, Not{Term{Status, Eq, "read"}}}},
+	}, {
+		name:  "or",
+		input: "tag:fantasy OR tag:scifi AND status:unread",
+		want: Or{[]Node{
+			Term{Tag, Eq, "fantasy"},
+			And{[]Node{Term{Tag, Eq, "scifi"}, Term{Status, Eq, "unread"}}},
+		}},
+	}, {
+		name:  "group",
+		input: "(tag:fantasy OR tag:scifi) rating>7",
+		want: And{[]Node{
+			Or{[]Node{Term{Tag, Eq, "fantasy"}, Term{Tag, Eq, "scifi"}}},
+			Term{Rating, Gt, "7"},
+		}},
+	}, {
+		name:  "text and terms",
+		input: `the expanse author:corey -"leviathan wakes"`,
+		want: And{[]Node{
+			Text{"the expanse"},
+			Term{Author, Eq, "corey"},
+			Not{Text{"leviathan wakes"}},
+		}},
+	}, {
+		name:  "has",
+		input: "has:Format -has:cover format:.EPUB",
+		want: And{[]Node{
+			Term{Has, Eq, "format"},
+			Not{Term{Has, Eq, "cover"}},
+			Term{Format, Eq, "epub"},
+		}},
+	}, {
+		name:  "identifier",
+		input: "identifier:Goodreads:12345 id:asin:B00ABC",
+		want: And{[]Node{
+			Term{Identifier, Eq, "goodreads:12345"},
+			Term{Identifier, Eq, "asin:B00ABC"},
+		}},
+	}, {
+		name:  "hyphenated word",
+		input: "sci-fi",
+		want:  Text{"sci-fi"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+
+			got, err := Parse(tt.input)
+			is.NoErr(err)
+			is.Equal(got, tt.want)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"unknown field", "foo:bar"},
+		{"missing value", "title:"},
+		{"unterminated quote", `author:"le guin`},
+		{"unclosed group", "(tag:scifi OR tag:fantasy"},
+		{"unopened group", "tag:scifi)"},
+		{"dangling or", "tag:scifi OR"},
+		{"invalid number", "rating>=high"},
+		{"invalid date", "added:yesterday"},
+		{"invalid status", "status:abandoned"},
+		{"invalid ownership", "ownership:stolen"},
+		{"invalid has", "has:rating"},
+		{"invalid identifier", "identifier:12345"},
+		{"text comparison", "title>dune"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+
+			_, err := Parse(tt.input)
+			is.True(errors.Is(err, ErrInvalidQuery))
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	is := is.New(t)
+
+	input := `(tag:fantasy OR tag:scifi) author:"le guin" -status:read rating>=8 "left hand"`
+	node, err := Parse(input)
+	is.NoErr(err)
+
+	// the string representation parses to the same tree
+	got, err := Parse(node.String())
+	is.NoErr(err)
+	is.Equal(got, node)
+}
+
+func TestDateRange(t *testing.T) {
+	tests := []struct {
+		input string
+		from  string
+		to    string
+	}{
+		{"2024", "2024-01-01", "2025-01-01"},
+		{"2024-02", "2024-02-01", "2024-03-01"},
+		{"2024-12-31", "2024-12-31", "2025-01-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			is := is.New(t)
+
+			from, to, err := DateRange(tt.input)
+			is.NoErr(err)
+			is.Equal(from.Format(time.DateOnly), tt.from)
+			is.Equal(to.Format(time.DateOnly), tt.to)
+		})
+	}
+}
@@ -10,6 +10,9 @@ import (
 type Series struct {
 	Id   int64  `json:"id"`
 	Name string `json:"name" db:"name"`
+
+	// books in reading order
+	Books []Book `json:"books,omitempty"`
 }
 
 func (a Series) Slugify() string {
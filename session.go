@@ -0,0 +1,105 @@
+package dusk
+
+import (
+	"time"
+
+	"github.com/kencx/dusk/null"
+	"github.com/kencx/dusk/validator"
+)
+
+type SessionState int
+
+const (
+	SessionReading SessionState = iota
+	SessionFinished
+	SessionAbandoned
+)
+
+// ReadingSession is a single read of a book. A book that is re-read has
+// multiple sessions.
+type ReadingSession struct {
+	Id            int64        `json:"id" db:"id"`
+	BookId        int64        `json:"book_id" db:"bookId"`
+	State         SessionState `json:"state" db:"state"`
+	DateStarted   null.Time    `json:"date_started" db:"dateStarted"`
+	DateCompleted null.Time    `json:"date_completed" db:"dateCompleted"`
+	Notes         null.String  `json:"notes,omitempty" db:"notes"`
+
+	// progress snapshots in chronological order
+	Progress []ReadingProgress `json:"progress,omitempty"`
+}
+
+// ReadingProgress is a snapshot of the progress of a reading session.
+type ReadingProgress struct {
+	Id        int64     `json:"id" db:"id"`
+	SessionId int64     `json:"session_id" db:"sessionId"`
+	Progress  int       `json:"progress" db:"progress"`
+	Timestamp time.Time `json:"timestamp" db:"timestamp"`
+}
+
+func (s ReadingSession) Valid() validator.ErrMap {
+	errMap := validator.New()
+
+	errMap.Check(s.State >= SessionReading && s.State <= SessionAbandoned, "state", "invalid state: must be reading, finished or abandoned")
+	errMap.Check(s.State == SessionFinished || !s.DateCompleted.Valid, "dateCompleted", "only finished sessions can be completed")
+	if s.DateStarted.Valid && s.DateCompleted.Valid {
+		errMap.Check(!s.DateCompleted.Time.Before(s.DateStarted.Time), "dateCompleted", "must be after date started")
+	}
+	for _, p := range s.Progress {
+		for k, v := range p.Valid() {
+			errMap.Add(k, v)
+		}
+	}
+	return errMap
+}
+
+func (p ReadingProgress) Valid() validator.ErrMap {
+	errMap := validator.New()
+
+	errMap.Check(p.Progress >= 0, "progress", "must be >= 0")
+	errMap.Check(p.Progress <= 100, "progress", "must be <= 100")
+	return errMap
+}
+
+// LatestProgress returns the last progress snapshot of the session or 0 if
+// there is none.
+func (s ReadingSession) LatestProgress() int {
+	if len(s.Progress) == 0 {
+		return 0
+	}
+	return s.Progress[len(s.Progress)-1].Progress
+}
+
+// StatusFromSessions derives the read status of a book from its reading
+// sessions in chronological order. An abandoned re-read does not undo a
+// previous finished read.
+func StatusFromSessions(sessions []ReadingSession) ReadStatus {
+	if len(sessions) == 0 {
+		return Unread
+	}
+
+	switch sessions[len(sessions)-1].State {
+	case SessionReading:
+		return Reading
+	case SessionFinished:
+		return Read
+	}
+
+	for _, s := range sessions {
+		if s.State == SessionFinished {
+			return Read
+		}
+	}
+	return Unread
+}
+
+// ReadCount returns the number of finished sessions.
+func ReadCount(sessions []ReadingSession) int {
+	var count int
+	for _, s := range sessions {
+		if s.State == SessionFinished {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,51 @@
+package dusk
+
+import (
+	"testing"
+)
+
+func TestStatusFromSessions(t *testing.T) {
+	tests := []struct {
+		name     string
+		sessions []ReadingSession
+		want     ReadStatus
+	}{{
+		name:     "no sessions",
+		sessions: nil,
+		want:     Unread,
+	}, {
+		name:     "reading",
+		sessions: []ReadingSession{{State: SessionReading}},
+		want:     Reading,
+	}, {
+		name:     "finished",
+		sessions: []ReadingSession{{State: SessionFinished}},
+		want:     Read,
+	}, {
+		name:     "abandoned",
+		sessions: []ReadingSession{{State: SessionAbandoned}},
+		want:     Unread,
+	}, {
+		name: "rereading",
+		sessions: []ReadingSession{
+			{State: SessionFinished},
+			{State: SessionReading},
+		},
+		want: Reading,
+	}, {
+		name: "abandoned reread",
+		sessions: []ReadingSession{
+			{State: SessionFinished},
+			{State: SessionAbandoned},
+		},
+		want: Read,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatusFromSessions(tt.sessions); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package dusk
+
+import (
+	"time"
+)
+
+// Stats summarises the books read in a year, or of all time if Year is 0. A book
+// that is re-read is counted once per finished reading session.
+type Stats struct {
+	Year          int     `json:"year,omitempty"`
+	BooksRead     int     `json:"books_read"`
+	PagesRead     int     `json:"pages_read"`
+	AverageRating float64 `json:"average_rating"`
+
+	// books and pages read per month of the year, or per year of all time
+	Periods []PeriodCount `json:"periods"`
+
+	TimeToFinish []Count `json:"time_to_finish"`
+	TopAuthors   []Count `json:"top_authors"`
+	TopTags      []Count `json:"top_tags"`
+	Decades      []Count `json:"publication_decades"`
+
+	// reading streak in days, independent of the year
+	Streak Streak `json:"streak"`
+
+	// years with finished books, in descending order
+	Years []int `json:"years"`
+}
+
+type PeriodCount struct {
+	Period string `json:"period" db:"period"`
+	Books  int    `json:"books" db:"books"`
+	Pages  int    `json:"pages" db:"pages"`
+}
+
+type Count struct {
+	Name  string `json:"name" db:"name"`
+	Count int    `json:"count" db:"count"`
+}
+
+type Streak struct {
+	Current int `json:"current"`
+	Longest int `json:"longest"`
+}
+
+var timeToFinishBuckets = []struct {
+	name string
+	max  int
+}{
+	{"under a week", 7},
+	{"1-2 weeks", 14},
+	{"2-4 weeks", 28},
+	{"1-3 months", 90},
+	{"over 3 months", -1},
+}
+
+// TimeToFinish groups the number of days taken to finish books into buckets.
+// Every bucket is returned, even if it is empty.
+func TimeToFinish(days []int) []Count {
+	result := make([]Count, len(timeToFinishBuckets))
+	for i, b := range timeToFinishBuckets {
+		result[i].Name = b.name
+	}
+
+	for _, d := range days {
+		for i, b := range timeToFinishBuckets {
+			if b.max == -1 || d < b.max {
+				result[i].Count++
+				break
+			}
+		}
+	}
+	return result
+}
+
+// ReadingStreak returns the current and longest number of consecutive days with
+// reading activity. The current streak is kept until the end of the day after
+// the last activity.
+func ReadingStreak(days []time.Time, today time.Time) Streak {
+	var streak Streak
+
+	seen := make(map[time.Time]bool)
+	for _, d := range days {
+		seen[truncateDay(d)] = true
+	}
+
+	for d := range seen {
+		// count forward from the first day of each streak
+		if seen[d.AddDate(0, 0, -1)] {
+			continue
+		}
+
+		var run int
+		for seen[d.AddDate(0, 0, run)] {
+			run++
+		}
+		streak.Longest = max(streak.Longest, run)
+	}
+
+	d := truncateDay(today)
+	if !seen[d] {
+		d = d.AddDate(0, 0, -1)
+	}
+	for seen[d] {
+		streak.Current++
+		d = d.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,71 @@
+package dusk
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTimeToFinish(t *testing.T) {
+	got := TimeToFinish([]int{0, 6, 7, 13, 20, 45, 89, 90, 400})
+	want := []Count{
+		{"under a week", 2},
+		{"1-2 weeks", 2},
+		{"2-4 weeks", 1},
+		{"1-3 months", 2},
+		{"over 3 months", 2},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestReadingStreak(t *testing.T) {
+	today := time.Date(2024, 3, 10, 18, 0, 0, 0, time.UTC)
+	day := func(offset int) time.Time {
+		return today.AddDate(0, 0, offset)
+	}
+
+	tests := []struct {
+		name string
+		days []time.Time
+		want Streak
+	}{{
+		name: "no activity",
+		days: nil,
+		want: Streak{0, 0},
+	}, {
+		name: "today",
+		days: []time.Time{day(-2), day(-1), day(0)},
+		want: Streak{3, 3},
+	}, {
+		name: "until yesterday",
+		days: []time.Time{day(-2), day(-1)},
+		want: Streak{2, 2},
+	}, {
+		name: "broken streak",
+		days: []time.Time{day(-10), day(-9), day(-8), day(-7), day(-3)},
+		want: Streak{0, 4},
+	}, {
+		name: "multiple activities a day",
+		days: []time.Time{day(-1), day(-1).Add(time.Hour), day(0)},
+		want: Streak{2, 2},
+	}, {
+		name: "across months",
+		days: []time.Time{
+			time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		want: Streak{0, 3},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReadingStreak(tt.days, today); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
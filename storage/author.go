@@ -2,8 +2,10 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 
 	"github.com/kencx/dusk"
@@ -16,18 +18,7 @@ import (
 
 func (s *Store) GetAuthor(id int64) (*dusk.Author, error) {
 	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
-		var author dusk.Author
-		stmt := `SELECT * FROM author WHERE id=$1;`
-
-		err := tx.QueryRowx(stmt, id).StructScan(&author)
-		if err == sql.ErrNoRows {
-			return nil, dusk.ErrDoesNotExist
-		}
-		if err != nil {
-			return nil, fmt.Errorf("[db] failed to retrieve author %d: %w", id, err)
-		}
-
-		return &author, nil
+		return getAuthor(tx, id)
 	})
 
 	if err != nil {
@@ -88,6 +79,9 @@ func (s *Store) GetAllBooksFromAuthor(id int64, f *filters.Book) (*page.Page[dus
 	return i.(*page.Page[dusk.Book]), nil
 }
 
+// CreateAuthor returns the existing author instead if the name matches the
+// name or alias of another author. Any missing profile of the existing author
+// is filled in.
 func (s *Store) CreateAuthor(a *dusk.Author) (*dusk.Author, error) {
 	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
 		id, err := insertAuthor(tx, a.Name)
@@ -95,8 +89,19 @@ func (s *Store) CreateAuthor(a *dusk.Author) (*dusk.Author, error) {
 			return nil, fmt.Errorf("[db] failed to create author: %w", err)
 		}
 
-		a.Id = id
-		return a, nil
+		if a.SortName != "" {
+			stmt := `UPDATE author SET sortName=$1 WHERE id=$2;`
+			if _, err := tx.Exec(stmt, a.SortName, id); err != nil {
+				return nil, fmt.Errorf("[db] failed to update author %d: %w", id, err)
+			}
+		}
+		if err := insertAuthorAliases(tx, id, a.Aliases); err != nil {
+			return nil, fmt.Errorf("[db] failed to create author: %w", err)
+		}
+		if err := fillAuthorProfile(tx, id, a); err != nil {
+			return nil, fmt.Errorf("[db] failed to create author: %w", err)
+		}
+		return getAuthor(tx, id)
 	})
 
 	if err != nil {
@@ -105,12 +110,24 @@ func (s *Store) CreateAuthor(a *dusk.Author) (*dusk.Author, error) {
 	return i.(*dusk.Author), nil
 }
 
+// UpdateAuthor replaces the name, profile and aliases of the author. The sort
+// name is derived from the name if it is not given.
 func (s *Store) UpdateAuthor(id int64, a *dusk.Author) (*dusk.Author, error) {
 	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
-		stmt := `UPDATE author SET name=$1 WHERE id=$2`
-		res, err := tx.Exec(stmt, a.Name, id)
+		sortName := a.SortName
+		if sortName == "" {
+			sortName = dusk.AuthorSortName(a.Name)
+		}
+
+		stmt := `UPDATE author
+			SET name=$1, sortName=$2, bio=$3, birthDate=$4, deathDate=$5, photo=$6
+			WHERE id=$7`
+		res, err := tx.Exec(stmt, a.Name, sortName, a.Bio, a.BirthDate, a.DeathDate, a.Photo, id)
 
 		if err != nil {
+			if isUniqueConstraintErr(err) {
+				return nil, fmt.Errorf("[db] failed to rename author %d: %w", id, dusk.ErrUniqueConstraint)
+			}
 			return nil, fmt.Errorf("[db] failed to update author %d: %w", id, err)
 		}
 		count, err := res.RowsAffected()
@@ -120,7 +137,28 @@ func (s *Store) UpdateAuthor(id int64, a *dusk.Author) (*dusk.Author, error) {
 		if count == 0 {
 			return nil, dusk.ErrNoChange
 		}
-		return a, nil
+
+		stmt = `DELETE FROM author_alias WHERE authorId=$1;`
+		if _, err := tx.Exec(stmt, id); err != nil {
+			return nil, fmt.Errorf("[db] failed to delete aliases of author %d: %w", id, err)
+		}
+		if err := insertAuthorAliases(tx, id, a.Aliases); err != nil {
+			return nil, fmt.Errorf("[db] failed to update author %d: %w", id, err)
+		}
+
+		for _, table := range []string{"author_identifier", "author_link"} {
+			stmt = fmt.Sprintf(`DELETE FROM %s WHERE authorId=$1;`, table)
+			if _, err := tx.Exec(stmt, id); err != nil {
+				return nil, fmt.Errorf("[db] failed to update author %d: %w", id, err)
+			}
+		}
+		if err := insertAuthorIdentifiers(tx, id, a.Identifiers); err != nil {
+			return nil, fmt.Errorf("[db] failed to update author %d: %w", id, err)
+		}
+		if err := insertAuthorLinks(tx, id, a.Links); err != nil {
+			return nil, fmt.Errorf("[db] failed to update author %d: %w", id, err)
+		}
+		return getAuthor(tx, id)
 	})
 
 	if err != nil {
@@ -139,8 +177,8 @@ func (s *Store) DeleteAuthor(id int64) error {
 		stmt := `DELETE FROM author WHERE id=$1;`
 		res, err := tx.Exec(stmt, id)
 		if err != nil {
-			if strings.Contains(err.Error(), "FOREIGN KEY constraint failed") {
-				return nil, fmt.Errorf("[db] failed to delete author %d of existing book: %w", id, err)
+			if isForeignKeyConstraintErr(err) {
+				return nil, fmt.Errorf("[db] failed to delete author %d of existing book: %w", id, dusk.ErrHasBooks)
 			}
 			return nil, fmt.Errorf("[db] failed to delete author %d: %w", id, err)
 		}
@@ -158,8 +196,108 @@ func (s *Store) DeleteAuthor(id int64) error {
 	return err
 }
 
+// MergeAuthors links all books of the author otherId to the author id and
+// deletes the other author. Its name and aliases are kept as aliases of the
+// surviving author, whose missing profile is filled in from the other author.
+func (s *Store) MergeAuthors(id, otherId int64) (*dusk.Author, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		if id == otherId {
+			return nil, fmt.Errorf("[db] failed to merge author %d: cannot merge author with itself", id)
+		}
+		if _, err := getAuthor(tx, id); err != nil {
+			return nil, err
+		}
+		other, err := getAuthor(tx, otherId)
+		if err != nil {
+			return nil, err
+		}
+
+		stmt := `INSERT OR IGNORE INTO book_author_link (book, author)
+			SELECT book, $1 FROM book_author_link WHERE author=$2;`
+		if _, err := tx.Exec(stmt, id, otherId); err != nil {
+			return nil, fmt.Errorf("[db] failed to link books of author %d to author %d: %w", otherId, id, err)
+		}
+
+		stmt = `DELETE FROM book_author_link WHERE author=$1;`
+		if _, err := tx.Exec(stmt, otherId); err != nil {
+			return nil, fmt.Errorf("[db] failed to unlink books from author %d: %w", otherId, err)
+		}
+
+		if err := fillAuthorProfile(tx, id, other); err != nil {
+			return nil, fmt.Errorf("[db] failed to merge author %d: %w", otherId, err)
+		}
+
+		stmt = `UPDATE author_alias SET authorId=$1 WHERE authorId=$2;`
+		if _, err := tx.Exec(stmt, id, otherId); err != nil {
+			return nil, fmt.Errorf("[db] failed to move aliases of author %d to author %d: %w", otherId, id, err)
+		}
+
+		stmt = `DELETE FROM author WHERE id=$1;`
+		if _, err := tx.Exec(stmt, otherId); err != nil {
+			return nil, fmt.Errorf("[db] failed to delete author %d: %w", otherId, err)
+		}
+
+		// the name is only freed after the other author is deleted
+		if err := insertAuthorAliases(tx, id, []string{other.Name}); err != nil {
+			return nil, fmt.Errorf("[db] failed to merge author %d: %w", otherId, err)
+		}
+		return getAuthor(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Author), nil
+}
+
+func getAuthor(tx *sqlx.Tx, id int64) (*dusk.Author, error) {
+	var author dusk.Author
+	stmt := `SELECT * FROM author WHERE id=$1;`
+
+	err := tx.QueryRowx(stmt, id).StructScan(&author)
+	if err == sql.ErrNoRows {
+		return nil, dusk.ErrDoesNotExist
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve author %d: %w", id, err)
+	}
+
+	stmt = `SELECT name FROM author_alias WHERE authorId=$1 ORDER BY name;`
+	if err := tx.Select(&author.Aliases, stmt, id); err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve aliases of author %d: %w", id, err)
+	}
+
+	var identifiers []struct {
+		Source string
+		Value  string
+	}
+	stmt = `SELECT source, value FROM author_identifier WHERE authorId=$1;`
+	if err := tx.Select(&identifiers, stmt, id); err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve identifiers of author %d: %w", id, err)
+	}
+	if len(identifiers) > 0 {
+		author.Identifiers = make(map[string]string)
+		for _, i := range identifiers {
+			author.Identifiers[i.Source] = i.Value
+		}
+	}
+
+	stmt = `SELECT title, url FROM author_link WHERE authorId=$1 ORDER BY id;`
+	if err := tx.Select(&author.Links, stmt, id); err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve links of author %d: %w", id, err)
+	}
+	return &author, nil
+}
+
 func queryAuthors(tx *sqlx.Tx, filters *filters.Search, dest *[]AuthorQueryRow) error {
-	query, params := buildSearchQuery("author", filters)
+	// authors are listed by their sort name
+	if filters != nil && filters.Sort == "name" {
+		f := *filters
+		f.Sort = "sortName"
+		f.SortSafeList = append(slices.Clone(f.SortSafeList), "sortName")
+		filters = &f
+	}
+	query, params := buildSearchQuery("author", "author", filters)
 
 	slog.Info("Running SQL query",
 		slog.String("stmt", util.TrimMultiLine(query)),
@@ -172,37 +310,141 @@ func queryAuthors(tx *sqlx.Tx, filters *filters.Search, dest *[]AuthorQueryRow)
 	return nil
 }
 
-// Insert given author. If author already exists, return its id instead
+// Insert given author. If an author with the same name or alias already exists,
+// return its id instead. Names in "Last, First" form are stored in "First Last"
+// form and matched in both forms.
 func insertAuthor(tx *sqlx.Tx, author string) (int64, error) {
-	stmt := `INSERT OR IGNORE INTO author (name) VALUES ($1);`
-	res, err := tx.Exec(stmt, author)
+	name := dusk.NormaliseAuthorName(author)
+	sortName := dusk.AuthorSortName(author)
+
+	id, err := findAuthor(tx, name, sortName, strings.TrimSpace(author))
+	if err == nil {
+		return id, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return -1, fmt.Errorf("failed to query existing author: %w", err)
+	}
+
+	stmt := `INSERT INTO author (name, sortName) VALUES ($1, $2);`
+	res, err := tx.Exec(stmt, name, sortName)
 	if err != nil {
 		return -1, err
 	}
 
-	n, err := res.RowsAffected()
+	id, err = res.LastInsertId()
+	if err != nil {
+		return -1, fmt.Errorf("failed to query existing author: %w", err)
+	}
+	return id, nil
+}
+
+// findAuthor returns the id of the author with any of the given names,
+// ignoring case. Author names take precedence over aliases.
+func findAuthor(tx *sqlx.Tx, names ...string) (int64, error) {
+	stmt := `SELECT id FROM (
+			SELECT id, 0 AS alias FROM author WHERE name COLLATE NOCASE IN (?)
+			UNION ALL
+			SELECT authorId, 1 AS alias FROM author_alias WHERE name IN (?)
+		) ORDER BY alias LIMIT 1;`
+
+	query, args, err := sqlx.In(stmt, names, names)
 	if err != nil {
 		return -1, err
 	}
 
-	// no rows inserted, query to get existing id
-	if n == 0 {
-		// authors.name is unique
-		var id int64
-		stmt := `SELECT id FROM author WHERE name=$1;`
-		err := tx.Get(&id, stmt, author)
-		if err != nil {
-			return -1, fmt.Errorf("failed to query existing author: %w", err)
+	var id int64
+	if err := tx.Get(&id, tx.Rebind(query), args...); err != nil {
+		return -1, err
+	}
+	return id, nil
+}
+
+// insert the given aliases of an author. Aliases that are the author's own
+// name are ignored. Aliases of other authors are not allowed.
+func insertAuthorAliases(tx *sqlx.Tx, id int64, aliases []string) error {
+	for _, alias := range aliases {
+		alias = strings.Join(strings.Fields(alias), " ")
+		if alias == "" {
+			continue
 		}
-		return id, nil
 
-	} else {
-		id, err := res.LastInsertId()
-		if err != nil {
-			return -1, fmt.Errorf("failed to query existing author: %w", err)
+		var taken bool
+		stmt := `SELECT EXISTS (
+				SELECT 1 FROM author WHERE name=$1 COLLATE NOCASE AND id!=$2
+				UNION ALL
+				SELECT 1 FROM author_alias WHERE name=$1 AND authorId!=$2
+			);`
+		if err := tx.Get(&taken, stmt, alias, id); err != nil {
+			return fmt.Errorf("failed to query alias %q: %w", alias, err)
 		}
-		return id, nil
+		if taken {
+			return fmt.Errorf("alias %q belongs to another author: %w", alias, dusk.ErrUniqueConstraint)
+		}
+
+		stmt = `INSERT OR IGNORE INTO author_alias (name, authorId)
+			SELECT $1, $2 WHERE NOT EXISTS
+				(SELECT 1 FROM author WHERE id=$2 AND name=$1 COLLATE NOCASE);`
+		if _, err := tx.Exec(stmt, alias, id); err != nil {
+			return fmt.Errorf("failed to insert alias %q: %w", alias, err)
+		}
+	}
+	return nil
+}
+
+// fill in the missing bio, dates, photo, identifiers and links of an author
+// from a
+func fillAuthorProfile(tx *sqlx.Tx, id int64, a *dusk.Author) error {
+	stmt := `UPDATE author SET
+			bio=COALESCE(bio, $1),
+			birthDate=COALESCE(birthDate, $2),
+			deathDate=COALESCE(deathDate, $3),
+			photo=COALESCE(photo, $4)
+		WHERE id=$5;`
+	if _, err := tx.Exec(stmt, a.Bio, a.BirthDate, a.DeathDate, a.Photo, id); err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
 	}
+
+	if err := insertAuthorIdentifiers(tx, id, a.Identifiers); err != nil {
+		return err
+	}
+
+	var links []dusk.AuthorLink
+	for _, l := range a.Links {
+		var exists bool
+		stmt := `SELECT EXISTS (SELECT 1 FROM author_link WHERE authorId=$1 AND url=$2);`
+		if err := tx.Get(&exists, stmt, id, l.Url); err != nil {
+			return fmt.Errorf("failed to query link %q: %w", l.Url, err)
+		}
+		if !exists {
+			links = append(links, l)
+		}
+	}
+	return insertAuthorLinks(tx, id, links)
+}
+
+// insert the given identifiers of an author. Existing identifiers of the same
+// source are kept.
+func insertAuthorIdentifiers(tx *sqlx.Tx, id int64, identifiers map[string]string) error {
+	stmt := `INSERT OR IGNORE INTO author_identifier (authorId, source, value) VALUES ($1, $2, $3);`
+	for source, value := range identifiers {
+		if source == "" || value == "" {
+			continue
+		}
+		if _, err := tx.Exec(stmt, id, strings.ToLower(source), value); err != nil {
+			return fmt.Errorf("failed to insert identifier %q: %w", source, err)
+		}
+	}
+	return nil
+}
+
+func insertAuthorLinks(tx *sqlx.Tx, id int64, links []dusk.AuthorLink) error {
+	stmt := `INSERT INTO author_link (authorId, title, url) VALUES ($1, $2, $3);`
+	for _, l := range links {
+		if _, err := tx.Exec(stmt, id, l.Title, l.Url); err != nil {
+			return fmt.Errorf("failed to insert link %q: %w", l.Url, err)
+		}
+	}
+	return nil
 }
 
 // Insert given slice of author names and returns slice of author IDs. If author already
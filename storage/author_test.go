@@ -42,40 +42,41 @@ func TestGetAuthorNotExists(t *testing.T) {
 
 func TestGetAllAuthors(t *testing.T) {
 	is := is.New(t)
-	got, err := ts.GetAllAuthors()
+	got, err := ts.GetAllAuthors(testFilters())
 	is.NoErr(err)
 
 	want := allTestAuthors
-	is.Equal(got, want)
+	is.Equal(len(got.Items), len(want))
+	for i := range want {
+		is.Equal(got.Items[i].Id, want[i].Id)
+		is.Equal(got.Items[i].Name, want[i].Name)
+	}
 }
 
 func TestGetAllAuthorEmpty(t *testing.T) {
 	defer resetDB()
 
 	// delete all data
-	if err := ts.MigrateUp(resetSchemaPath); err != nil {
+	if err := ts.execFile(resetSchemaPath); err != nil {
 		t.Errorf("failed to reset database")
 	}
 
-	got, err := ts.GetAllAuthors()
-	if err == nil {
-		t.Errorf("expected error: ErrNoRows")
-	}
-
-	if err != dusk.ErrNoRows {
+	got, err := ts.GetAllAuthors(testFilters())
+	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
 
-	if got != nil {
-		t.Errorf("got %v, want nil", got)
+	if len(got.Items) != 0 {
+		t.Errorf("got %v, want no items", got.Items)
 	}
 }
 
 func TestGetAllBooksFromAuthor(t *testing.T) {
 	is := is.New(t)
 
-	got, err := ts.GetAllBooksFromAuthor(testAuthor5.Id)
+	result, err := ts.GetAllBooksFromAuthor(testAuthor5.Id, testBookFilters())
 	is.NoErr(err)
+	got := result.Items
 
 	want := dusk.Books{testBook3, testBook4}
 
@@ -95,7 +96,8 @@ func TestCreateAuthor(t *testing.T) {
 
 	got, err := ts.CreateAuthor(want)
 	is.NoErr(err)
-	is.Equal(got, want)
+	is.True(got.Id != 0)
+	is.Equal(got.Name, want.Name)
 }
 
 func TestCreateAuthorDuplicates(t *testing.T) {
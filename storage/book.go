@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/kencx/dusk"
@@ -25,29 +26,14 @@ type BookRow struct {
 	Isbn13String null.String `db:"isbn13_string"`
 	FormatString null.String `db:"format_string"`
 	SeriesString null.String `db:"series_string"`
+
+	// shadows dusk.Book.SeriesPosition
+	SeriesPosition null.Float `db:"series_position"`
 }
 
 func (s *Store) GetBook(id int64) (*dusk.Book, error) {
 	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
-		var dest BookRow
-		stmt := `SELECT * FROM book_view b WHERE b.id=$1;`
-
-		err := tx.QueryRowx(stmt, id).StructScan(&dest)
-		if err == sql.ErrNoRows {
-			return nil, dusk.ErrDoesNotExist
-		}
-		if err != nil {
-			return nil, fmt.Errorf("[db] failed to retrieve book id %d: %w", id, err)
-		}
-
-		// TODO test if author name has comma
-		dest.Author = strings.Split(dest.AuthorString, ",")
-		dest.Tag = dest.TagString.Split(",")
-		dest.Isbn10 = dest.Isbn10String.Split(",")
-		dest.Isbn13 = dest.Isbn13String.Split(",")
-		dest.Formats = dest.FormatString.Split(",")
-		dest.Series = dest.SeriesString
-		return dest.Book, nil
+		return getBook(tx, id)
 	})
 
 	if err != nil {
@@ -101,6 +87,7 @@ func (s *Store) GetAllBooks(f *filters.Book) (*page.Page[dusk.Book], error) {
 
 func (s *Store) CreateBook(b *dusk.Book) (*dusk.Book, error) {
 	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		b.Identifiers = b.Identifiers.Normalize()
 		book, err := insertBook(tx, b)
 		if err != nil {
 			return nil, fmt.Errorf("[db] failed to create book: %w", err)
@@ -142,172 +129,87 @@ func (s *Store) CreateBook(b *dusk.Book) (*dusk.Book, error) {
 				return nil, fmt.Errorf("[db] %w", err)
 			}
 		}
-
-		if len(b.Formats) > 0 {
-			_, err = insertFormats(tx, book.Id, b.Formats)
-			if err != nil {
+		if len(b.Identifiers) > 0 {
+			if err := insertIdentifiers(tx, book.Id, b.Identifiers); err != nil {
 				return nil, fmt.Errorf("[db] %w", err)
 			}
 		}
 
-		if b.Series.Valid && b.Series.Valid {
-			_, err = insertSeries(tx, book.Id, b.Series.ValueOrZero())
+		if len(b.Formats) > 0 {
+			_, err = insertFormats(tx, book.Id, b.Formats)
 			if err != nil {
-				return nil, fmt.Errorf("[db] failed to insert series for book %d: %w", b.Id, err)
+				return nil, fmt.Errorf("[db] %w", err)
 			}
 		}
-		return book, nil
-	})
 
-	if err != nil {
-		return nil, err
-	}
-	return i.(*dusk.Book), nil
-}
-
-func (s *Store) UpdateBook(id int64, b *dusk.Book) (*dusk.Book, error) {
-	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
-		err := updateBook(tx, id, b)
-		if err != nil {
-			return nil, fmt.Errorf("[db] failed to update book %d: %w", id, err)
-		}
-
-		current_authors, err := getAuthorsFromBook(tx, b.Id)
-		if err != nil {
-			return nil, fmt.Errorf("[db] failed to get author from book %d", id)
-		}
-
-		util.Sort(b.Author)
-		if !reflect.DeepEqual(current_authors, b.Author) {
-
-			// Renaming an author should not update the same author row for other books
-			// Always create a new author row, never update the original in this case
-			authorIDs, err := insertAuthors(tx, b.Author)
+		if b.Series.Valid {
+			seriesId, err := insertSeries(tx, b.Series.ValueOrZero())
 			if err != nil {
-				return nil, fmt.Errorf("[db] %w", err)
-			}
-
-			if err := linkBookToAuthors(tx, id, authorIDs); err != nil {
-				return nil, fmt.Errorf("[db] %w", err)
+				return nil, fmt.Errorf("[db] failed to insert series for book %d: %w", book.Id, err)
 			}
-
-			// remove existing links for authors not in new list of ids
-			if err := unlinkBookFromAuthors(tx, id, authorIDs); err != nil {
+			if err := linkBookToSeries(tx, book.Id, seriesId, b.SeriesPosition); err != nil {
 				return nil, fmt.Errorf("[db] %w", err)
 			}
 		}
 
-		current_tags, err := getTagsFromBook(tx, b.Id)
-		if err != nil {
-			return nil, fmt.Errorf("[db] failed to get tag from book %d", id)
-		}
-
-		util.Sort(b.Tag)
-		if !reflect.DeepEqual(current_tags, b.Tag) {
-			if len(b.Tag) <= 0 {
-				if err := unlinkBookFromTags(tx, id, []int64{}); err != nil {
-					return nil, fmt.Errorf("[db] %w", err)
-				}
-			} else {
-				// Renaming a tag should not update the same tag row for other books
-				// Always create a new tag row, never update the original in this case
-				tagIDs, err := insertTags(tx, b.Tag)
-				if err != nil {
-					return nil, fmt.Errorf("[db] %w", err)
-				}
-
-				if err := linkBookToTags(tx, id, tagIDs); err != nil {
-					return nil, fmt.Errorf("[db] %w", err)
-				}
-
-				if err := unlinkBookFromTags(tx, id, tagIDs); err != nil {
-					return nil, fmt.Errorf("[db] %w", err)
-				}
+		if b.Work != nil {
+			if err := updateBookWork(tx, book); err != nil {
+				return nil, err
 			}
 		}
 
-		current_isbn10, err := getIsbn10FromBook(tx, b.Id)
-		if err != nil {
-			return nil, fmt.Errorf("[db] failed to get isbn10 from book %d", b.Id, err)
-		}
-
-		util.Sort(b.Isbn10)
-		if !reflect.DeepEqual(current_isbn10, b.Isbn10) {
-			if _, err = insertIsbn10s(tx, b.Id, b.Isbn10); err != nil {
-				return nil, fmt.Errorf("[db] %w", err)
-			}
-
-			for _, i := range current_isbn10 {
-				if err := deleteIsbn10(tx, i); err != nil {
-					return nil, fmt.Errorf("[db] failed to delete isbn10: %w", err)
-				}
+		if b.Wishlist != nil {
+			if err := updateBookWishlist(tx, book.Id, book); err != nil {
+				return nil, err
 			}
 		}
 
-		current_isbn13, err := getIsbn13FromBook(tx, b.Id)
-		if err != nil {
-			return nil, fmt.Errorf("[db] failed to get isbn10 from book %d", b.Id, err)
-		}
-
-		util.Sort(b.Isbn13)
-		if !reflect.DeepEqual(current_isbn13, b.Isbn13) {
-			if _, err = insertIsbn13s(tx, b.Id, b.Isbn13); err != nil {
-				return nil, fmt.Errorf("[db] %w", err)
+		for i := range b.Copies {
+			if err := insertCopy(tx, book.Id, &b.Copies[i]); err != nil {
+				return nil, fmt.Errorf("[db] failed to insert copy for book %d: %w", book.Id, err)
 			}
+		}
 
-			for _, i := range current_isbn13 {
-				if err := deleteIsbn13(tx, i); err != nil {
-					return nil, fmt.Errorf("[db] failed to delete isbn13: %w", err)
+		// reading history is recorded as is, otherwise it begins from the
+		// book's status
+		if len(b.Sessions) > 0 {
+			for i := range b.Sessions {
+				if err := insertSession(tx, book.Id, &b.Sessions[i]); err != nil {
+					return nil, fmt.Errorf("[db] failed to insert reading session for book %d: %w", book.Id, err)
 				}
 			}
+		} else if err := applyStatusToSessions(tx, book.Id, b); err != nil {
+			return nil, fmt.Errorf("[db] failed to insert reading session for book %d: %w", book.Id, err)
 		}
-
-		current_series, err := getSeriesFromBook(tx, b.Id)
-		if err != nil {
-			if !errors.Is(err, dusk.ErrDoesNotExist) {
-				return nil, fmt.Errorf("[db] failed to get series from book %d: %w", b.Id, err)
-			}
+		if err := syncBookWithSessions(tx, book.Id, book); err != nil {
+			return nil, fmt.Errorf("[db] %w", err)
 		}
+		return book, nil
+	})
 
-		if b.Series.Valid {
-			if current_series == nil {
-				if _, err = insertSeries(tx, b.Id, b.Series.ValueOrZero()); err != nil {
-					return nil, fmt.Errorf("[db] failed to insert series for book %d: %w", b.Id, err)
-				}
-			} else {
-				if current_series.Name != b.Series.ValueOrZero() {
-					if _, err = insertSeries(tx, b.Id, b.Series.ValueOrZero()); err != nil {
-						return nil, fmt.Errorf("[db] failed to update series for book %d: %w", b.Id, err)
-					}
-
-					if err := deleteBookFromSeries(tx, b.Id, current_series.Id); err != nil {
-						return nil, fmt.Errorf("[db] failed to delete book %d from series %d: %w", b.Id, current_series.Id, err)
-					}
-				}
-			}
-		}
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Book), nil
+}
 
-		current_formats, err := getFormatsFromBook(tx, b.Id)
+func (s *Store) UpdateBook(id int64, b *dusk.Book) (*dusk.Book, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		err := updateBook(tx, id, b)
 		if err != nil {
-			return nil, fmt.Errorf("[db] failed to retrieve formats from book %d: %w", b.Id, err)
+			return nil, fmt.Errorf("[db] failed to update book %d: %w", id, err)
 		}
 
-		util.Sort(b.Formats)
-		if !reflect.DeepEqual(current_formats, b.Formats) {
-			if _, err = insertFormats(tx, b.Id, b.Formats); err != nil {
-				return nil, fmt.Errorf("[db] %w", err)
-			}
-			for _, f := range current_formats {
-				if err := deleteFormat(tx, f); err != nil {
-					return nil, fmt.Errorf("[db] failed to delete format: %w", err)
-				}
-			}
+		if err := applyStatusToSessions(tx, id, b); err != nil {
+			return nil, fmt.Errorf("[db] failed to update reading sessions of book %d: %w", id, err)
 		}
-
-		if err := deleteAuthorsWithNoBooks(tx); err != nil {
+		if err := syncBookWithSessions(tx, id, b); err != nil {
 			return nil, fmt.Errorf("[db] %w", err)
 		}
 
+		if err := updateBookLinks(tx, id, b); err != nil {
+			return nil, err
+		}
 		return b, nil
 	})
 
@@ -323,12 +225,18 @@ func (s *Store) DeleteBook(id int64) error {
 			return nil, fmt.Errorf("[db]: failed to delete book %d: %w", id, err)
 		}
 
-		// delete authors with no remaining books
-		// isbn10, isbn13, series and formats are
-		// deleted by sqlite with CASCADE
+		// delete authors, series and works with no remaining books
+		// isbn10, isbn13, identifiers, series and work links, formats and
+		// copies are deleted by sqlite with CASCADE
 		if err := deleteAuthorsWithNoBooks(tx); err != nil {
 			return nil, fmt.Errorf("[db] %w", err)
 		}
+		if err := deleteSeriesWithNoBooks(tx); err != nil {
+			return nil, fmt.Errorf("[db] %w", err)
+		}
+		if err := deleteWorksWithNoBooks(tx); err != nil {
+			return nil, fmt.Errorf("[db] %w", err)
+		}
 		return nil, nil
 	})
 	return err
@@ -342,26 +250,88 @@ func (s *Store) DeleteBooks(ids []int64) error {
 			}
 		}
 
-		// delete authors with no remaining books
-		// isbn10, isbn13, series and formats are
-		// deleted by sqlite with CASCADE
+		// delete authors, series and works with no remaining books
+		// isbn10, isbn13, identifiers, series and work links, formats and
+		// copies are deleted by sqlite with CASCADE
 		if err := deleteAuthorsWithNoBooks(tx); err != nil {
 			return nil, fmt.Errorf("[db] %w", err)
 		}
+		if err := deleteSeriesWithNoBooks(tx); err != nil {
+			return nil, fmt.Errorf("[db] %w", err)
+		}
+		if err := deleteWorksWithNoBooks(tx); err != nil {
+			return nil, fmt.Errorf("[db] %w", err)
+		}
 		return nil, nil
 	})
 	return err
 }
 
+func getBook(tx *sqlx.Tx, id int64) (*dusk.Book, error) {
+	var dest BookRow
+	stmt := `SELECT * FROM book_view b WHERE b.id=$1;`
+
+	err := tx.QueryRowx(stmt, id).StructScan(&dest)
+	if err == sql.ErrNoRows {
+		return nil, dusk.ErrDoesNotExist
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve book id %d: %w", id, err)
+	}
+
+	// TODO test if author name has comma
+	dest.Author = strings.Split(dest.AuthorString, ",")
+	dest.Tag = dest.TagString.Split(",")
+	dest.Isbn10 = dest.Isbn10String.Split(",")
+	dest.Isbn13 = dest.Isbn13String.Split(",")
+	dest.Formats = dest.FormatString.Split(",")
+	dest.Series = dest.SeriesString
+	dest.Book.SeriesPosition = dest.SeriesPosition
+
+	identifiers, err := getIdentifiersFromBook(tx, id)
+	if err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve identifiers from book %d: %w", id, err)
+	}
+	dest.Identifiers = identifiers
+
+	work, err := getWorkFromBook(tx, id)
+	if err != nil && !errors.Is(err, dusk.ErrDoesNotExist) {
+		return nil, fmt.Errorf("[db] failed to retrieve work from book %d: %w", id, err)
+	}
+	dest.Work = work
+
+	wishlist, err := getWishlistItemFromBook(tx, id)
+	if err != nil && !errors.Is(err, dusk.ErrDoesNotExist) {
+		return nil, fmt.Errorf("[db] failed to retrieve wishlist of book %d: %w", id, err)
+	}
+	dest.Wishlist = wishlist
+
+	sessions, err := getSessionsFromBook(tx, id)
+	if err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve reading sessions from book %d: %w", id, err)
+	}
+	dest.Sessions = sessions
+
+	copies, err := getCopiesFromBook(tx, id)
+	if err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve copies from book %d: %w", id, err)
+	}
+	dest.Copies = copies
+	return dest.Book, nil
+}
+
 func queryBooks(tx *sqlx.Tx, f *filters.Book, dest *[]BookQueryRow) error {
-	query, params := buildBookQuery(f)
+	query, params, err := buildBookQuery(f)
+	if err != nil {
+		return err
+	}
 
 	slog.Info("Running SQL query",
 		slog.String("stmt", util.TrimMultiLine(query)),
 		slog.Any("params", params),
 	)
 
-	err := tx.Select(dest, query, params...)
+	err = tx.Select(dest, query, params...)
 	if err != nil {
 		return err
 	}
@@ -377,6 +347,7 @@ func insertBook(tx *sqlx.Tx, b *dusk.Book) (*dusk.Book, error) {
 		progress,
 		rating,
 		status,
+		ownership,
 		publisher,
 		datePublished,
 		description,
@@ -392,6 +363,7 @@ func insertBook(tx *sqlx.Tx, b *dusk.Book) (*dusk.Book, error) {
 		:progress,
 		:rating,
 		:status,
+		:ownership,
 		:publisher,
 		:datePublished,
 		:description,
@@ -431,6 +403,7 @@ func updateBook(tx *sqlx.Tx, id int64, b *dusk.Book) error {
 			progress=:progress,
 			rating=:rating,
 			status=:status,
+			ownership=:ownership,
 			publisher=:publisher,
 			datePublished=:datePublished,
 			description=:description,
@@ -455,6 +428,183 @@ func updateBook(tx *sqlx.Tx, id int64, b *dusk.Book) error {
 	return nil
 }
 
+// update the authors, tags, isbns, identifiers, series, work, wishlist and
+// formats of a book
+func updateBookLinks(tx *sqlx.Tx, id int64, b *dusk.Book) error {
+	current_authors, err := getAuthorsFromBook(tx, b.Id)
+	if err != nil {
+		return fmt.Errorf("[db] failed to get author from book %d", id)
+	}
+
+	util.Sort(b.Author)
+	if !reflect.DeepEqual(current_authors, b.Author) {
+
+		// Renaming an author should not update the same author row for other books
+		// Always create a new author row, never update the original in this case
+		authorIDs, err := insertAuthors(tx, b.Author)
+		if err != nil {
+			return fmt.Errorf("[db] %w", err)
+		}
+
+		if err := linkBookToAuthors(tx, id, authorIDs); err != nil {
+			return fmt.Errorf("[db] %w", err)
+		}
+
+		// remove existing links for authors not in new list of ids
+		if err := unlinkBookFromAuthors(tx, id, authorIDs); err != nil {
+			return fmt.Errorf("[db] %w", err)
+		}
+	}
+
+	current_tags, err := getTagsFromBook(tx, b.Id)
+	if err != nil {
+		return fmt.Errorf("[db] failed to get tag from book %d", id)
+	}
+
+	util.Sort(b.Tag)
+	if !reflect.DeepEqual(current_tags, b.Tag) {
+		if len(b.Tag) <= 0 {
+			if err := unlinkBookFromTags(tx, id, []int64{}); err != nil {
+				return fmt.Errorf("[db] %w", err)
+			}
+		} else {
+			// Renaming a tag should not update the same tag row for other books
+			// Always create a new tag row, never update the original in this case
+			tagIDs, err := insertTags(tx, b.Tag)
+			if err != nil {
+				return fmt.Errorf("[db] %w", err)
+			}
+
+			if err := linkBookToTags(tx, id, tagIDs); err != nil {
+				return fmt.Errorf("[db] %w", err)
+			}
+
+			if err := unlinkBookFromTags(tx, id, tagIDs); err != nil {
+				return fmt.Errorf("[db] %w", err)
+			}
+		}
+	}
+
+	current_isbn10, err := getIsbn10FromBook(tx, b.Id)
+	if err != nil {
+		return fmt.Errorf("[db] failed to get isbn10 from book %d: %w", b.Id, err)
+	}
+
+	util.Sort(b.Isbn10)
+	if !reflect.DeepEqual(current_isbn10, b.Isbn10) {
+		if _, err = insertIsbn10s(tx, b.Id, b.Isbn10); err != nil {
+			return fmt.Errorf("[db] %w", err)
+		}
+
+		for _, i := range current_isbn10 {
+			if err := deleteIsbn10(tx, i); err != nil {
+				return fmt.Errorf("[db] failed to delete isbn10: %w", err)
+			}
+		}
+	}
+
+	current_isbn13, err := getIsbn13FromBook(tx, b.Id)
+	if err != nil {
+		return fmt.Errorf("[db] failed to get isbn13 from book %d: %w", b.Id, err)
+	}
+
+	util.Sort(b.Isbn13)
+	if !reflect.DeepEqual(current_isbn13, b.Isbn13) {
+		if _, err = insertIsbn13s(tx, b.Id, b.Isbn13); err != nil {
+			return fmt.Errorf("[db] %w", err)
+		}
+
+		for _, i := range current_isbn13 {
+			if err := deleteIsbn13(tx, i); err != nil {
+				return fmt.Errorf("[db] failed to delete isbn13: %w", err)
+			}
+		}
+	}
+
+	current_identifiers, err := getIdentifiersFromBook(tx, b.Id)
+	if err != nil {
+		return fmt.Errorf("[db] failed to get identifiers from book %d: %w", b.Id, err)
+	}
+
+	b.Identifiers = b.Identifiers.Normalize()
+	if !current_identifiers.Equal(b.Identifiers) {
+		if err := updateIdentifiers(tx, b.Id, b.Identifiers); err != nil {
+			return fmt.Errorf("[db] %w", err)
+		}
+	}
+
+	current_series, err := getSeriesFromBook(tx, b.Id)
+	if err != nil {
+		if !errors.Is(err, dusk.ErrDoesNotExist) {
+			return fmt.Errorf("[db] failed to get series from book %d: %w", b.Id, err)
+		}
+	}
+
+	if b.Series.Valid {
+		if current_series == nil {
+			seriesId, err := insertSeries(tx, b.Series.ValueOrZero())
+			if err != nil {
+				return fmt.Errorf("[db] failed to insert series for book %d: %w", b.Id, err)
+			}
+			if err := linkBookToSeries(tx, b.Id, seriesId, b.SeriesPosition); err != nil {
+				return fmt.Errorf("[db] %w", err)
+			}
+		} else if current_series.Name != b.Series.ValueOrZero() ||
+			!current_series.Position.Equal(b.SeriesPosition) {
+			seriesId, err := insertSeries(tx, b.Series.ValueOrZero())
+			if err != nil {
+				return fmt.Errorf("[db] failed to update series for book %d: %w", b.Id, err)
+			}
+			if err := relinkBookToSeries(tx, b.Id, current_series.Id, seriesId, b.SeriesPosition); err != nil {
+				return fmt.Errorf("[db] %w", err)
+			}
+		}
+	} else if current_series != nil {
+		if err := unlinkBookFromSeries(tx, b.Id, current_series.Id); err != nil {
+			return fmt.Errorf("[db] failed to delete book %d from series %d: %w", b.Id, current_series.Id, err)
+		}
+	}
+
+	if err := updateBookWork(tx, b); err != nil {
+		return err
+	}
+
+	if err := updateBookWishlist(tx, b.Id, b); err != nil {
+		return err
+	}
+
+	current_formats, err := getFormatsFromBook(tx, b.Id)
+	if err != nil {
+		return fmt.Errorf("[db] failed to retrieve formats from book %d: %w", b.Id, err)
+	}
+
+	util.Sort(b.Formats)
+	if !reflect.DeepEqual(current_formats, b.Formats) {
+		if _, err = insertFormats(tx, b.Id, b.Formats); err != nil {
+			return fmt.Errorf("[db] %w", err)
+		}
+		for _, f := range current_formats {
+			if slices.Contains(b.Formats, f) {
+				continue
+			}
+			if err := deleteFormat(tx, f); err != nil {
+				return fmt.Errorf("[db] failed to delete format: %w", err)
+			}
+		}
+	}
+
+	if err := deleteAuthorsWithNoBooks(tx); err != nil {
+		return fmt.Errorf("[db] %w", err)
+	}
+	if err := deleteSeriesWithNoBooks(tx); err != nil {
+		return fmt.Errorf("[db] %w", err)
+	}
+	if err := deleteWorksWithNoBooks(tx); err != nil {
+		return fmt.Errorf("[db] %w", err)
+	}
+	return nil
+}
+
 // delete book entry from books table
 func deleteBook(tx *sqlx.Tx, id int64) error {
 	stmt := `DELETE from book WHERE id=$1;`
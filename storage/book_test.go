@@ -52,8 +52,9 @@ func TestGetBook(t *testing.T) {
 
 func TestGetAllBooks(t *testing.T) {
 	is := is.New(t)
-	got, err := ts.GetAllBooks()
+	result, err := ts.GetAllBooks(testBookFilters())
 	is.NoErr(err)
+	got := result.Items
 
 	want := allTestBooks
 
@@ -68,6 +69,21 @@ func TestGetAllBooks(t *testing.T) {
 	}
 }
 
+func TestGetAllBooksLikeWildcards(t *testing.T) {
+	// short queries are matched with LIKE, whose wildcards match literally
+	for _, q := range []string{"%", "_", "o_", `\`} {
+		t.Run(q, func(t *testing.T) {
+			is := is.New(t)
+			f := testBookFilters()
+			f.Search.Search = q
+
+			result, err := ts.GetAllBooks(f)
+			is.NoErr(err)
+			is.Equal(len(result.Items), 0)
+		})
+	}
+}
+
 // func TestGetAllByTitle(t *testing.T) {
 // 	is := is.New(t)
 //
@@ -341,6 +357,7 @@ func TestCreateBook(t *testing.T) {
 			Author:        []string{"author 6"},
 			NumOfPages:    100,
 			Rating:        10,
+			Status:        dusk.Reading,
 			Progress:      80,
 			Tag:           []string{"tag 4"},
 			Publisher:     null.StringFrom("publisher 1"),
@@ -348,6 +365,15 @@ func TestCreateBook(t *testing.T) {
 			Series:        null.StringFrom("series 2"),
 			Description:   null.StringFrom("lorem ipsum"),
 			DateStarted:   null.TimeFrom(time.Now()),
+		},
+	}, {
+		name: "read book",
+		want: &dusk.Book{
+			Title:         "Book 8",
+			Author:        []string{"author 6"},
+			Status:        dusk.Read,
+			Progress:      100,
+			DateStarted:   null.TimeFrom(time.Now().AddDate(0, 0, -7)),
 			DateCompleted: null.TimeFrom(time.Now()),
 		},
 	}, {
@@ -379,6 +405,31 @@ func TestCreateBook(t *testing.T) {
 	}
 }
 
+// the reading status, dates and progress of a book are those of its reading
+// sessions, which begin from its status
+func TestCreateBookUnreadClearsReading(t *testing.T) {
+	defer resetDB()
+	is := is.New(t)
+
+	b, err := ts.CreateBook(&dusk.Book{
+		Title:         "Book 9",
+		Author:        []string{"author 6"},
+		Status:        dusk.Unread,
+		Progress:      80,
+		DateStarted:   null.TimeFrom(time.Now()),
+		DateCompleted: null.TimeFrom(time.Now()),
+	})
+	is.NoErr(err)
+
+	got, err := ts.GetBook(b.Id)
+	is.NoErr(err)
+	is.Equal(got.Status, dusk.Unread)
+	is.Equal(got.Progress, 0)
+	is.True(!got.DateStarted.Valid)
+	is.True(!got.DateCompleted.Valid)
+	is.Equal(len(got.Sessions), 0)
+}
+
 func TestCreateBookExistingIsbn10(t *testing.T) {
 	_, err := ts.CreateBook(testBook2)
 	if err == nil {
@@ -406,8 +457,9 @@ func TestCreateBookExistingAuthor(t *testing.T) {
 
 	assertAuthorsExist(t, got)
 
-	relatedBooks, err := ts.GetAllBooksFromAuthor(testAuthor1.Id)
+	result, err := ts.GetAllBooksFromAuthor(testAuthor1.Id, testBookFilters())
 	is.NoErr(err)
+	relatedBooks := result.Items
 	if len(relatedBooks) != 2 {
 		t.Errorf("got %d books, want %d books", len(relatedBooks), 2)
 	}
@@ -425,8 +477,9 @@ func TestCreateBookNewAndExistingAuthor(t *testing.T) {
 
 	assertAuthorsExist(t, got)
 
-	relatedBooks, err := ts.GetAllBooksFromAuthor(testAuthor1.Id)
+	result, err := ts.GetAllBooksFromAuthor(testAuthor1.Id, testBookFilters())
 	is.NoErr(err)
+	relatedBooks := result.Items
 	if len(relatedBooks) != 2 {
 		t.Errorf("got %d books, want %d books", len(relatedBooks), 2)
 	}
@@ -445,8 +498,9 @@ func TestCreateBookExistingTag(t *testing.T) {
 
 	assertTagsExist(t, got)
 
-	relatedBooks, err := ts.GetAllBooksFromTag(testTag1.Id)
+	result, err := ts.GetAllBooksFromTag(testTag1.Id, testBookFilters())
 	is.NoErr(err)
+	relatedBooks := result.Items
 	if len(relatedBooks) != 2 {
 		t.Errorf("got %d books, want %d books", len(relatedBooks), 2)
 	}
@@ -746,9 +800,11 @@ func TestUpdateBookSeries(t *testing.T) {
 		t.Errorf("got %v, want %v", prettyPrint(got), prettyPrint(want))
 	}
 
-	// check old series does not exist
+	// check old series is no longer linked
 	var dest []string
-	stmt := `SELECT name FROM series WHERE bookId=$1`
+	stmt := `SELECT s.name FROM series s
+		JOIN book_series_link bs ON bs.series=s.id
+		WHERE bs.book=$1`
 	if err := ts.db.Select(&dest, stmt, want.Id); err != nil {
 		t.Errorf("unexpected err: %v", err)
 	}
@@ -863,8 +919,8 @@ func TestDeleteBook(t *testing.T) {
 		t.Errorf("isbn13 %s not deleted", destName[0])
 	}
 
-	// check series deleted
-	stmt = `SELECT name FROM series WHERE bookId=$1`
+	// check series unlinked
+	stmt = `SELECT series FROM book_series_link WHERE book=$1`
 	if err := ts.db.Select(&destName, stmt, testBook1.Id); err != nil {
 		t.Errorf("unexpected err: %v", err)
 	}
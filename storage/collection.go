@@ -0,0 +1,434 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/filters"
+	"github.com/kencx/dusk/page"
+)
+
+const collectionStmt = `SELECT c.id, c.name, c.description, COUNT(cb.book) AS bookCount
+	FROM collection c
+		LEFT JOIN collection_book_link cb ON cb.collection=c.id`
+
+func (s *Store) GetCollection(id int64) (*dusk.Collection, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		return getCollection(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Collection), nil
+}
+
+// GetAllCollections returns all collections by name, without their books.
+func (s *Store) GetAllCollections() ([]dusk.Collection, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		var collections []dusk.Collection
+		stmt := collectionStmt + ` GROUP BY c.id ORDER BY c.name;`
+
+		if err := tx.Select(&collections, stmt); err != nil {
+			return nil, fmt.Errorf("[db] failed to retrieve collections: %w", err)
+		}
+		return collections, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.([]dusk.Collection), nil
+}
+
+// GetCollectionsFromBook returns the collections that contain the book, without
+// their books.
+func (s *Store) GetCollectionsFromBook(id int64) ([]dusk.Collection, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		var collections []dusk.Collection
+		stmt := collectionStmt + `
+			WHERE c.id IN (SELECT collection FROM collection_book_link WHERE book=$1)
+			GROUP BY c.id ORDER BY c.name;`
+
+		if err := tx.Select(&collections, stmt, id); err != nil {
+			return nil, fmt.Errorf("[db] failed to retrieve collections of book %d: %w", id, err)
+		}
+		return collections, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.([]dusk.Collection), nil
+}
+
+func (s *Store) CreateCollection(c *dusk.Collection) (*dusk.Collection, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stmt := `INSERT INTO collection (name, description) VALUES ($1, $2);`
+		res, err := tx.Exec(stmt, c.Name, c.Description)
+		if err != nil {
+			if isUniqueConstraintErr(err) {
+				return nil, fmt.Errorf("[db] failed to create collection: %w", dusk.ErrUniqueConstraint)
+			}
+			return nil, fmt.Errorf("[db] failed to create collection: %w", err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to create collection: %w", err)
+		}
+		return getCollection(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Collection), nil
+}
+
+// UpdateCollection updates the name and description of a collection. Its books
+// are left unchanged.
+func (s *Store) UpdateCollection(id int64, c *dusk.Collection) (*dusk.Collection, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stmt := `UPDATE collection SET name=$1, description=$2 WHERE id=$3;`
+		res, err := tx.Exec(stmt, c.Name, c.Description, id)
+		if err != nil {
+			if isUniqueConstraintErr(err) {
+				return nil, fmt.Errorf("[db] failed to rename collection %d: %w", id, dusk.ErrUniqueConstraint)
+			}
+			return nil, fmt.Errorf("[db] failed to update collection %d: %w", id, err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to update collection %d: %w", id, err)
+		}
+		if count == 0 {
+			return nil, dusk.ErrNoChange
+		}
+		return getCollection(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Collection), nil
+}
+
+// Deleting a collection does not delete its books.
+func (s *Store) DeleteCollection(id int64) error {
+	_, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		// delete cascaded to collection_book_link table
+		stmt := `DELETE FROM collection WHERE id=$1;`
+		res, err := tx.Exec(stmt, id)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete collection %d: %w", id, err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete collection %d: %w", id, err)
+		}
+		if count == 0 {
+			return nil, dusk.ErrNoChange
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// AddBookToCollection adds the book to the end of the collection.
+func (s *Store) AddBookToCollection(id, bookId int64) (*dusk.Collection, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		if _, err := getCollection(tx, id); err != nil {
+			return nil, err
+		}
+
+		var exists bool
+		if err := tx.Get(&exists, `SELECT EXISTS (SELECT 1 FROM book WHERE id=$1);`, bookId); err != nil {
+			return nil, fmt.Errorf("[db] failed to retrieve book %d: %w", bookId, err)
+		}
+		if !exists {
+			return nil, dusk.ErrDoesNotExist
+		}
+
+		stmt := `INSERT INTO collection_book_link (collection, book, position)
+			SELECT $1, $2, COALESCE(MAX(position), 0) + 1
+			FROM collection_book_link WHERE collection=$1;`
+		if _, err := tx.Exec(stmt, id, bookId); err != nil {
+			if isUniqueConstraintErr(err) {
+				return nil, fmt.Errorf("[db] failed to add book %d to collection %d: %w", bookId, id, dusk.ErrUniqueConstraint)
+			}
+			return nil, fmt.Errorf("[db] failed to add book %d to collection %d: %w", bookId, id, err)
+		}
+		return getCollection(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Collection), nil
+}
+
+// RemoveBookFromCollection removes the book from the collection. The books
+// after it move up to close the gap.
+func (s *Store) RemoveBookFromCollection(id, bookId int64) error {
+	_, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		var position int64
+		stmt := `SELECT position FROM collection_book_link WHERE collection=$1 AND book=$2;`
+		if err := tx.Get(&position, stmt, id, bookId); err != nil {
+			if err == sql.ErrNoRows {
+				return nil, dusk.ErrNoChange
+			}
+			return nil, fmt.Errorf("[db] failed to remove book %d from collection %d: %w", bookId, id, err)
+		}
+
+		stmt = `DELETE FROM collection_book_link WHERE collection=$1 AND book=$2;`
+		if _, err := tx.Exec(stmt, id, bookId); err != nil {
+			return nil, fmt.Errorf("[db] failed to remove book %d from collection %d: %w", bookId, id, err)
+		}
+
+		stmt = `UPDATE collection_book_link SET position=position-1
+			WHERE collection=$1 AND position>$2;`
+		if _, err := tx.Exec(stmt, id, position); err != nil {
+			return nil, fmt.Errorf("[db] failed to remove book %d from collection %d: %w", bookId, id, err)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// ReorderCollection moves the given books to the start of the collection in
+// the given order. Books that are not given keep their order after them, and
+// books that are not in the collection are ignored.
+func (s *Store) ReorderCollection(id int64, bookIds []int64) (*dusk.Collection, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		c, err := getCollection(tx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		var order []int64
+		for _, bookId := range bookIds {
+			inCollection := slices.ContainsFunc(c.Books, func(b dusk.Book) bool { return b.Id == bookId })
+			if inCollection && !slices.Contains(order, bookId) {
+				order = append(order, bookId)
+			}
+		}
+		for _, b := range c.Books {
+			if !slices.Contains(order, b.Id) {
+				order = append(order, b.Id)
+			}
+		}
+
+		stmt := `UPDATE collection_book_link SET position=$1 WHERE collection=$2 AND book=$3;`
+		for pos, bookId := range order {
+			if _, err := tx.Exec(stmt, pos+1, id, bookId); err != nil {
+				return nil, fmt.Errorf("[db] failed to reorder collection %d: %w", id, err)
+			}
+		}
+		return getCollection(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Collection), nil
+}
+
+func getCollection(tx *sqlx.Tx, id int64) (*dusk.Collection, error) {
+	var c dusk.Collection
+	stmt := collectionStmt + ` WHERE c.id=$1 GROUP BY c.id;`
+
+	err := tx.QueryRowx(stmt, id).StructScan(&c)
+	if err == sql.ErrNoRows {
+		return nil, dusk.ErrDoesNotExist
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve collection %d: %w", id, err)
+	}
+
+	books, err := getBooksFromCollection(tx, id)
+	if err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve books from collection %d: %w", id, err)
+	}
+	c.Books = books
+	return &c, nil
+}
+
+// get all books in collection order
+func getBooksFromCollection(tx *sqlx.Tx, id int64) ([]dusk.Book, error) {
+	var dest []BookRow
+	stmt := `SELECT b.*
+		FROM collection_book_link cb
+		JOIN book_view b ON b.id=cb.book
+		WHERE cb.collection=$1
+		ORDER BY cb.position`
+
+	if err := tx.Select(&dest, stmt, id); err != nil {
+		return nil, err
+	}
+
+	var books []dusk.Book
+	for _, row := range dest {
+		row.Author = strings.Split(row.AuthorString, ",")
+		row.Tag = row.TagString.Split(",")
+		row.Isbn10 = row.Isbn10String.Split(",")
+		row.Isbn13 = row.Isbn13String.Split(",")
+		row.Formats = row.FormatString.Split(",")
+		row.Series = row.SeriesString
+		row.Book.SeriesPosition = row.SeriesPosition
+		books = append(books, *row.Book)
+	}
+	return books, nil
+}
+
+func (s *Store) GetSmartCollection(id int64) (*dusk.SmartCollection, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		return getSmartCollection(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.SmartCollection), nil
+}
+
+func (s *Store) GetAllSmartCollections() ([]dusk.SmartCollection, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		var collections []dusk.SmartCollection
+		stmt := `SELECT * FROM smart_collection ORDER BY name;`
+
+		if err := tx.Select(&collections, stmt); err != nil {
+			return nil, fmt.Errorf("[db] failed to retrieve smart collections: %w", err)
+		}
+		return collections, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.([]dusk.SmartCollection), nil
+}
+
+// GetAllBooksFromSmartCollection runs the query of the smart collection,
+// narrowed down by any search query in f.
+func (s *Store) GetAllBooksFromSmartCollection(id int64, f *filters.Book) (*page.Page[dusk.Book], error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		c, err := getSmartCollection(tx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		sf := *f
+		sf.Search.Search = c.Search(f.Search.Search)
+
+		var dest []BookQueryRow
+		if err := queryBooks(tx, &sf, &dest); err != nil {
+			return nil, fmt.Errorf("[db] failed to query books from smart collection %d: %w", id, err)
+		}
+
+		result, err := newBookPage(dest, f)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to create new book page: %w", err)
+		}
+		return result, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*page.Page[dusk.Book]), nil
+}
+
+func (s *Store) CreateSmartCollection(c *dusk.SmartCollection) (*dusk.SmartCollection, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stmt := `INSERT INTO smart_collection (name, query) VALUES ($1, $2);`
+		res, err := tx.Exec(stmt, c.Name, c.Query)
+		if err != nil {
+			if isUniqueConstraintErr(err) {
+				return nil, fmt.Errorf("[db] failed to create smart collection: %w", dusk.ErrUniqueConstraint)
+			}
+			return nil, fmt.Errorf("[db] failed to create smart collection: %w", err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to create smart collection: %w", err)
+		}
+		c.Id = id
+		return c, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.SmartCollection), nil
+}
+
+func (s *Store) UpdateSmartCollection(id int64, c *dusk.SmartCollection) (*dusk.SmartCollection, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stmt := `UPDATE smart_collection SET name=$1, query=$2 WHERE id=$3;`
+		res, err := tx.Exec(stmt, c.Name, c.Query, id)
+		if err != nil {
+			if isUniqueConstraintErr(err) {
+				return nil, fmt.Errorf("[db] failed to rename smart collection %d: %w", id, dusk.ErrUniqueConstraint)
+			}
+			return nil, fmt.Errorf("[db] failed to update smart collection %d: %w", id, err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to update smart collection %d: %w", id, err)
+		}
+		if count == 0 {
+			return nil, dusk.ErrNoChange
+		}
+
+		c.Id = id
+		return c, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.SmartCollection), nil
+}
+
+func (s *Store) DeleteSmartCollection(id int64) error {
+	_, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stmt := `DELETE FROM smart_collection WHERE id=$1;`
+		res, err := tx.Exec(stmt, id)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete smart collection %d: %w", id, err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete smart collection %d: %w", id, err)
+		}
+		if count == 0 {
+			return nil, dusk.ErrNoChange
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func getSmartCollection(tx *sqlx.Tx, id int64) (*dusk.SmartCollection, error) {
+	var c dusk.SmartCollection
+	stmt := `SELECT * FROM smart_collection WHERE id=$1;`
+
+	err := tx.QueryRowx(stmt, id).StructScan(&c)
+	if err == sql.ErrNoRows {
+		return nil, dusk.ErrDoesNotExist
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve smart collection %d: %w", id, err)
+	}
+	return &c, nil
+}
@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/query"
+	"github.com/matryer/is"
+)
+
+func TestGetAllBooksFromSmartCollection(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	c, err := ts.CreateSmartCollection(&dusk.SmartCollection{
+		Name:  "Author 5",
+		Query: `author:"Author 5"`,
+	})
+	is.NoErr(err)
+
+	t.Run("paged", func(t *testing.T) {
+		is := is.New(t)
+		f := testBookFilters()
+		f.Limit = 1
+
+		got, err := ts.GetAllBooksFromSmartCollection(c.Id, f)
+		is.NoErr(err)
+		is.Equal(got.TotalCount, 2)
+		is.Equal(got.Items[0].Id, testBook3.Id)
+
+		// the saved query is not part of the page links
+		is.Equal(got.QueryParams.Get("q"), "")
+
+		f.AfterId = got.LastRowNo
+		next, err := ts.GetAllBooksFromSmartCollection(c.Id, f)
+		is.NoErr(err)
+		is.Equal(next.TotalCount, 2)
+		is.Equal(next.Items[0].Id, testBook4.Id)
+	})
+
+	t.Run("with search", func(t *testing.T) {
+		is := is.New(t)
+		f := testBookFilters()
+		f.Search.Search = `title:"Book 4"`
+
+		got, err := ts.GetAllBooksFromSmartCollection(c.Id, f)
+		is.NoErr(err)
+		is.Equal(got.TotalCount, 1)
+		is.Equal(got.Items[0].Id, testBook4.Id)
+		is.Equal(got.QueryParams.Get("q"), `title:"Book 4"`)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		is := is.New(t)
+		bad, err := ts.CreateSmartCollection(&dusk.SmartCollection{
+			Name:  "Invalid",
+			Query: `(author:"Author 5"`,
+		})
+		is.NoErr(err)
+
+		_, err = ts.GetAllBooksFromSmartCollection(bad.Id, testBookFilters())
+		is.True(errors.Is(err, query.ErrInvalidQuery))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		is := is.New(t)
+		_, err := ts.GetAllBooksFromSmartCollection(-1, testBookFilters())
+		is.True(errors.Is(err, dusk.ErrDoesNotExist))
+	})
+}
+
+// createTestCollection creates a collection of the test books in order
+func createTestCollection(t *testing.T) *dusk.Collection {
+	t.Helper()
+	is := is.New(t)
+
+	c, err := ts.CreateCollection(&dusk.Collection{Name: "Collection 1"})
+	is.NoErr(err)
+	for _, b := range allTestBooks {
+		c, err = ts.AddBookToCollection(c.Id, b.Id)
+		is.NoErr(err)
+	}
+	return c
+}
+
+// collectionOrder returns the ids and positions of the books in the collection
+func collectionOrder(t *testing.T, id int64) ([]int64, []int64) {
+	t.Helper()
+
+	var dest []struct {
+		Book     int64 `db:"book"`
+		Position int64 `db:"position"`
+	}
+	stmt := `SELECT book, position FROM collection_book_link WHERE collection=$1 ORDER BY position;`
+	if err := ts.db.Select(&dest, stmt, id); err != nil {
+		t.Fatal(err)
+	}
+
+	var books, positions []int64
+	for _, row := range dest {
+		books = append(books, row.Book)
+		positions = append(positions, row.Position)
+	}
+	return books, positions
+}
+
+func TestAddBookToCollection(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	c := createTestCollection(t)
+
+	// books are added to the end
+	is.Equal(c.BookCount, 4)
+	for i, b := range c.Books {
+		is.Equal(b.Id, allTestBooks[i].Id)
+	}
+	books, positions := collectionOrder(t, c.Id)
+	is.Equal(books, []int64{1, 2, 3, 4})
+	is.Equal(positions, []int64{1, 2, 3, 4})
+
+	_, err := ts.AddBookToCollection(c.Id, testBook1.Id)
+	is.True(errors.Is(err, dusk.ErrUniqueConstraint))
+
+	_, err = ts.AddBookToCollection(c.Id, -1)
+	is.True(errors.Is(err, dusk.ErrDoesNotExist))
+
+	_, err = ts.AddBookToCollection(-1, testBook1.Id)
+	is.True(errors.Is(err, dusk.ErrDoesNotExist))
+}
+
+func TestRemoveBookFromCollection(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	c := createTestCollection(t)
+
+	is.NoErr(ts.RemoveBookFromCollection(c.Id, testBook2.Id))
+	books, positions := collectionOrder(t, c.Id)
+	is.Equal(books, []int64{1, 3, 4})
+	is.Equal(positions, []int64{1, 2, 3})
+
+	// a book added after the removal still goes to the end
+	_, err := ts.AddBookToCollection(c.Id, testBook2.Id)
+	is.NoErr(err)
+	books, positions = collectionOrder(t, c.Id)
+	is.Equal(books, []int64{1, 3, 4, 2})
+	is.Equal(positions, []int64{1, 2, 3, 4})
+
+	err = ts.RemoveBookFromCollection(c.Id, -1)
+	is.True(errors.Is(err, dusk.ErrNoChange))
+}
+
+func TestReorderCollection(t *testing.T) {
+	tests := []struct {
+		name  string
+		order []int64
+		want  []int64
+	}{{
+		name:  "all books",
+		order: []int64{4, 3, 2, 1},
+		want:  []int64{4, 3, 2, 1},
+	}, {
+		name:  "missing books keep their order",
+		order: []int64{3},
+		want:  []int64{3, 1, 2, 4},
+	}, {
+		name:  "unknown books are ignored",
+		order: []int64{-1, 4, 99, 2},
+		want:  []int64{4, 2, 1, 3},
+	}, {
+		name:  "duplicates",
+		order: []int64{2, 2, 1},
+		want:  []int64{2, 1, 3, 4},
+	}, {
+		name:  "empty",
+		order: nil,
+		want:  []int64{1, 2, 3, 4},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer resetDB()
+
+			is := is.New(t)
+			c := createTestCollection(t)
+
+			got, err := ts.ReorderCollection(c.Id, tt.order)
+			is.NoErr(err)
+			for i, b := range got.Books {
+				is.Equal(b.Id, tt.want[i])
+			}
+
+			// the order is persisted without gaps
+			books, positions := collectionOrder(t, c.Id)
+			is.Equal(books, tt.want)
+			is.Equal(positions, []int64{1, 2, 3, 4})
+		})
+	}
+
+	_, err := ts.ReorderCollection(-1, []int64{1})
+	if !errors.Is(err, dusk.ErrDoesNotExist) {
+		t.Errorf("got %v, want %v", err, dusk.ErrDoesNotExist)
+	}
+}
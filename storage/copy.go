@@ -0,0 +1,381 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kencx/dusk"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func (s *Store) GetCopies(bookId int64) ([]dusk.Copy, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		copies, err := getCopiesFromBook(tx, bookId)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to retrieve copies from book %d: %w", bookId, err)
+		}
+		return copies, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.([]dusk.Copy), nil
+}
+
+func (s *Store) GetCopy(id int64) (*dusk.Copy, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		return getCopy(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Copy), nil
+}
+
+// CreateCopy adds a copy, with its loans, to the book.
+func (s *Store) CreateCopy(bookId int64, c *dusk.Copy) (*dusk.Copy, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		if err := insertCopy(tx, bookId, c); err != nil {
+			if isForeignKeyConstraintErr(err) {
+				return nil, dusk.ErrDoesNotExist
+			}
+			if isUniqueConstraintErr(err) {
+				return nil, fmt.Errorf("[db] failed to create copy for book %d: %w", bookId, dusk.ErrCopyLent)
+			}
+			return nil, fmt.Errorf("[db] failed to create copy for book %d: %w", bookId, err)
+		}
+		return c, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Copy), nil
+}
+
+// UpdateCopy updates the details of a copy. Its loans are left unchanged.
+func (s *Store) UpdateCopy(id int64, c *dusk.Copy) (*dusk.Copy, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		current, err := getCopy(tx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		c.Id = id
+		c.BookId = current.BookId
+		stmt := `UPDATE copy
+			SET
+				condition=:condition,
+				location=:location,
+				dateAcquired=:dateAcquired,
+				price=:price,
+				notes=:notes
+			WHERE id=:id;`
+		if _, err := tx.NamedExec(stmt, c); err != nil {
+			return nil, fmt.Errorf("[db] failed to update copy %d: %w", id, err)
+		}
+
+		c.Loans = current.Loans
+		return c, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Copy), nil
+}
+
+// Deleting a copy deletes its loans.
+func (s *Store) DeleteCopy(id int64) error {
+	_, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		// delete cascaded to loan table
+		stmt := `DELETE FROM copy WHERE id=$1;`
+		res, err := tx.Exec(stmt, id)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete copy %d: %w", id, err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete copy %d: %w", id, err)
+		}
+		if count == 0 {
+			return nil, dusk.ErrDoesNotExist
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// GetActiveLoans returns all loans that have not been returned, with the book
+// of their copy, by due date. Loans with no due date are ordered last.
+func (s *Store) GetActiveLoans() ([]dusk.Loan, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		var dest []struct {
+			dusk.Loan
+			BookId       int64  `db:"bookId"`
+			Title        string `db:"title"`
+			AuthorString string `db:"author_string"`
+		}
+		stmt := `SELECT l.*, b.id AS bookId, b.title, b.author_string
+			FROM loan l
+				JOIN copy c ON c.id=l.copyId
+				JOIN book_view b ON b.id=c.bookId
+			WHERE l.dateReturned IS NULL
+			ORDER BY l.dateDue IS NULL, l.dateDue, l.dateLent;`
+
+		if err := tx.Select(&dest, stmt); err != nil {
+			return nil, fmt.Errorf("[db] failed to retrieve active loans: %w", err)
+		}
+
+		var loans []dusk.Loan
+		for _, row := range dest {
+			row.Loan.Book = &dusk.Book{
+				Id:     row.BookId,
+				Title:  row.Title,
+				Author: strings.Split(row.AuthorString, ","),
+			}
+			loans = append(loans, row.Loan)
+		}
+		return loans, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.([]dusk.Loan), nil
+}
+
+func (s *Store) GetLoan(id int64) (*dusk.Loan, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		return getLoan(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Loan), nil
+}
+
+// CreateLoan lends the copy to a borrower. It fails with ErrCopyLent if the
+// copy has not been returned from its last loan.
+func (s *Store) CreateLoan(copyId int64, l *dusk.Loan) (*dusk.Loan, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		if err := insertLoan(tx, copyId, l); err != nil {
+			if isForeignKeyConstraintErr(err) {
+				return nil, dusk.ErrDoesNotExist
+			}
+			if isUniqueConstraintErr(err) {
+				return nil, fmt.Errorf("[db] failed to lend copy %d: %w", copyId, dusk.ErrCopyLent)
+			}
+			return nil, fmt.Errorf("[db] failed to lend copy %d: %w", copyId, err)
+		}
+		return l, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Loan), nil
+}
+
+func (s *Store) UpdateLoan(id int64, l *dusk.Loan) (*dusk.Loan, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		current, err := getLoan(tx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		l.Id = id
+		l.CopyId = current.CopyId
+		stmt := `UPDATE loan
+			SET
+				borrower=:borrower,
+				dateLent=:dateLent,
+				dateDue=:dateDue,
+				dateReturned=:dateReturned,
+				notes=:notes
+			WHERE id=:id;`
+		if _, err := tx.NamedExec(stmt, l); err != nil {
+			if isUniqueConstraintErr(err) {
+				return nil, fmt.Errorf("[db] failed to update loan %d: %w", id, dusk.ErrCopyLent)
+			}
+			return nil, fmt.Errorf("[db] failed to update loan %d: %w", id, err)
+		}
+		return l, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Loan), nil
+}
+
+// ReturnLoan records the return of the lent copy on the given date. It fails
+// with ErrNoChange if the copy has already been returned.
+func (s *Store) ReturnLoan(id int64, date time.Time) (*dusk.Loan, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		current, err := getLoan(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !current.Active() {
+			return nil, dusk.ErrNoChange
+		}
+
+		stmt := `UPDATE loan SET dateReturned=$1 WHERE id=$2;`
+		if _, err := tx.Exec(stmt, date, id); err != nil {
+			return nil, fmt.Errorf("[db] failed to return loan %d: %w", id, err)
+		}
+		return getLoan(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Loan), nil
+}
+
+func (s *Store) DeleteLoan(id int64) error {
+	_, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stmt := `DELETE FROM loan WHERE id=$1;`
+		res, err := tx.Exec(stmt, id)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete loan %d: %w", id, err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete loan %d: %w", id, err)
+		}
+		if count == 0 {
+			return nil, dusk.ErrDoesNotExist
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func getCopy(tx *sqlx.Tx, id int64) (*dusk.Copy, error) {
+	var c dusk.Copy
+	stmt := `SELECT * FROM copy WHERE id=$1;`
+
+	if err := tx.QueryRowx(stmt, id).StructScan(&c); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, dusk.ErrDoesNotExist
+		}
+		return nil, fmt.Errorf("[db] failed to retrieve copy %d: %w", id, err)
+	}
+
+	loans, err := getLoansFromCopies(tx, []int64{id})
+	if err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve loans of copy %d: %w", id, err)
+	}
+	c.Loans = loans[id]
+	return &c, nil
+}
+
+// get all copies of book in order of acquisition
+func getCopiesFromBook(tx *sqlx.Tx, bookId int64) ([]dusk.Copy, error) {
+	var copies []dusk.Copy
+	stmt := `SELECT * FROM copy WHERE bookId=$1 ORDER BY id;`
+
+	if err := tx.Select(&copies, stmt, bookId); err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, c := range copies {
+		ids = append(ids, c.Id)
+	}
+
+	loans, err := getLoansFromCopies(tx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i, c := range copies {
+		copies[i].Loans = loans[c.Id]
+	}
+	return copies, nil
+}
+
+func getLoansFromCopies(tx *sqlx.Tx, copyIds []int64) (map[int64][]dusk.Loan, error) {
+	result := make(map[int64][]dusk.Loan)
+	if len(copyIds) == 0 {
+		return result, nil
+	}
+
+	var loans []dusk.Loan
+	query, args, err := sqlx.In(`SELECT * FROM loan
+		WHERE copyId IN (?)
+		ORDER BY dateLent, id;`, copyIds)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Select(&loans, tx.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	for _, l := range loans {
+		result[l.CopyId] = append(result[l.CopyId], l)
+	}
+	return result, nil
+}
+
+func getLoan(tx *sqlx.Tx, id int64) (*dusk.Loan, error) {
+	var l dusk.Loan
+	stmt := `SELECT * FROM loan WHERE id=$1;`
+
+	if err := tx.QueryRowx(stmt, id).StructScan(&l); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, dusk.ErrDoesNotExist
+		}
+		return nil, fmt.Errorf("[db] failed to retrieve loan %d: %w", id, err)
+	}
+	return &l, nil
+}
+
+func insertCopy(tx *sqlx.Tx, bookId int64, c *dusk.Copy) error {
+	c.BookId = bookId
+	stmt := `INSERT INTO copy (bookId, condition, location, dateAcquired, price, notes)
+		VALUES (:bookId, :condition, :location, :dateAcquired, :price, :notes);`
+
+	res, err := tx.NamedExec(stmt, c)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	c.Id = id
+
+	for i := range c.Loans {
+		if err := insertLoan(tx, id, &c.Loans[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertLoan(tx *sqlx.Tx, copyId int64, l *dusk.Loan) error {
+	l.CopyId = copyId
+	stmt := `INSERT INTO loan (copyId, borrower, dateLent, dateDue, dateReturned, notes)
+		VALUES (:copyId, :borrower, :dateLent, :dateDue, :dateReturned, :notes);`
+
+	res, err := tx.NamedExec(stmt, l)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	l.Id = id
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/filters"
 	"github.com/kencx/dusk/null"
 )
 
@@ -13,12 +14,12 @@ var (
 	testAuthor3    = &dusk.Author{Id: 3, Name: "Author 3"}
 	testAuthor4    = &dusk.Author{Id: 4, Name: "Author 4"}
 	testAuthor5    = &dusk.Author{Id: 5, Name: "Author 5"}
-	allTestAuthors = dusk.Authors{testAuthor1, testAuthor2, testAuthor3, testAuthor4, testAuthor5}
+	allTestAuthors = []*dusk.Author{testAuthor1, testAuthor2, testAuthor3, testAuthor4, testAuthor5}
 
 	testTag1    = &dusk.Tag{Id: 1, Name: "tag 1"}
 	testTag2    = &dusk.Tag{Id: 2, Name: "tag 2"}
 	testTag3    = &dusk.Tag{Id: 3, Name: "tag 3"}
-	allTestTags = dusk.Tags{testTag1, testTag2, testTag3}
+	allTestTags = []*dusk.Tag{testTag1, testTag2, testTag3}
 
 	testIsbn101 = "0441013597"
 	testIsbn102 = "0141439513"
@@ -61,17 +62,22 @@ var (
 	testSortSafeList = []string{"id", "title", "-id", "-title"}
 )
 
-func testFilters() dusk.BookFilters {
-	return dusk.BookFilters{
-		Filters: dusk.Filters{
-			AfterId:      0,
-			PageSize:     5,
-			Sort:         "id",
-			SortSafeList: testSortSafeList,
+func testFilters() *filters.Search {
+	return &filters.Search{
+		Base: filters.Base{
+			AfterId:       0,
+			Limit:         5,
+			Sort:          "id",
+			SortDirection: "ASC",
+			SortSafeList:  testSortSafeList,
 		},
 	}
 }
 
+func testBookFilters() *filters.Book {
+	return &filters.Book{Search: *testFilters()}
+}
+
 var stmts = map[string]string{
 	"book":   `INSERT INTO book (title) VALUES ('%s');`,
 	"author": `INSERT INTO author (name) VALUES ('%s');`,
@@ -86,8 +92,10 @@ var stmts = map[string]string{
 		(SELECT id FROM tag WHERE name = '%s')
 	);`,
 
-	"series": `INSERT INTO series (bookId, name) VALUES (
-		(SELECT id FROM book WHERE title = '%s'), '%s'
+	"series": `INSERT OR IGNORE INTO series (name) VALUES ('%[2]s');
+	INSERT INTO book_series_link (book, series) VALUES (
+		(SELECT id FROM book WHERE title = '%[1]s'),
+		(SELECT id FROM series WHERE name = '%[2]s')
 	);`,
 	"isbn10": `INSERT INTO isbn10 (bookId, isbn) VALUES (
 		(SELECT id FROM book WHERE title = '%s'), '%s'
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kencx/dusk"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// GetDuplicateCandidates returns all books with any of the given ISBNs or
+// authors. Authors are matched case-insensitively by name or alias. Only the
+// fields of the book view are filled in, the full book is retrieved with
+// GetBook.
+func (s *Store) GetDuplicateCandidates(isbns, authors []string) (dusk.Books, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		var names []string
+		for _, a := range authors {
+			names = append(names, strings.ToLower(a))
+		}
+
+		// IN () is not valid sql
+		if len(isbns) == 0 {
+			isbns = []string{""}
+		}
+		if len(names) == 0 {
+			names = []string{""}
+		}
+
+		stmt := `SELECT * FROM book_view WHERE id IN (
+				SELECT b.id
+				FROM book b
+					LEFT JOIN isbn10 i ON i.bookId=b.id
+					LEFT JOIN isbn13 j ON j.bookId=b.id
+					LEFT JOIN book_author_link ba ON ba.book=b.id
+					LEFT JOIN author a ON a.id=ba.author
+					LEFT JOIN author_alias al ON al.authorId=a.id
+				WHERE i.isbn IN (?) OR j.isbn IN (?) OR LOWER(a.name) IN (?) OR LOWER(al.name) IN (?))
+			ORDER BY id;`
+
+		query, args, err := sqlx.In(stmt, isbns, isbns, names, names)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to query duplicate candidates: %w", err)
+		}
+
+		var dest []BookRow
+		if err := tx.Select(&dest, tx.Rebind(query), args...); err != nil {
+			return nil, fmt.Errorf("[db] failed to query duplicate candidates: %w", err)
+		}
+
+		var books dusk.Books
+		for _, row := range dest {
+			row.Author = strings.Split(row.AuthorString, ",")
+			row.Tag = row.TagString.Split(",")
+			row.Isbn10 = row.Isbn10String.Split(",")
+			row.Isbn13 = row.Isbn13String.Split(",")
+			row.Formats = row.FormatString.Split(",")
+			row.Series = row.SeriesString
+			row.Book.SeriesPosition = row.SeriesPosition
+			books = append(books, row.Book)
+		}
+		return books, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(dusk.Books), nil
+}
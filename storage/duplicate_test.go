@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestGetDuplicateCandidates(t *testing.T) {
+	is := is.New(t)
+
+	got, err := ts.GetDuplicateCandidates([]string{testIsbn101}, []string{"author 5"})
+	is.NoErr(err)
+	is.Equal(len(got), 3)
+
+	is.Equal(got[0].Id, testBook2.Id)
+	is.Equal(got[0].Isbn10, testBook2.Isbn10)
+	is.Equal(got[0].Series, testBook2.Series)
+	is.Equal(got[1].Id, testBook3.Id)
+	is.Equal(got[1].Author, testBook3.Author)
+	is.Equal(got[2].Id, testBook4.Id)
+}
+
+func TestGetDuplicateCandidatesNone(t *testing.T) {
+	is := is.New(t)
+
+	got, err := ts.GetDuplicateCandidates(nil, nil)
+	is.NoErr(err)
+	is.Equal(len(got), 0)
+}
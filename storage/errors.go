@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+func isUniqueConstraintErr(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) &&
+		(sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique ||
+			sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey)
+}
+
+// ON DELETE RESTRICT violations are reported as SQLITE_CONSTRAINT_TRIGGER instead
+// of SQLITE_CONSTRAINT_FOREIGNKEY
+func isForeignKeyConstraintErr(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) &&
+		sqliteErr.Code == sqlite3.ErrConstraint &&
+		strings.Contains(sqliteErr.Error(), "FOREIGN KEY constraint failed")
+}
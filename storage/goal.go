@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kencx/dusk"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func (s *Store) GetGoal(year int) (*dusk.Goal, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		var goal dusk.Goal
+		stmt := `SELECT * FROM reading_goal WHERE year=$1;`
+
+		err := tx.QueryRowx(stmt, year).StructScan(&goal)
+		if err == sql.ErrNoRows {
+			return nil, dusk.ErrDoesNotExist
+		}
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to retrieve goal for %d: %w", year, err)
+		}
+
+		if err := projectGoal(tx, &goal); err != nil {
+			return nil, fmt.Errorf("[db] %w", err)
+		}
+		return &goal, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Goal), nil
+}
+
+func (s *Store) GetAllGoals() ([]dusk.Goal, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		var goals []dusk.Goal
+		stmt := `SELECT * FROM reading_goal ORDER BY year DESC;`
+
+		if err := tx.Select(&goals, stmt); err != nil {
+			return nil, fmt.Errorf("[db] failed to retrieve goals: %w", err)
+		}
+
+		for i := range goals {
+			if err := projectGoal(tx, &goals[i]); err != nil {
+				return nil, fmt.Errorf("[db] %w", err)
+			}
+		}
+		return goals, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.([]dusk.Goal), nil
+}
+
+// SetGoal creates or replaces the goal of its year.
+func (s *Store) SetGoal(g *dusk.Goal) (*dusk.Goal, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stmt := `INSERT INTO reading_goal (year, books, pages)
+			VALUES (:year, :books, :pages)
+			ON CONFLICT (year) DO UPDATE
+			SET books=excluded.books, pages=excluded.pages;`
+
+		if _, err := tx.NamedExec(stmt, g); err != nil {
+			return nil, fmt.Errorf("[db] failed to set goal for %d: %w", g.Year, err)
+		}
+
+		if err := projectGoal(tx, g); err != nil {
+			return nil, fmt.Errorf("[db] %w", err)
+		}
+		return g, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Goal), nil
+}
+
+func (s *Store) DeleteGoal(year int) error {
+	_, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stmt := `DELETE FROM reading_goal WHERE year=$1;`
+		res, err := tx.Exec(stmt, year)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete goal for %d: %w", year, err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete goal for %d: %w", year, err)
+		}
+
+		if count == 0 {
+			return nil, dusk.ErrNoChange
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// projectGoal computes the goal's progress from the books finished in its year
+func projectGoal(tx *sqlx.Tx, g *dusk.Goal) error {
+	var stats dusk.Stats
+	if err := getTotalStats(tx, strconv.Itoa(g.Year), &stats); err != nil {
+		return fmt.Errorf("failed to query progress of goal for %d: %w", g.Year, err)
+	}
+
+	g.Project(stats.BooksRead, stats.PagesRead, time.Now())
+	return nil
+}
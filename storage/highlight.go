@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/filters"
+	"github.com/kencx/dusk/page"
+	"github.com/kencx/dusk/util"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func (s *Store) GetHighlights(bookId int64) ([]dusk.Highlight, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		highlights, err := getHighlightsFromBook(tx, bookId)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to retrieve highlights from book %d: %w", bookId, err)
+		}
+		return highlights, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.([]dusk.Highlight), nil
+}
+
+func (s *Store) GetHighlight(id int64) (*dusk.Highlight, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		return getHighlight(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Highlight), nil
+}
+
+func (s *Store) CreateHighlight(bookId int64, h *dusk.Highlight) (*dusk.Highlight, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		h.BookId = bookId
+		stmt := `INSERT INTO highlight (bookId, text, page, location, chapter, note, dateHighlighted)
+			VALUES (:bookId, :text, :page, :location, :chapter, :note, :dateHighlighted);`
+
+		res, err := tx.NamedExec(stmt, h)
+		if err != nil {
+			if isForeignKeyConstraintErr(err) {
+				return nil, dusk.ErrDoesNotExist
+			}
+			if isUniqueConstraintErr(err) {
+				return nil, dusk.ErrUniqueConstraint
+			}
+			return nil, fmt.Errorf("[db] failed to create highlight for book %d: %w", bookId, err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to create highlight for book %d: %w", bookId, err)
+		}
+		h.Id = id
+		return h, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Highlight), nil
+}
+
+// AddHighlights adds highlights to the book, skipping those that it already
+// has. It returns the number of highlights that were added.
+func (s *Store) AddHighlights(bookId int64, highlights []dusk.Highlight) (int, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stmt := `INSERT OR IGNORE INTO highlight (bookId, text, page, location, chapter, note, dateHighlighted)
+			VALUES (:bookId, :text, :page, :location, :chapter, :note, :dateHighlighted);`
+
+		var added int
+		for _, h := range highlights {
+			h.BookId = bookId
+			res, err := tx.NamedExec(stmt, h)
+			if err != nil {
+				if isForeignKeyConstraintErr(err) {
+					return nil, dusk.ErrDoesNotExist
+				}
+				return nil, fmt.Errorf("[db] failed to add highlights to book %d: %w", bookId, err)
+			}
+
+			count, err := res.RowsAffected()
+			if err != nil {
+				return nil, fmt.Errorf("[db] failed to add highlights to book %d: %w", bookId, err)
+			}
+			added += int(count)
+		}
+		return added, nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return i.(int), nil
+}
+
+func (s *Store) UpdateHighlight(id int64, h *dusk.Highlight) (*dusk.Highlight, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		current, err := getHighlight(tx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		h.Id = id
+		h.BookId = current.BookId
+		stmt := `UPDATE highlight
+			SET
+				text=:text,
+				page=:page,
+				location=:location,
+				chapter=:chapter,
+				note=:note,
+				dateHighlighted=:dateHighlighted
+			WHERE id=:id;`
+		if _, err := tx.NamedExec(stmt, h); err != nil {
+			if isUniqueConstraintErr(err) {
+				return nil, dusk.ErrUniqueConstraint
+			}
+			return nil, fmt.Errorf("[db] failed to update highlight %d: %w", id, err)
+		}
+		return h, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Highlight), nil
+}
+
+func (s *Store) DeleteHighlight(id int64) error {
+	_, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stmt := `DELETE FROM highlight WHERE id=$1;`
+		res, err := tx.Exec(stmt, id)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete highlight %d: %w", id, err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete highlight %d: %w", id, err)
+		}
+		if count == 0 {
+			return nil, dusk.ErrDoesNotExist
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// SearchHighlights returns a page of the highlights whose text or note contain
+// the search term, with their book. They are ranked best match first.
+func (s *Store) SearchHighlights(f *filters.Search) (*page.Page[dusk.Highlight], error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		var dest []HighlightQueryRow
+
+		err := queryHighlights(tx, f, &dest)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to search highlights: %w", err)
+		}
+
+		result, err := newHighlightPage(dest, f)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to create new highlight page: %w", err)
+		}
+		return result, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*page.Page[dusk.Highlight]), nil
+}
+
+// queryHighlights ranks matching highlights by their fts rank or, for searches
+// too short for fts, by book and page.
+func queryHighlights(tx *sqlx.Tx, f *filters.Search, dest *[]HighlightQueryRow) error {
+	var search string
+	if f != nil {
+		search = f.Search
+	}
+
+	var table string
+	var params []any
+	if utf8.RuneCountInString(search) < minFtsLength {
+		table = `(SELECT h.*, b.title, b.author_string,
+				ROW_NUMBER() OVER(ORDER BY b.title, h.page, h.id) AS rank
+			FROM highlight h
+				JOIN book_view b ON b.id=h.bookId
+			WHERE h.text LIKE $1 ESCAPE '\' OR h.note LIKE $1 ESCAPE '\')`
+		params = []any{likePattern(search)}
+	} else {
+		table = `(SELECT h.*, b.title, b.author_string, f.rank AS rank
+			FROM highlight_fts f
+				JOIN highlight h ON h.id=f.rowid
+				JOIN book_view b ON b.id=h.bookId
+			WHERE highlight_fts MATCH $1)`
+		params = []any{ftsPhrase(search)}
+	}
+
+	var query string
+	if f == nil {
+		query = buildBaseStmt("rank", "ASC", table, "")
+	} else {
+		query = buildPagedStmt(&f.Base, table, "")
+		params = append(params, f.AfterId, f.Limit)
+	}
+
+	slog.Info("Running SQL query",
+		slog.String("stmt", util.TrimMultiLine(query)),
+		slog.Any("params", params),
+	)
+	return tx.Select(dest, query, params...)
+}
+
+func getHighlight(tx *sqlx.Tx, id int64) (*dusk.Highlight, error) {
+	var h dusk.Highlight
+	stmt := `SELECT * FROM highlight WHERE id=$1;`
+
+	if err := tx.QueryRowx(stmt, id).StructScan(&h); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, dusk.ErrDoesNotExist
+		}
+		return nil, fmt.Errorf("[db] failed to retrieve highlight %d: %w", id, err)
+	}
+	return &h, nil
+}
+
+// get all highlights of book in reading order. Locations are ordered by their
+// first number, e.g. 180 for 180-182.
+func getHighlightsFromBook(tx *sqlx.Tx, bookId int64) ([]dusk.Highlight, error) {
+	var highlights []dusk.Highlight
+	stmt := `SELECT * FROM highlight
+		WHERE bookId=$1
+		ORDER BY page, CAST(location AS INTEGER), id;`
+
+	if err := tx.Select(&highlights, stmt, bookId); err != nil {
+		return nil, err
+	}
+	return highlights, nil
+}
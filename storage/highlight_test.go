@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/filters"
+	"github.com/matryer/is"
+)
+
+func TestSearchHighlights(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	for i := range 5 {
+		_, err := ts.CreateHighlight(testBook1.Id, &dusk.Highlight{
+			Text: fmt.Sprintf("a quiet highlight %d", i),
+			Page: i + 1,
+		})
+		is.NoErr(err)
+	}
+	_, err := ts.CreateHighlight(testBook2.Id, &dusk.Highlight{Text: "something else", Page: 1})
+	is.NoErr(err)
+
+	tests := []struct {
+		name   string
+		search string
+		total  int
+	}{
+		{"all", "", 6},
+		{"short", "qu", 5},
+		{"fts", "quiet", 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+			f := &filters.Search{Search: tt.search, Base: filters.Base{
+				AfterId:       0,
+				Limit:         2,
+				Sort:          "rank",
+				SortDirection: "ASC",
+				SortSafeList:  filters.HighlightSafeList(),
+			}}
+
+			got, err := ts.SearchHighlights(f)
+			is.NoErr(err)
+			is.Equal(got.TotalCount, tt.total)
+			is.Equal(len(got.Items), 2)
+			is.Equal(got.Items[0].Book.Id, testBook1.Id)
+
+			// the next page continues after the first
+			f.AfterId = got.LastRowNo
+			next, err := ts.SearchHighlights(f)
+			is.NoErr(err)
+			is.Equal(next.FirstRowNo, 3)
+			is.True(next.Items[0].Id != got.Items[0].Id)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kencx/dusk"
+)
+
+func getIdentifiersFromBook(tx *sqlx.Tx, bookId int64) (dusk.Identifiers, error) {
+	var dest []struct {
+		Scheme string
+		Value  string
+	}
+	stmt := `SELECT scheme, value
+		FROM identifier
+		WHERE bookId=$1
+		ORDER BY scheme, rowid`
+
+	if err := tx.Select(&dest, stmt, bookId); err != nil {
+		return nil, err
+	}
+
+	var result dusk.Identifiers
+	for _, v := range dest {
+		result.Add(v.Scheme, v.Value)
+	}
+	return result, nil
+}
+
+// Insert the given identifiers of a book. If an identifier already belongs to
+// another book, dusk.ErrIdentifierExists is returned.
+func insertIdentifiers(tx *sqlx.Tx, bookId int64, identifiers dusk.Identifiers) error {
+	stmt := `INSERT INTO identifier (scheme, value, bookId) VALUES ($1, $2, $3)
+		ON CONFLICT (scheme, value) DO UPDATE SET bookId=excluded.bookId
+		WHERE bookId=excluded.bookId;`
+
+	for scheme, values := range identifiers {
+		scheme = dusk.IdentifierScheme(scheme)
+		for _, value := range values {
+			res, err := tx.Exec(stmt, scheme, value, bookId)
+			if err != nil {
+				return fmt.Errorf("failed to insert identifier %s:%s to book %d: %w", scheme, value, bookId, err)
+			}
+
+			count, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				return fmt.Errorf("failed to insert identifier %s:%s to book %d: %w", scheme, value, bookId, dusk.ErrIdentifierExists)
+			}
+		}
+	}
+	return nil
+}
+
+// replace the identifiers of a book with the given identifiers
+func updateIdentifiers(tx *sqlx.Tx, bookId int64, identifiers dusk.Identifiers) error {
+	if _, err := tx.Exec(`DELETE FROM identifier WHERE bookId=$1;`, bookId); err != nil {
+		return fmt.Errorf("failed to delete identifiers of book %d: %w", bookId, err)
+	}
+	return insertIdentifiers(tx, bookId, identifiers)
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/kencx/dusk"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// GetImportedBook returns the book that was imported from the given source
+// and source id.
+func (s *Store) GetImportedBook(source, sourceId string) (*dusk.Book, error) {
+	var bookId int64
+	stmt := `SELECT bookId FROM import_source WHERE source=$1 AND sourceId=$2;`
+
+	err := s.db.Get(&bookId, stmt, source, sourceId)
+	if err == sql.ErrNoRows {
+		return nil, dusk.ErrDoesNotExist
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve imported book %s %s: %w", source, sourceId, err)
+	}
+	return s.GetBook(bookId)
+}
+
+// SetImportedBook records the source of an imported book.
+func (s *Store) SetImportedBook(source, sourceId string, bookId int64) error {
+	_, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stmt := `INSERT INTO import_source (source, sourceId, bookId) VALUES ($1, $2, $3)
+			ON CONFLICT (source, sourceId) DO UPDATE SET bookId=excluded.bookId;`
+
+		if _, err := tx.Exec(stmt, source, sourceId, bookId); err != nil {
+			if isForeignKeyConstraintErr(err) {
+				return nil, dusk.ErrDoesNotExist
+			}
+			return nil, fmt.Errorf("[db] failed to insert import source %s %s: %w", source, sourceId, err)
+		}
+		return nil, nil
+	})
+	return err
+}
@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kencx/dusk"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const jobStmt = `SELECT j.id, j.kind, j.state, j.error, j.dateCreated, j.dateStarted, j.dateCompleted,
+		COUNT(i.id) AS total,
+		COALESCE(SUM(i.state=1), 0) AS done,
+		COALESCE(SUM(i.state=2), 0) AS failed,
+		COALESCE(SUM(i.state=3), 0) AS skipped
+	FROM job j
+		LEFT JOIN job_item i ON i.jobId=j.id`
+
+func (s *Store) GetJob(id int64) (*dusk.Job, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		job, err := getJob(tx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		job.Items, err = getJobItems(tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to retrieve items of job %d: %w", id, err)
+		}
+		return job, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Job), nil
+}
+
+// GetAllJobs returns all jobs, most recent first, without their items.
+func (s *Store) GetAllJobs() ([]dusk.Job, error) {
+	var jobs []dusk.Job
+	stmt := jobStmt + ` GROUP BY j.id ORDER BY j.id DESC;`
+
+	if err := s.db.Select(&jobs, stmt); err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func (s *Store) CreateJob(j *dusk.Job) (*dusk.Job, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stmt := `INSERT INTO job (kind, state, dateCreated) VALUES ($1, $2, $3);`
+		res, err := tx.Exec(stmt, j.Kind, dusk.JobQueued, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to insert job: %w", err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to insert job: %w", err)
+		}
+
+		stmt = `INSERT INTO job_item (jobId, name, state, payload) VALUES ($1, $2, $3, $4);`
+		for _, item := range j.Items {
+			if _, err := tx.Exec(stmt, id, item.Name, dusk.ItemPending, item.Payload); err != nil {
+				return nil, fmt.Errorf("[db] failed to insert item of job %d: %w", id, err)
+			}
+		}
+
+		job, err := getJob(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		job.Items, err = getJobItems(tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to retrieve items of job %d: %w", id, err)
+		}
+		return job, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Job), nil
+}
+
+// UpdateJob updates the state, error and dates of a job.
+func (s *Store) UpdateJob(id int64, j *dusk.Job) (*dusk.Job, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stmt := `UPDATE job
+			SET state=$1, error=$2, dateStarted=$3, dateCompleted=$4
+			WHERE id=$5;`
+
+		res, err := tx.Exec(stmt, j.State, j.Error, j.DateStarted, j.DateCompleted, id)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to update job %d: %w", id, err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to update job %d: %w", id, err)
+		}
+		if count == 0 {
+			return nil, dusk.ErrDoesNotExist
+		}
+		return getJob(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Job), nil
+}
+
+// UpdateJobItem updates the state, error and book of a job item.
+func (s *Store) UpdateJobItem(id int64, item *dusk.JobItem) error {
+	stmt := `UPDATE job_item SET state=$1, error=$2, bookId=NULLIF($3, 0) WHERE id=$4;`
+
+	res, err := s.db.Exec(stmt, item.State, item.Error, item.BookId, id)
+	if err != nil {
+		return fmt.Errorf("[db] failed to update job item %d: %w", id, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("[db] failed to update job item %d: %w", id, err)
+	}
+	if count == 0 {
+		return dusk.ErrDoesNotExist
+	}
+	return nil
+}
+
+// RetryJob resets the failed items of a finished job and queues it again.
+func (s *Store) RetryJob(id int64) (*dusk.Job, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		job, err := getJob(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !job.Finished() || job.Failed == 0 {
+			return nil, dusk.ErrNoChange
+		}
+
+		stmt := `UPDATE job_item SET state=$1, error=NULL WHERE jobId=$2 AND state=$3;`
+		if _, err := tx.Exec(stmt, dusk.ItemPending, id, dusk.ItemFailed); err != nil {
+			return nil, fmt.Errorf("[db] failed to reset items of job %d: %w", id, err)
+		}
+
+		stmt = `UPDATE job SET state=$1, error=NULL, dateCompleted=NULL WHERE id=$2;`
+		if _, err := tx.Exec(stmt, dusk.JobQueued, id); err != nil {
+			return nil, fmt.Errorf("[db] failed to queue job %d: %w", id, err)
+		}
+		return getJob(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Job), nil
+}
+
+func (s *Store) DeleteJob(id int64) error {
+	_, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		res, err := tx.Exec(`DELETE FROM job WHERE id=$1;`, id)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete job %d: %w", id, err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete job %d: %w", id, err)
+		}
+		if count == 0 {
+			return nil, dusk.ErrDoesNotExist
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func getJob(tx *sqlx.Tx, id int64) (*dusk.Job, error) {
+	var job dusk.Job
+	stmt := jobStmt + ` WHERE j.id=$1 GROUP BY j.id;`
+
+	err := tx.QueryRowx(stmt, id).StructScan(&job)
+	if err == sql.ErrNoRows {
+		return nil, dusk.ErrDoesNotExist
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve job %d: %w", id, err)
+	}
+	return &job, nil
+}
+
+func getJobItems(tx *sqlx.Tx, jobId int64) ([]dusk.JobItem, error) {
+	var items []dusk.JobItem
+	stmt := `SELECT id, jobId, name, state, error, COALESCE(bookId, 0) AS bookId, payload
+		FROM job_item
+		WHERE jobId=$1
+		ORDER BY id;`
+
+	if err := tx.Select(&items, stmt, jobId); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
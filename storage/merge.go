@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/kencx/dusk"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// MergeBooks merges the book otherId into the book id, which is updated to b.
+// The reading sessions, copies, highlights, import sources and job items of
+// the other book are moved to the surviving book before the other book is deleted.
+func (s *Store) MergeBooks(id, otherId int64, b *dusk.Book) (*dusk.Book, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		if id == otherId {
+			return nil, fmt.Errorf("[db] failed to merge book %d: cannot merge book with itself", id)
+		}
+		if _, err := getBook(tx, id); err != nil {
+			return nil, err
+		}
+		if _, err := getBook(tx, otherId); err != nil {
+			return nil, err
+		}
+
+		// highlights and collections that the book already has are deleted
+		// with the other book
+		stmts := map[string]string{
+			"reading sessions": `UPDATE reading_session SET bookId=$1 WHERE bookId=$2;`,
+			"copies":           `UPDATE copy SET bookId=$1 WHERE bookId=$2;`,
+			"highlights":       `UPDATE OR IGNORE highlight SET bookId=$1 WHERE bookId=$2;`,
+			"collections":      `UPDATE OR IGNORE collection_book_link SET book=$1 WHERE book=$2;`,
+			"import sources":   `UPDATE import_source SET bookId=$1 WHERE bookId=$2;`,
+			"job items":        `UPDATE job_item SET bookId=$1 WHERE bookId=$2;`,
+		}
+		for name, stmt := range stmts {
+			if _, err := tx.Exec(stmt, id, otherId); err != nil {
+				return nil, fmt.Errorf("[db] failed to move %s of book %d to book %d: %w", name, otherId, id, err)
+			}
+		}
+
+		// the book keeps its own work, otherwise it takes the other book's work
+		stmt := `INSERT OR IGNORE INTO book_work_link (book, work)
+			SELECT $1, work FROM book_work_link WHERE book=$2;`
+		if _, err := tx.Exec(stmt, id, otherId); err != nil {
+			return nil, fmt.Errorf("[db] failed to move work of book %d to book %d: %w", otherId, id, err)
+		}
+
+		// the other book's isbns, identifiers and formats are deleted first so that they can
+		// be added to the surviving book
+		if err := deleteBook(tx, otherId); err != nil {
+			return nil, fmt.Errorf("[db] failed to delete book %d: %w", otherId, err)
+		}
+
+		b.Id = id
+		if err := updateBook(tx, id, b); err != nil {
+			return nil, fmt.Errorf("[db] failed to update book %d: %w", id, err)
+		}
+		if err := syncBookWithSessions(tx, id, b); err != nil {
+			return nil, fmt.Errorf("[db] %w", err)
+		}
+		if err := updateBookLinks(tx, id, b); err != nil {
+			return nil, err
+		}
+		if err := deleteWorksWithNoBooks(tx); err != nil {
+			return nil, fmt.Errorf("[db] %w", err)
+		}
+		return getBook(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Book), nil
+}
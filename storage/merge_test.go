@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/kencx/dusk"
+	"github.com/matryer/is"
+)
+
+func TestMergeBooksCollections(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	only, err := ts.CreateCollection(&dusk.Collection{Name: "only other"})
+	is.NoErr(err)
+	both, err := ts.CreateCollection(&dusk.Collection{Name: "both"})
+	is.NoErr(err)
+
+	_, err = ts.AddBookToCollection(only.Id, testBook3.Id)
+	is.NoErr(err)
+	_, err = ts.AddBookToCollection(both.Id, testBook1.Id)
+	is.NoErr(err)
+	_, err = ts.AddBookToCollection(both.Id, testBook3.Id)
+	is.NoErr(err)
+
+	b, err := ts.GetBook(testBook1.Id)
+	is.NoErr(err)
+	_, err = ts.MergeBooks(testBook1.Id, testBook3.Id, b)
+	is.NoErr(err)
+
+	got, err := ts.GetCollection(only.Id)
+	is.NoErr(err)
+	is.Equal(len(got.Books), 1)
+	is.Equal(got.Books[0].Id, testBook1.Id)
+
+	got, err = ts.GetCollection(both.Id)
+	is.NoErr(err)
+	is.Equal(len(got.Books), 1)
+	is.Equal(got.Books[0].Id, testBook1.Id)
+}
@@ -0,0 +1,400 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
+)
+
+var (
+	ErrSchemaTooNew = errors.New("db: database schema is newer than this binary, please upgrade dusk")
+
+	migrationFileRegex = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+	// migrationHooks are run after the up migration of the same version, in
+	// the same transaction, for data changes that cannot be written in SQL
+	migrationHooks = map[int]func(tx *sqlx.Tx) error{
+		8:  backfillAuthorSortNames,
+		18: normaliseTagNames,
+	}
+)
+
+// Migration is a single versioned change to the database schema, read from the
+// numbered files migrations/<version>_<name>.up.sql and
+// migrations/<version>_<name>.down.sql.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+	After   func(tx *sqlx.Tx) error
+}
+
+type MigrationStatus struct {
+	Version   int       `db:"version"`
+	Name      string    `db:"name"`
+	AppliedAt null.Time `db:"appliedAt"`
+}
+
+func (m MigrationStatus) Applied() bool {
+	return m.AppliedAt.Valid
+}
+
+func loadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFs, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("db: failed to read migrations: %w", err)
+	}
+
+	migrations := make(map[int]*Migration)
+	for _, entry := range entries {
+		matches := migrationFileRegex.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return nil, fmt.Errorf("db: invalid migration version %q: %w", entry.Name(), err)
+		}
+
+		stmt, err := migrationFs.ReadFile(fmt.Sprintf("migrations/%s", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("db: cannot read sql file %q: %w", entry.Name(), err)
+		}
+
+		m, ok := migrations[version]
+		if !ok {
+			m = &Migration{Version: version, Name: matches[2], After: migrationHooks[version]}
+			migrations[version] = m
+		} else if m.Name != matches[2] {
+			return nil, fmt.Errorf("db: conflicting migrations for version %d: %q and %q", version, m.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			m.Up = string(stmt)
+		} else {
+			m.Down = string(stmt)
+		}
+	}
+
+	var result []Migration
+	for _, m := range migrations {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("db: migration %d_%s must have both up and down files", m.Version, m.Name)
+		}
+		result = append(result, *m)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
+
+func (s *Store) createSchemaVersionTable() error {
+	stmt := `CREATE TABLE IF NOT EXISTS schema_version (
+        version   INTEGER NOT NULL PRIMARY KEY,
+        name      TEXT NOT NULL,
+        appliedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    );`
+
+	if _, err := s.db.Exec(stmt); err != nil {
+		return fmt.Errorf("db: failed to create schema_version table: %w", err)
+	}
+	return nil
+}
+
+// SchemaVersion returns the version of the latest migration applied to the
+// database, or 0 if no migrations have been applied.
+func (s *Store) SchemaVersion() (int, error) {
+	if err := s.createSchemaVersionTable(); err != nil {
+		return 0, err
+	}
+
+	var version int
+	if err := s.db.Get(&version, "SELECT COALESCE(MAX(version), 0) FROM schema_version;"); err != nil {
+		return 0, fmt.Errorf("db: failed to query schema version: %w", err)
+	}
+	return version, nil
+}
+
+// CheckSchemaVersion returns ErrSchemaTooNew if the database has been migrated
+// past the latest migration known to this binary.
+func (s *Store) CheckSchemaVersion() error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	version, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	var latest int
+	if len(migrations) > 0 {
+		latest = migrations[len(migrations)-1].Version
+	}
+
+	if version > latest {
+		return fmt.Errorf("%w (database: %d, binary: %d)", ErrSchemaTooNew, version, latest)
+	}
+	return nil
+}
+
+// MigrateUp applies all pending migrations in order. Each migration is applied
+// in its own transaction.
+func (s *Store) MigrateUp() error {
+	if err := s.CheckSchemaVersion(); err != nil {
+		return err
+	}
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	version, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.Version <= version {
+			continue
+		}
+
+		if err := s.applyMigration(m, true); err != nil {
+			return err
+		}
+		slog.Info("Applied migration", slog.Int("version", m.Version), slog.String("name", m.Name))
+	}
+
+	return nil
+}
+
+// MigrateDown reverts the given number of most recently applied migrations.
+func (s *Store) MigrateDown(steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("db: number of steps must be at least 1")
+	}
+
+	if err := s.CheckSchemaVersion(); err != nil {
+		return err
+	}
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	version, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+		m := migrations[i]
+		if m.Version > version {
+			continue
+		}
+
+		if err := s.applyMigration(m, false); err != nil {
+			return err
+		}
+		slog.Info("Reverted migration", slog.Int("version", m.Version), slog.String("name", m.Name))
+		steps--
+	}
+
+	return nil
+}
+
+// MigrationStatus lists all known migrations and when they were applied.
+func (s *Store) MigrationStatus() ([]MigrationStatus, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.createSchemaVersionTable(); err != nil {
+		return nil, err
+	}
+
+	var applied []MigrationStatus
+	if err := s.db.Select(&applied, "SELECT * FROM schema_version ORDER BY version;"); err != nil {
+		return nil, fmt.Errorf("db: failed to query schema version: %w", err)
+	}
+
+	appliedMap := make(map[int]MigrationStatus)
+	for _, a := range applied {
+		appliedMap[a.Version] = a
+	}
+
+	var result []MigrationStatus
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if a, ok := appliedMap[m.Version]; ok {
+			status.AppliedAt = a.AppliedAt
+			delete(appliedMap, m.Version)
+		}
+		result = append(result, status)
+	}
+
+	// migrations applied by a newer binary
+	for _, a := range appliedMap {
+		result = append(result, a)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
+
+// applyMigration runs a single migration statement, and its hook if applied
+// up, and records it in schema_version within one transaction. Foreign key
+// enforcement is disabled for the duration of the migration to allow tables to
+// be rebuilt, and the foreign keys are checked before commit instead.
+func (s *Store) applyMigration(m Migration, up bool) error {
+	ctx := context.Background()
+	version, name, stmt := m.Version, m.Name, m.Down
+	if up {
+		stmt = m.Up
+	}
+
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("db: failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	// foreign_keys is a no-op within a transaction
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF;"); err != nil {
+		return fmt.Errorf("db: failed to disable foreign keys: %w", err)
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON;")
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("db: failed to begin migration %d_%s: %w", version, name, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(stmt); err != nil {
+		return fmt.Errorf("db: failed to execute migration %d_%s: %w", version, name, err)
+	}
+
+	if up && m.After != nil {
+		if err := m.After(tx); err != nil {
+			return fmt.Errorf("db: failed to execute migration %d_%s: %w", version, name, err)
+		}
+	}
+
+	if err := checkForeignKeys(tx); err != nil {
+		return fmt.Errorf("db: migration %d_%s: %w", version, name, err)
+	}
+
+	if up {
+		_, err = tx.Exec("INSERT INTO schema_version (version, name) VALUES ($1, $2);", version, name)
+	} else {
+		_, err = tx.Exec("DELETE FROM schema_version WHERE version=$1;", version)
+	}
+	if err != nil {
+		return fmt.Errorf("db: failed to update schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("db: failed to commit migration %d_%s: %w", version, name, err)
+	}
+	return nil
+}
+
+func checkForeignKeys(tx *sqlx.Tx) error {
+	rows, err := tx.Query("PRAGMA foreign_key_check;")
+	if err != nil {
+		return fmt.Errorf("failed to check foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return errors.New("foreign key constraint violated")
+	}
+	return rows.Err()
+}
+
+// backfillAuthorSortNames sets the sort name of existing authors, which
+// depends on the name particles known to dusk.AuthorSortName
+func backfillAuthorSortNames(tx *sqlx.Tx) error {
+	var authors []struct {
+		Id   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	if err := tx.Select(&authors, `SELECT id, name FROM author;`); err != nil {
+		return fmt.Errorf("failed to retrieve authors: %w", err)
+	}
+
+	for _, a := range authors {
+		stmt := `UPDATE author SET sortName=$1 WHERE id=$2;`
+		if _, err := tx.Exec(stmt, dusk.AuthorSortName(a.Name), a.Id); err != nil {
+			return fmt.Errorf("failed to update sort name of author %d: %w", a.Id, err)
+		}
+	}
+	return nil
+}
+
+// normaliseTagNames lowercases the names of existing tags with
+// dusk.NormaliseTagName. Tags that only differ in case are merged into one.
+func normaliseTagNames(tx *sqlx.Tx) error {
+	var tags []struct {
+		Id   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	if err := tx.Select(&tags, `SELECT id, name FROM tag ORDER BY id;`); err != nil {
+		return fmt.Errorf("failed to retrieve tags: %w", err)
+	}
+
+	for _, t := range tags {
+		name := dusk.NormaliseTagName(t.Name)
+		if name == t.Name {
+			continue
+		}
+
+		var id int64
+		err := tx.Get(&id, `SELECT id FROM tag WHERE name=$1;`, name)
+		if errors.Is(err, sql.ErrNoRows) {
+			if _, err := tx.Exec(`UPDATE tag SET name=$1 WHERE id=$2;`, name, t.Id); err != nil {
+				return fmt.Errorf("failed to rename tag %d: %w", t.Id, err)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to retrieve tag %q: %w", name, err)
+		}
+
+		stmt := `INSERT OR IGNORE INTO book_tag_link (book, tag)
+			SELECT book, $1 FROM book_tag_link WHERE tag=$2;`
+		if _, err := tx.Exec(stmt, id, t.Id); err != nil {
+			return fmt.Errorf("failed to merge tag %d into tag %d: %w", t.Id, id, err)
+		}
+
+		// foreign keys are disabled, so the links are not deleted with the tag
+		if _, err := tx.Exec(`DELETE FROM book_tag_link WHERE tag=$1;`, t.Id); err != nil {
+			return fmt.Errorf("failed to merge tag %d into tag %d: %w", t.Id, id, err)
+		}
+		if _, err := tx.Exec(`DELETE FROM tag WHERE id=$1;`, t.Id); err != nil {
+			return fmt.Errorf("failed to merge tag %d into tag %d: %w", t.Id, id, err)
+		}
+	}
+	return nil
+}
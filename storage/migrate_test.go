@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/kencx/dusk"
+	"github.com/matryer/is"
+)
+
+// newMigrateStore opens an empty database, separate from the test store
+func newMigrateStore(t *testing.T) *Store {
+	t.Helper()
+
+	db, err := Open(filepath.Join(t.TempDir(), "migrate.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return New(db)
+}
+
+func latestMigration(t *testing.T) int {
+	t.Helper()
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+func TestMigrateRoundTrip(t *testing.T) {
+	is := is.New(t)
+	s := newMigrateStore(t)
+	latest := latestMigration(t)
+
+	is.NoErr(s.MigrateUp())
+	b, err := s.CreateBook(&dusk.Book{
+		Title:     "Book 1",
+		Author:    []string{"Ludwig van Beethoven"},
+		Ownership: dusk.Wishlisted,
+	})
+	is.NoErr(err)
+
+	// revert to before author sort names, rebuilding the book and author
+	// tables on the way
+	is.NoErr(s.MigrateDown(latest - 7))
+	version, err := s.SchemaVersion()
+	is.NoErr(err)
+	is.Equal(version, 7)
+
+	var got struct {
+		Id           int64  `db:"id"`
+		Title        string `db:"title"`
+		AuthorString string `db:"author_string"`
+	}
+	is.NoErr(s.db.Get(&got, `SELECT id, title, author_string FROM book_view;`))
+	is.Equal(got.Id, b.Id)
+	is.Equal(got.Title, b.Title)
+	is.Equal(got.AuthorString, "Ludwig van Beethoven")
+
+	// the rebuilt tables are still indexed
+	var count int
+	is.NoErr(s.db.Get(&count, `SELECT COUNT(*) FROM author_fts WHERE author_fts MATCH 'Beethoven';`))
+	is.Equal(count, 1)
+
+	is.NoErr(s.MigrateUp())
+	book, err := s.GetBook(b.Id)
+	is.NoErr(err)
+	is.Equal(book.Ownership, dusk.Owned)
+
+	// sort names are backfilled again
+	authors, err := s.GetAuthorsFromBook(book.Id)
+	is.NoErr(err)
+	is.Equal(authors[0].SortName, "van Beethoven, Ludwig")
+
+	// all the way down and back up
+	is.NoErr(s.MigrateDown(latest))
+	version, err = s.SchemaVersion()
+	is.NoErr(err)
+	is.Equal(version, 0)
+	is.NoErr(s.MigrateUp())
+}
+
+func TestMigrationStatus(t *testing.T) {
+	is := is.New(t)
+	s := newMigrateStore(t)
+	latest := latestMigration(t)
+
+	statuses, err := s.MigrationStatus()
+	is.NoErr(err)
+	is.Equal(len(statuses), latest)
+	for _, status := range statuses {
+		is.True(!status.Applied())
+	}
+
+	is.NoErr(s.MigrateUp())
+	is.NoErr(s.MigrateDown(1))
+
+	statuses, err = s.MigrationStatus()
+	is.NoErr(err)
+	for _, status := range statuses[:latest-1] {
+		is.True(status.Applied())
+	}
+	is.Equal(statuses[latest-1].Version, latest)
+	is.True(!statuses[latest-1].Applied())
+}
+
+func TestErrSchemaTooNew(t *testing.T) {
+	is := is.New(t)
+	s := newMigrateStore(t)
+	latest := latestMigration(t)
+
+	is.NoErr(s.MigrateUp())
+	_, err := s.db.Exec(`INSERT INTO schema_version (version, name) VALUES ($1, 'future');`, latest+1)
+	is.NoErr(err)
+
+	is.True(errors.Is(s.MigrateUp(), ErrSchemaTooNew))
+	is.True(errors.Is(s.MigrateDown(1), ErrSchemaTooNew))
+
+	// migrations applied by a newer binary are listed last
+	statuses, err := s.MigrationStatus()
+	is.NoErr(err)
+	is.Equal(len(statuses), latest+1)
+	is.Equal(statuses[latest].Name, "future")
+	is.True(statuses[latest].Applied())
+}
@@ -0,0 +1,26 @@
+DROP TRIGGER IF EXISTS tag_fts_after_delete;
+DROP TRIGGER IF EXISTS tag_fts_after_update;
+DROP TRIGGER IF EXISTS tag_fts_after_insert;
+DROP TABLE IF EXISTS tag_fts;
+
+DROP TRIGGER IF EXISTS author_fts_after_delete;
+DROP TRIGGER IF EXISTS author_fts_after_update;
+DROP TRIGGER IF EXISTS author_fts_after_insert;
+DROP TABLE IF EXISTS author_fts;
+
+DROP TRIGGER IF EXISTS book_fts_after_delete;
+DROP TRIGGER IF EXISTS book_fts_after_update;
+DROP TRIGGER IF EXISTS book_fts_after_insert;
+DROP TABLE IF EXISTS book_fts;
+
+DROP VIEW IF EXISTS book_view;
+
+DROP TABLE IF EXISTS format;
+DROP TABLE IF EXISTS isbn13;
+DROP TABLE IF EXISTS isbn10;
+DROP TABLE IF EXISTS series;
+DROP TABLE IF EXISTS book_tag_link;
+DROP TABLE IF EXISTS tag;
+DROP TABLE IF EXISTS book_author_link;
+DROP TABLE IF EXISTS author;
+DROP TABLE IF EXISTS book;
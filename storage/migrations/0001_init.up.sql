@@ -1,3 +1,6 @@
+-- Tables are created with IF NOT EXISTS so that databases created before
+-- versioned migrations were introduced can be adopted as version 1.
+
 CREATE TABLE IF NOT EXISTS book (
     id            INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
     title         TEXT NOT NULL,
@@ -0,0 +1,38 @@
+DROP VIEW IF EXISTS book_view;
+
+-- M to 1
+CREATE TABLE series_old (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    bookId INTEGER NOT NULL REFERENCES book(id) ON DELETE CASCADE,
+    name TEXT NOT NULL
+);
+
+INSERT INTO series_old (bookId, name)
+    SELECT bs.book, s.name FROM book_series_link bs
+    JOIN series s ON s.id=bs.series
+    ORDER BY bs.rowid;
+
+DROP TABLE book_series_link;
+DROP TABLE series;
+
+ALTER TABLE series_old RENAME TO series;
+
+CREATE VIEW book_view AS
+    SELECT b.*,
+    GROUP_CONCAT(DISTINCT a.name) AS author_string,
+    GROUP_CONCAT(DISTINCT t.name) AS tag_string,
+    GROUP_CONCAT(DISTINCT it.isbn) AS isbn10_string,
+    GROUP_CONCAT(DISTINCT ith.isbn) AS isbn13_string,
+    GROUP_CONCAT(DISTINCT f.filepath) AS format_string,
+    s.Name AS series_string
+    FROM book b
+        INNER JOIN book_author_link ba ON ba.book=b.id
+        INNER JOIN author a ON ba.author=a.id
+        LEFT JOIN  book_tag_link bt ON b.id=bt.book
+        LEFT JOIN  tag t ON bt.tag=t.id
+        LEFT JOIN  isbn10 it ON it.bookId=b.id
+        LEFT JOIN  isbn13 ith ON ith.bookId=b.id
+        LEFT JOIN  format f ON f.bookId=b.id
+        LEFT JOIN  series s ON s.bookId=b.id
+    GROUP BY b.id
+    ORDER BY b.id;
@@ -0,0 +1,54 @@
+-- series are linked to books through book_series_link instead of
+-- having one series row per book
+DROP VIEW IF EXISTS book_view;
+
+ALTER TABLE series RENAME TO series_old;
+
+CREATE TABLE series (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE
+);
+
+-- M to M
+CREATE TABLE book_series_link (
+    book INTEGER NOT NULL REFERENCES book(id) ON DELETE CASCADE,
+    series INTEGER NOT NULL REFERENCES series(id) ON DELETE CASCADE,
+    position REAL,
+    PRIMARY KEY(book, series)
+);
+
+INSERT INTO series (name)
+    SELECT name FROM series_old GROUP BY name ORDER BY MIN(id);
+
+INSERT OR IGNORE INTO book_series_link (book, series)
+    SELECT so.bookId, s.id FROM series_old so
+    JOIN series s ON s.name=so.name
+    ORDER BY so.id;
+
+DROP TABLE series_old;
+
+-- a book's primary series is the first series it was linked to
+CREATE VIEW book_view AS
+    SELECT b.*,
+    GROUP_CONCAT(DISTINCT a.name) AS author_string,
+    GROUP_CONCAT(DISTINCT t.name) AS tag_string,
+    GROUP_CONCAT(DISTINCT it.isbn) AS isbn10_string,
+    GROUP_CONCAT(DISTINCT ith.isbn) AS isbn13_string,
+    GROUP_CONCAT(DISTINCT f.filepath) AS format_string,
+    (SELECT s.name FROM book_series_link bs
+        JOIN series s ON s.id=bs.series
+        WHERE bs.book=b.id
+        ORDER BY bs.rowid LIMIT 1) AS series_string,
+    (SELECT bs.position FROM book_series_link bs
+        WHERE bs.book=b.id
+        ORDER BY bs.rowid LIMIT 1) AS series_position
+    FROM book b
+        INNER JOIN book_author_link ba ON ba.book=b.id
+        INNER JOIN author a ON ba.author=a.id
+        LEFT JOIN  book_tag_link bt ON b.id=bt.book
+        LEFT JOIN  tag t ON bt.tag=t.id
+        LEFT JOIN  isbn10 it ON it.bookId=b.id
+        LEFT JOIN  isbn13 ith ON ith.bookId=b.id
+        LEFT JOIN  format f ON f.bookId=b.id
+    GROUP BY b.id
+    ORDER BY b.id;
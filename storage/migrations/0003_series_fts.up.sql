@@ -0,0 +1,17 @@
+CREATE VIRTUAL TABLE IF NOT EXISTS series_fts
+	USING fts5(name, tokenize = trigram, content = 'series', content_rowid = 'id');
+
+INSERT INTO series_fts (rowid, name) SELECT id, name FROM series;
+
+CREATE TRIGGER IF NOT EXISTS series_fts_after_insert AFTER INSERT ON series BEGIN
+	INSERT INTO series_fts (rowid, name) VALUES (new.id, new.name);
+END;
+
+CREATE TRIGGER IF NOT EXISTS series_fts_after_update AFTER UPDATE ON series BEGIN
+  INSERT INTO series_fts (series_fts, rowid, name) VALUES ('delete', old.id, old.name);
+  INSERT INTO series_fts (rowid, name) VALUES (new.id, new.name);
+END;
+
+CREATE TRIGGER IF NOT EXISTS series_fts_after_delete AFTER DELETE ON series BEGIN
+  INSERT INTO series_fts (series_fts, rowid, name) VALUES ('delete', old.id, old.name);
+END;
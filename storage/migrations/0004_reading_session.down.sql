@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS reading_progress;
+DROP TABLE IF EXISTS reading_session;
@@ -0,0 +1,33 @@
+-- A book may be read multiple times. Each read is recorded as a session and
+-- the status, dates and progress columns of book are kept in sync with its
+-- latest session.
+CREATE TABLE IF NOT EXISTS reading_session (
+    id            INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    bookId        INTEGER NOT NULL REFERENCES book(id) ON DELETE CASCADE,
+    state         INTEGER NOT NULL DEFAULT (0) CHECK( state IN (0,1,2) ),
+    dateStarted   TIMESTAMP,
+    dateCompleted TIMESTAMP,
+    notes         TEXT
+);
+
+CREATE TABLE IF NOT EXISTS reading_progress (
+    id        INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    sessionId INTEGER NOT NULL REFERENCES reading_session(id) ON DELETE CASCADE,
+    progress  INTEGER NOT NULL CHECK( progress BETWEEN 0 AND 100 ),
+    timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- existing reads become the first session of their book
+INSERT INTO reading_session (bookId, state, dateStarted, dateCompleted)
+    SELECT id,
+        CASE status WHEN 2 THEN 1 ELSE 0 END,
+        dateStarted,
+        CASE status WHEN 2 THEN dateCompleted END
+    FROM book
+    WHERE status != 0;
+
+INSERT INTO reading_progress (sessionId, progress, timestamp)
+    SELECT rs.id, MIN(b.progress, 100), COALESCE(b.dateStarted, CURRENT_TIMESTAMP)
+    FROM reading_session rs
+        INNER JOIN book b ON b.id=rs.bookId
+    WHERE b.status=1 AND b.progress > 0;
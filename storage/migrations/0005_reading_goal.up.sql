@@ -0,0 +1,6 @@
+-- A target of 0 is not set
+CREATE TABLE IF NOT EXISTS reading_goal (
+    year  INTEGER NOT NULL PRIMARY KEY,
+    books INTEGER NOT NULL DEFAULT 0 CHECK( books >= 0 ),
+    pages INTEGER NOT NULL DEFAULT 0 CHECK( pages >= 0 )
+);
@@ -0,0 +1,8 @@
+-- Books imported from another library are recorded with their id in the
+-- source so that re-running an import skips them.
+CREATE TABLE IF NOT EXISTS import_source (
+    source   TEXT NOT NULL,
+    sourceId TEXT NOT NULL,
+    bookId   INTEGER NOT NULL REFERENCES book(id) ON DELETE CASCADE,
+    PRIMARY KEY (source, sourceId)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS job_item_job_idx;
+DROP TABLE IF EXISTS job_item;
+DROP TABLE IF EXISTS job;
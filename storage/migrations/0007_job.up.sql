@@ -0,0 +1,23 @@
+-- Background jobs are persisted so that they can be resumed after a restart
+-- and their failed items retried.
+CREATE TABLE IF NOT EXISTS job (
+    id            INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    kind          TEXT NOT NULL,
+    state         INTEGER NOT NULL DEFAULT (0) CHECK( state IN (0,1,2,3) ),
+    error         TEXT,
+    dateCreated   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dateStarted   TIMESTAMP,
+    dateCompleted TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS job_item (
+    id      INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    jobId   INTEGER NOT NULL REFERENCES job(id) ON DELETE CASCADE,
+    name    TEXT NOT NULL DEFAULT '',
+    state   INTEGER NOT NULL DEFAULT (0) CHECK( state IN (0,1,2,3) ),
+    error   TEXT,
+    bookId  INTEGER REFERENCES book(id) ON DELETE SET NULL,
+    payload BLOB
+);
+
+CREATE INDEX IF NOT EXISTS job_item_job_idx ON job_item (jobId);
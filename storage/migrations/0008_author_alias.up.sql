@@ -0,0 +1,13 @@
+-- Authors are listed by their sort name in "Last, First" form. Existing
+-- authors are backfilled after this migration with dusk.AuthorSortName, as
+-- name particles such as "van" or "de" are kept with the last name.
+ALTER TABLE author ADD COLUMN sortName TEXT NOT NULL DEFAULT '';
+
+-- Other spellings of an author's name. Incoming names are matched against
+-- these so that they are linked to the existing author.
+CREATE TABLE IF NOT EXISTS author_alias (
+    name     TEXT NOT NULL PRIMARY KEY COLLATE NOCASE,
+    authorId INTEGER NOT NULL REFERENCES author(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS author_alias_author_idx ON author_alias (authorId);
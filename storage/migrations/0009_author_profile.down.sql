@@ -0,0 +1,58 @@
+DROP INDEX IF EXISTS author_link_author_idx;
+DROP TABLE IF EXISTS author_link;
+DROP TABLE IF EXISTS author_identifier;
+
+-- the profile columns are removed by rebuilding author, as DROP COLUMN is
+-- only supported from SQLite 3.35
+DROP VIEW IF EXISTS book_view;
+
+CREATE TABLE author_new (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    sortName TEXT NOT NULL DEFAULT ''
+);
+
+INSERT INTO author_new (id, name, sortName)
+    SELECT id, name, sortName FROM author;
+
+DROP TABLE author;
+
+ALTER TABLE author_new RENAME TO author;
+
+CREATE TRIGGER author_fts_after_insert AFTER INSERT ON author BEGIN
+	INSERT INTO author_fts (rowid, name) VALUES (new.id, new.name);
+END;
+
+CREATE TRIGGER author_fts_after_update AFTER UPDATE ON author BEGIN
+  INSERT INTO author_fts (author_fts, rowid, name) VALUES ('delete', old.id, old.name);
+  INSERT INTO author_fts (rowid, name) VALUES (new.id, new.name);
+END;
+
+CREATE TRIGGER author_fts_after_delete AFTER DELETE ON author BEGIN
+  INSERT INTO author_fts (author_fts, rowid, name) VALUES ('delete', old.id, old.name);
+END;
+
+CREATE VIEW book_view AS
+    SELECT b.*,
+    GROUP_CONCAT(DISTINCT a.name) AS author_string,
+    GROUP_CONCAT(DISTINCT t.name) AS tag_string,
+    GROUP_CONCAT(DISTINCT it.isbn) AS isbn10_string,
+    GROUP_CONCAT(DISTINCT ith.isbn) AS isbn13_string,
+    GROUP_CONCAT(DISTINCT f.filepath) AS format_string,
+    (SELECT s.name FROM book_series_link bs
+        JOIN series s ON s.id=bs.series
+        WHERE bs.book=b.id
+        ORDER BY bs.rowid LIMIT 1) AS series_string,
+    (SELECT bs.position FROM book_series_link bs
+        WHERE bs.book=b.id
+        ORDER BY bs.rowid LIMIT 1) AS series_position
+    FROM book b
+        INNER JOIN book_author_link ba ON ba.book=b.id
+        INNER JOIN author a ON ba.author=a.id
+        LEFT JOIN  book_tag_link bt ON b.id=bt.book
+        LEFT JOIN  tag t ON bt.tag=t.id
+        LEFT JOIN  isbn10 it ON it.bookId=b.id
+        LEFT JOIN  isbn13 ith ON ith.bookId=b.id
+        LEFT JOIN  format f ON f.bookId=b.id
+    GROUP BY b.id
+    ORDER BY b.id;
@@ -0,0 +1,21 @@
+ALTER TABLE author ADD COLUMN bio TEXT;
+ALTER TABLE author ADD COLUMN birthDate TEXT;
+ALTER TABLE author ADD COLUMN deathDate TEXT;
+ALTER TABLE author ADD COLUMN photo TEXT;
+
+-- ids of an author in external sources, e.g. openlibrary, wikidata
+CREATE TABLE IF NOT EXISTS author_identifier (
+    authorId INTEGER NOT NULL REFERENCES author(id) ON DELETE CASCADE,
+    source   TEXT NOT NULL,
+    value    TEXT NOT NULL,
+    PRIMARY KEY (authorId, source)
+);
+
+CREATE TABLE IF NOT EXISTS author_link (
+    id       INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    authorId INTEGER NOT NULL REFERENCES author(id) ON DELETE CASCADE,
+    title    TEXT NOT NULL DEFAULT '',
+    url      TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS author_link_author_idx ON author_link (authorId);
@@ -0,0 +1,8 @@
+CREATE VIEW IF NOT EXISTS tag_view AS
+SELECT
+    t.id,
+    t.name,
+    COUNT(bt.book) AS bookCount
+FROM tag t
+    LEFT JOIN book_tag_link bt ON bt.tag=t.id
+GROUP BY t.id;
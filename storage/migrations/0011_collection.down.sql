@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS collection_book_link_book_idx;
+DROP TABLE IF EXISTS collection_book_link;
+DROP TABLE IF EXISTS collection;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS collection (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    description TEXT
+);
+
+-- position is the manual order of books in the collection
+CREATE TABLE IF NOT EXISTS collection_book_link (
+    collection INTEGER NOT NULL REFERENCES collection(id) ON DELETE CASCADE,
+    book INTEGER NOT NULL REFERENCES book(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY(collection, book)
+);
+
+CREATE INDEX IF NOT EXISTS collection_book_link_book_idx ON collection_book_link(book);
@@ -0,0 +1,6 @@
+-- query is a library search query, see the query package
+CREATE TABLE IF NOT EXISTS smart_collection (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    query TEXT NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS identifier_book_idx;
+DROP TABLE IF EXISTS identifier;
@@ -0,0 +1,10 @@
+-- ids of a book in external sources, e.g. goodreads, asin, openlibrary. An id
+-- belongs to a single book within its scheme.
+CREATE TABLE IF NOT EXISTS identifier (
+    scheme TEXT NOT NULL,
+    value  TEXT NOT NULL,
+    bookId INTEGER NOT NULL REFERENCES book(id) ON DELETE CASCADE,
+    PRIMARY KEY (scheme, value)
+);
+
+CREATE INDEX IF NOT EXISTS identifier_book_idx ON identifier (bookId);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS book_work_link_work_idx;
+DROP TABLE IF EXISTS book_work_link;
+DROP TABLE IF EXISTS work;
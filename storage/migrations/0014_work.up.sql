@@ -0,0 +1,15 @@
+-- openlibrary is the Open Library work key, which is shared by all editions
+CREATE TABLE IF NOT EXISTS work (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL,
+    rating INTEGER NOT NULL DEFAULT 0,
+    openlibrary TEXT UNIQUE
+);
+
+-- a book is an edition of at most one work
+CREATE TABLE IF NOT EXISTS book_work_link (
+    book INTEGER NOT NULL PRIMARY KEY REFERENCES book(id) ON DELETE CASCADE,
+    work INTEGER NOT NULL REFERENCES work(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS book_work_link_work_idx ON book_work_link(work);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS loan_active_idx;
+DROP INDEX IF EXISTS loan_copy_idx;
+DROP TABLE IF EXISTS loan;
+DROP INDEX IF EXISTS copy_book_idx;
+DROP TABLE IF EXISTS copy;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS copy (
+    id           INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    bookId       INTEGER NOT NULL REFERENCES book(id) ON DELETE CASCADE,
+    condition    TEXT CHECK( condition IN ('new','fine','good','fair','poor') ),
+    location     TEXT,
+    dateAcquired TIMESTAMP,
+    price        REAL CHECK( price >= 0 ),
+    notes        TEXT
+);
+
+CREATE INDEX IF NOT EXISTS copy_book_idx ON copy(bookId);
+
+CREATE TABLE IF NOT EXISTS loan (
+    id           INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    copyId       INTEGER NOT NULL REFERENCES copy(id) ON DELETE CASCADE,
+    borrower     TEXT NOT NULL,
+    dateLent     TIMESTAMP NOT NULL,
+    dateDue      TIMESTAMP,
+    dateReturned TIMESTAMP,
+    notes        TEXT
+);
+
+CREATE INDEX IF NOT EXISTS loan_copy_idx ON loan(copyId);
+
+-- a copy can only be lent to one borrower at a time
+CREATE UNIQUE INDEX IF NOT EXISTS loan_active_idx ON loan(copyId) WHERE dateReturned IS NULL;
@@ -0,0 +1,77 @@
+DROP TABLE IF EXISTS wishlist;
+
+-- rebuild book without the ownership column, as DROP COLUMN requires
+-- SQLite 3.35
+DROP VIEW IF EXISTS book_view;
+
+CREATE TABLE book_new (
+    id            INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    title         TEXT NOT NULL,
+    subtitle      TEXT,
+
+    numOfPages    INTEGER DEFAULT 0,
+    progress      INTEGER DEFAULT 0,
+    rating        INTEGER DEFAULT 0,
+    status        INTEGER NOT NULL DEFAULT (0) CHECK( status IN (0,1,2) ),
+
+    publisher     TEXT,
+    datePublished TIMESTAMP,
+
+    description   TEXT,
+    notes         TEXT,
+    cover         TEXT,
+
+    dateStarted   TIMESTAMP,
+    dateCompleted TIMESTAMP,
+    dateAdded     TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO book_new (id, title, subtitle, numOfPages, progress, rating, status,
+    publisher, datePublished, description, notes, cover, dateStarted,
+    dateCompleted, dateAdded)
+    SELECT id, title, subtitle, numOfPages, progress, rating, status,
+        publisher, datePublished, description, notes, cover, dateStarted,
+        dateCompleted, dateAdded
+    FROM book;
+
+DROP TABLE book;
+
+ALTER TABLE book_new RENAME TO book;
+
+CREATE TRIGGER book_fts_after_insert AFTER INSERT ON book BEGIN
+	INSERT INTO book_fts (rowid, title, subtitle) VALUES (new.id, new.title, new.subtitle);
+END;
+
+CREATE TRIGGER book_fts_after_update AFTER UPDATE ON book BEGIN
+  INSERT INTO book_fts (book_fts, rowid, title, subtitle) VALUES ('delete', old.id, old.title, old.subtitle);
+  INSERT INTO book_fts (rowid, title, subtitle) VALUES (new.id, new.title, new.subtitle);
+END;
+
+CREATE TRIGGER book_fts_after_delete AFTER DELETE ON book BEGIN
+  INSERT INTO book_fts (book_fts, rowid, title, subtitle) VALUES ('delete', old.id, old.title, old.subtitle);
+END;
+
+CREATE VIEW book_view AS
+    SELECT b.*,
+    GROUP_CONCAT(DISTINCT a.name) AS author_string,
+    GROUP_CONCAT(DISTINCT t.name) AS tag_string,
+    GROUP_CONCAT(DISTINCT it.isbn) AS isbn10_string,
+    GROUP_CONCAT(DISTINCT ith.isbn) AS isbn13_string,
+    GROUP_CONCAT(DISTINCT f.filepath) AS format_string,
+    (SELECT s.name FROM book_series_link bs
+        JOIN series s ON s.id=bs.series
+        WHERE bs.book=b.id
+        ORDER BY bs.rowid LIMIT 1) AS series_string,
+    (SELECT bs.position FROM book_series_link bs
+        WHERE bs.book=b.id
+        ORDER BY bs.rowid LIMIT 1) AS series_position
+    FROM book b
+        INNER JOIN book_author_link ba ON ba.book=b.id
+        INNER JOIN author a ON ba.author=a.id
+        LEFT JOIN  book_tag_link bt ON b.id=bt.book
+        LEFT JOIN  tag t ON bt.tag=t.id
+        LEFT JOIN  isbn10 it ON it.bookId=b.id
+        LEFT JOIN  isbn13 ith ON ith.bookId=b.id
+        LEFT JOIN  format f ON f.bookId=b.id
+    GROUP BY b.id
+    ORDER BY b.id;
@@ -0,0 +1,10 @@
+-- existing books are owned
+ALTER TABLE book ADD COLUMN ownership INTEGER NOT NULL DEFAULT (0) CHECK( ownership IN (0,1,2,3) );
+
+-- details of books on the wishlist, removed once they are owned
+CREATE TABLE IF NOT EXISTS wishlist (
+    bookId   INTEGER NOT NULL PRIMARY KEY REFERENCES book(id) ON DELETE CASCADE,
+    price    REAL CHECK( price >= 0 ),
+    priority INTEGER NOT NULL DEFAULT (0) CHECK( priority BETWEEN 0 AND 3 ),
+    notes    TEXT
+);
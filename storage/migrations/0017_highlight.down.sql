@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS highlight_fts_after_delete;
+DROP TRIGGER IF EXISTS highlight_fts_after_update;
+DROP TRIGGER IF EXISTS highlight_fts_after_insert;
+DROP TABLE IF EXISTS highlight_fts;
+DROP INDEX IF EXISTS highlight_unique_idx;
+DROP INDEX IF EXISTS highlight_book_idx;
+DROP TABLE IF EXISTS highlight;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS highlight (
+    id              INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    bookId          INTEGER NOT NULL REFERENCES book(id) ON DELETE CASCADE,
+    text            TEXT NOT NULL DEFAULT '',
+    page            INTEGER NOT NULL DEFAULT (0) CHECK( page >= 0 ),
+    location        TEXT,
+    chapter         TEXT,
+    note            TEXT,
+    dateHighlighted TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS highlight_book_idx ON highlight(bookId);
+
+-- the same highlight is only imported once
+CREATE UNIQUE INDEX IF NOT EXISTS highlight_unique_idx ON highlight(bookId, text, page, COALESCE(location, ''));
+
+CREATE VIRTUAL TABLE IF NOT EXISTS highlight_fts
+	USING fts5(text, note, tokenize = trigram, content = 'highlight', content_rowid = 'id');
+
+CREATE TRIGGER IF NOT EXISTS highlight_fts_after_insert AFTER INSERT ON highlight BEGIN
+	INSERT INTO highlight_fts (rowid, text, note) VALUES (new.id, new.text, new.note);
+END;
+
+CREATE TRIGGER IF NOT EXISTS highlight_fts_after_update AFTER UPDATE ON highlight BEGIN
+  INSERT INTO highlight_fts (highlight_fts, rowid, text, note) VALUES ('delete', old.id, old.text, old.note);
+  INSERT INTO highlight_fts (rowid, text, note) VALUES (new.id, new.text, new.note);
+END;
+
+CREATE TRIGGER IF NOT EXISTS highlight_fts_after_delete AFTER DELETE ON highlight BEGIN
+  INSERT INTO highlight_fts (highlight_fts, rowid, text, note) VALUES ('delete', old.id, old.text, old.note);
+END;
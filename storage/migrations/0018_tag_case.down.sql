@@ -0,0 +1,2 @@
+-- the original case of tag names is not kept
+SELECT 1;
@@ -0,0 +1,4 @@
+-- Tags are matched regardless of case. Existing tag names are lowercased
+-- after this migration with dusk.NormaliseTagName, merging tags that only
+-- differ in case.
+SELECT 1;
@@ -1,18 +1,49 @@
 DELETE FROM book;
 DELETE FROM author;
+DELETE FROM author_alias;
+DELETE FROM author_identifier;
+DELETE FROM author_link;
 DELETE FROM book_author_link;
 DELETE FROM tag;
 DELETE FROM book_tag_link;
 DELETE FROM series;
+DELETE FROM book_series_link;
+DELETE FROM collection;
+DELETE FROM collection_book_link;
+DELETE FROM smart_collection;
+DELETE FROM work;
+DELETE FROM book_work_link;
 DELETE FROM isbn10;
 DELETE FROM isbn13;
+DELETE FROM identifier;
 DELETE FROM format;
+DELETE FROM copy;
+DELETE FROM loan;
+DELETE FROM wishlist;
+DELETE FROM highlight;
+DELETE FROM reading_session;
+DELETE FROM reading_progress;
+DELETE FROM reading_goal;
+DELETE FROM import_source;
+DELETE FROM job_item;
+DELETE FROM job;
 
 -- reset autoincrement
 DELETE FROM SQLITE_SEQUENCE WHERE name='book';
 DELETE FROM SQLITE_SEQUENCE WHERE name='author';
+DELETE FROM SQLITE_SEQUENCE WHERE name='author_link';
 DELETE FROM SQLITE_SEQUENCE WHERE name='tag';
 DELETE FROM SQLITE_SEQUENCE WHERE name='series';
+DELETE FROM SQLITE_SEQUENCE WHERE name='collection';
+DELETE FROM SQLITE_SEQUENCE WHERE name='smart_collection';
+DELETE FROM SQLITE_SEQUENCE WHERE name='work';
 DELETE FROM SQLITE_SEQUENCE WHERE name='isbn10';
 DELETE FROM SQLITE_SEQUENCE WHERE name='isbn13';
 DELETE FROM SQLITE_SEQUENCE WHERE name='format';
+DELETE FROM SQLITE_SEQUENCE WHERE name='copy';
+DELETE FROM SQLITE_SEQUENCE WHERE name='loan';
+DELETE FROM SQLITE_SEQUENCE WHERE name='highlight';
+DELETE FROM SQLITE_SEQUENCE WHERE name='reading_session';
+DELETE FROM SQLITE_SEQUENCE WHERE name='reading_progress';
+DELETE FROM SQLITE_SEQUENCE WHERE name='job';
+DELETE FROM SQLITE_SEQUENCE WHERE name='job_item';
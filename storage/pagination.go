@@ -20,8 +20,8 @@ var (
 	`
 	pagedStmt = fmt.Sprintf(`WITH paginate AS (%s)
 		SELECT * FROM paginate
-		WHERE rowno > $2
-		LIMIT $3;`,
+		WHERE rowno > {{.After}}
+		LIMIT {{.Limit}};`,
 		baseStmt,
 	)
 )
@@ -37,11 +37,19 @@ func buildBaseStmt(sortColumn, sortDirection, table, conditional string) string
 }
 
 func buildPagedStmt(f *filters.Base, table, conditional string) string {
+	return buildPagedStmtWithParams(f, table, conditional, 1)
+}
+
+// buildPagedStmtWithParams builds a paged statement whose conditional binds n
+// params. The after and limit params follow as $n+1 and $n+2.
+func buildPagedStmtWithParams(f *filters.Base, table, conditional string, n int) string {
 	data := map[string]interface{}{
 		"SortColumn":    f.SortColumn(),
 		"SortDirection": f.SortDirection,
 		"Table":         table,
 		"Conditional":   conditional,
+		"After":         fmt.Sprintf("$%d", n+1),
+		"Limit":         fmt.Sprintf("$%d", n+2),
 	}
 	return Tprintf(pagedStmt, data)
 }
@@ -55,7 +63,9 @@ func Tprintf(tmpl string, data map[string]interface{}) string {
 	return buf.String()
 }
 
-func buildSearchQuery(table string, f *filters.Search) (string, []any) {
+// buildSearchQuery builds a search query of the table, which may be a view of
+// the indexed table fts
+func buildSearchQuery(table, fts string, f *filters.Search) (string, []any) {
 	var (
 		conditional = "WHERE $1"
 		params      = []any{"1"}
@@ -67,10 +77,7 @@ func buildSearchQuery(table string, f *filters.Search) (string, []any) {
 
 	// non-empty search query
 	if f.Search != "" {
-		conditional = fmt.Sprintf(
-			`WHERE id IN (SELECT rowid FROM %[1]s_fts WHERE %[1]s_fts MATCH $1)`,
-			table,
-		)
+		conditional = fmt.Sprintf(`WHERE id IN (SELECT rowid FROM %[1]s_fts WHERE %[1]s_fts MATCH $1)`, fts)
 		// escape search params
 		params = []any{fmt.Sprintf(`"%s"`, f.Search)}
 	}
@@ -79,63 +86,32 @@ func buildSearchQuery(table string, f *filters.Search) (string, []any) {
 	return buildPagedStmt(&f.Base, table, conditional), params
 }
 
-func buildBookQuery(f *filters.Book) (string, []any) {
-	// TODO query by:
-	//   - title, subtitle
-	//   - series
+func buildBookQuery(f *filters.Book) (string, []any, error) {
 	var (
 		conditional = "WHERE $1"
 		params      = []any{"1"}
 	)
 
 	if f == nil || f.Empty() {
-		return buildBaseStmt("name", "ASC", "book_view", conditional), params
-	}
-
-	// generic library search
-	switch {
-
-	case f.Search.Search != "":
-		conditional = `WHERE t.id IN (
-		SELECT ba.book FROM book_author_link ba
-			LEFT JOIN book_tag_link bt ON ba.book=bt.book
-		WHERE ba.book IN
-			(SELECT rowid FROM book_fts WHERE book_fts MATCH $1)
-		OR ba.author IN
-			(SELECT rowid FROM author_fts WHERE author_fts MATCH $1)
-		OR bt.tag IN
-			(SELECT rowid FROM tag_fts WHERE tag_fts MATCH $1))`
-		// escape params
-		params = []any{fmt.Sprintf(`"%s"`, f.Search.Search)}
-
-	// ?title param
-	case f.Title != "":
-		conditional = `WHERE t.id IN (SELECT rowid FROM book_fts WHERE book_fts MATCH $1)`
-		params = []any{fmt.Sprintf(`"%s"`, f.Title)}
-
-	// ?author param
-	case f.Author != "":
-		conditional = `WHERE t.id IN (SELECT ba.book
-			FROM book_author_link ba
-			WHERE ba.author IN
-				(SELECT rowid FROM author_fts WHERE author_fts MATCH $1))`
-		params = []any{fmt.Sprintf(`"%s"`, f.Author)}
-
-	// ?tag param
-	case f.Tag != "":
-		conditional = `WHERE t.id IN (SELECT bt.book
-			FROM book_tag_link bt
-			WHERE bt.tag IN
-			(SELECT rowid FROM tag_fts WHERE tag_fts MATCH $1))`
-		params = []any{fmt.Sprintf(`"%s"`, f.Tag)}
-
-	// no filter by default
-	default:
-		break
+		return buildBaseStmt("title", "ASC", "book_view", conditional), params, nil
+	}
+
+	// library search query and ?title, ?author, ?tag, ?series params
+	node, err := f.Query()
+	if err != nil {
+		return "", nil, err
 	}
 
+	if node != nil {
+		conditional, params, err = compileQuery(node)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	n := len(params)
 	params = append(params, f.AfterId, f.Limit)
-	return buildPagedStmt(&f.Base, "book_view", conditional), params
+	return buildPagedStmtWithParams(&f.Base, "book_view", conditional, n), params, nil
 }
 
 type RowMetadata struct {
@@ -158,6 +134,19 @@ type TagQueryRow struct {
 	*dusk.Tag
 }
 
+type SeriesQueryRow struct {
+	*RowMetadata
+	*dusk.Series
+}
+
+type HighlightQueryRow struct {
+	*RowMetadata
+	*dusk.Highlight
+	Title        string  `db:"title"`
+	AuthorString string  `db:"author_string"`
+	Rank         float64 `db:"rank"`
+}
+
 func newBookPage(dest []BookQueryRow, f *filters.Book) (*page.Page[dusk.Book], error) {
 	// sqlx Select does not return sql.ErrNoRows
 	// related issue: https://github.com/jmoiron/sqlx/issues/762#issuecomment-1062649063
@@ -183,6 +172,7 @@ func newBookPage(dest []BookQueryRow, f *filters.Book) (*page.Page[dusk.Book], e
 		row.Isbn13 = row.Isbn13String.Split(",")
 		row.Formats = row.FormatString.Split(",")
 		row.Series = row.SeriesString
+		row.Book.SeriesPosition = row.BookRow.SeriesPosition
 		books = append(books, *row.Book)
 	}
 
@@ -193,8 +183,17 @@ func newBookPage(dest []BookQueryRow, f *filters.Book) (*page.Page[dusk.Book], e
 		&f.Base,
 		books,
 	)
-	if f.Search.Search != "" {
-		result.QueryParams.Add("q", f.Search.Search)
+
+	for k, v := range map[string]string{
+		"q":      f.Search.Search,
+		"title":  f.Title,
+		"author": f.Author,
+		"tag":    f.Tag,
+		"series": f.Series,
+	} {
+		if v != "" {
+			result.QueryParams.Add(k, v)
+		}
 	}
 	return result, nil
 }
@@ -207,18 +206,12 @@ func newAuthorPage(dest []AuthorQueryRow, f *filters.Search) (*page.Page[dusk.Au
 	first := dest[0]
 	last := dest[len(dest)-1]
 
-	if first.RowNo > last.RowNo {
-		return nil, fmt.Errorf("first row no cannot be larger than last row no")
-	}
-	if (last.RowNo - first.RowNo) > int64(f.Limit) {
-		return nil, fmt.Errorf("num of items cannot be larger than page limit")
-	}
-
 	var authors []dusk.Author
 	for _, row := range dest {
 		authors = append(authors, *row.Author)
 	}
 
+	// unpaginated query
 	if f == nil {
 		return &page.Page[dusk.Author]{
 			Info:  nil,
@@ -226,6 +219,13 @@ func newAuthorPage(dest []AuthorQueryRow, f *filters.Search) (*page.Page[dusk.Au
 		}, nil
 	}
 
+	if first.RowNo > last.RowNo {
+		return nil, fmt.Errorf("first row no cannot be larger than last row no")
+	}
+	if (last.RowNo - first.RowNo) > int64(f.Limit) {
+		return nil, fmt.Errorf("num of items cannot be larger than page limit")
+	}
+
 	result := page.New(
 		int(first.Total),
 		int(first.RowNo),
@@ -247,6 +247,19 @@ func newTagPage(dest []TagQueryRow, f *filters.Search) (*page.Page[dusk.Tag], er
 	first := dest[0]
 	last := dest[len(dest)-1]
 
+	var tags []dusk.Tag
+	for _, row := range dest {
+		tags = append(tags, *row.Tag)
+	}
+
+	// unpaginated query
+	if f == nil {
+		return &page.Page[dusk.Tag]{
+			Info:  nil,
+			Items: tags,
+		}, nil
+	}
+
 	if first.RowNo > last.RowNo {
 		return nil, fmt.Errorf("first row no cannot be larger than last row no")
 	}
@@ -254,24 +267,99 @@ func newTagPage(dest []TagQueryRow, f *filters.Search) (*page.Page[dusk.Tag], er
 		return nil, fmt.Errorf("num of items cannot be larger than page limit")
 	}
 
-	var tags []dusk.Tag
+	result := page.New(
+		int(first.Total),
+		int(first.RowNo),
+		int(last.RowNo),
+		&f.Base,
+		tags,
+	)
+	if f.Search != "" {
+		result.QueryParams.Add("q", f.Search)
+	}
+	return result, nil
+}
+
+func newSeriesPage(dest []SeriesQueryRow, f *filters.Search) (*page.Page[dusk.Series], error) {
+	if len(dest) == 0 {
+		return page.NewEmpty[dusk.Series](), nil
+	}
+
+	first := dest[0]
+	last := dest[len(dest)-1]
+
+	var series []dusk.Series
 	for _, row := range dest {
-		tags = append(tags, *row.Tag)
+		series = append(series, *row.Series)
 	}
 
+	// unpaginated query
 	if f == nil {
-		return &page.Page[dusk.Tag]{
+		return &page.Page[dusk.Series]{
 			Info:  nil,
-			Items: tags,
+			Items: series,
 		}, nil
 	}
 
+	if first.RowNo > last.RowNo {
+		return nil, fmt.Errorf("first row no cannot be larger than last row no")
+	}
+	if (last.RowNo - first.RowNo) > int64(f.Limit) {
+		return nil, fmt.Errorf("num of items cannot be larger than page limit")
+	}
+
 	result := page.New(
 		int(first.Total),
 		int(first.RowNo),
 		int(last.RowNo),
 		&f.Base,
-		tags,
+		series,
+	)
+	if f.Search != "" {
+		result.QueryParams.Add("q", f.Search)
+	}
+	return result, nil
+}
+
+func newHighlightPage(dest []HighlightQueryRow, f *filters.Search) (*page.Page[dusk.Highlight], error) {
+	if len(dest) == 0 {
+		return page.NewEmpty[dusk.Highlight](), nil
+	}
+
+	first := dest[0]
+	last := dest[len(dest)-1]
+
+	var highlights []dusk.Highlight
+	for _, row := range dest {
+		row.Highlight.Book = &dusk.Book{
+			Id:     row.BookId,
+			Title:  row.Title,
+			Author: strings.Split(row.AuthorString, ","),
+		}
+		highlights = append(highlights, *row.Highlight)
+	}
+
+	// unpaginated query
+	if f == nil {
+		return &page.Page[dusk.Highlight]{
+			Info:  nil,
+			Items: highlights,
+		}, nil
+	}
+
+	if first.RowNo > last.RowNo {
+		return nil, fmt.Errorf("first row no cannot be larger than last row no")
+	}
+	if (last.RowNo - first.RowNo) > int64(f.Limit) {
+		return nil, fmt.Errorf("num of items cannot be larger than page limit")
+	}
+
+	result := page.New(
+		int(first.Total),
+		int(first.RowNo),
+		int(last.RowNo),
+		&f.Base,
+		highlights,
 	)
 	if f.Search != "" {
 		result.QueryParams.Add("q", f.Search)
@@ -0,0 +1,267 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/query"
+)
+
+// trigram FTS tables cannot match terms shorter than 3 characters
+const minFtsLength = 3
+
+// queryCompiler compiles a query AST into a parameterised SQL conditional
+// against book_view. Params are bound with sequentially numbered $N
+// placeholders in the order they appear in the conditional.
+type queryCompiler struct {
+	params []any
+}
+
+// compileQuery returns the WHERE clause and params of a query AST.
+func compileQuery(node query.Node) (string, []any, error) {
+	c := &queryCompiler{}
+
+	expr, err := c.compile(node)
+	if err != nil {
+		return "", nil, err
+	}
+	return "WHERE " + expr, c.params, nil
+}
+
+func (c *queryCompiler) bind(v any) string {
+	c.params = append(c.params, v)
+	return "$" + strconv.Itoa(len(c.params))
+}
+
+func (c *queryCompiler) compile(node query.Node) (string, error) {
+	switch n := node.(type) {
+	case query.And:
+		return c.join(n.Nodes, " AND ")
+	case query.Or:
+		return c.join(n.Nodes, " OR ")
+	case query.Not:
+		expr, err := c.compile(n.Node)
+		if err != nil {
+			return "", err
+		}
+		// NULL comparisons must not be excluded from negated terms
+		return fmt.Sprintf("NOT IFNULL((%s), 0)", expr), nil
+	case query.Text:
+		return c.text(n.Value), nil
+	case query.Term:
+		return c.term(n)
+	default:
+		return "", fmt.Errorf("unsupported query node %T", node)
+	}
+}
+
+func (c *queryCompiler) join(nodes []query.Node, sep string) (string, error) {
+	var exprs []string
+	for _, n := range nodes {
+		expr, err := c.compile(n)
+		if err != nil {
+			return "", err
+		}
+		exprs = append(exprs, expr)
+	}
+	return "(" + strings.Join(exprs, sep) + ")", nil
+}
+
+// free text matches the title, authors or tags of a book
+func (c *queryCompiler) text(value string) string {
+	if utf8.RuneCountInString(value) < minFtsLength {
+		like := likePattern(value)
+		return fmt.Sprintf(
+			"(t.title LIKE %[1]s ESCAPE '\\' OR t.subtitle LIKE %[1]s ESCAPE '\\' OR t.author_string LIKE %[1]s ESCAPE '\\' OR t.tag_string LIKE %[1]s ESCAPE '\\')",
+			c.bind(like),
+		)
+	}
+
+	match := ftsPhrase(value)
+	return fmt.Sprintf(`t.id IN (
+		SELECT ba.book FROM book_author_link ba
+			LEFT JOIN book_tag_link bt ON ba.book=bt.book
+		WHERE ba.book IN
+			(SELECT rowid FROM book_fts WHERE book_fts MATCH %s)
+		OR ba.author IN
+			(SELECT rowid FROM author_fts WHERE author_fts MATCH %s)
+		OR bt.tag IN
+			(SELECT rowid FROM tag_fts WHERE tag_fts MATCH %s))`,
+		c.bind(match), c.bind(match), c.bind(match),
+	)
+}
+
+func (c *queryCompiler) term(t query.Term) (string, error) {
+	switch t.Field {
+	case query.Title:
+		if utf8.RuneCountInString(t.Value) < minFtsLength {
+			like := likePattern(t.Value)
+			return fmt.Sprintf("(t.title LIKE %[1]s ESCAPE '\\' OR t.subtitle LIKE %[1]s ESCAPE '\\')", c.bind(like)), nil
+		}
+		return fmt.Sprintf(
+			"t.id IN (SELECT rowid FROM book_fts WHERE book_fts MATCH %s)",
+			c.bind(ftsPhrase(t.Value)),
+		), nil
+
+	case query.Author:
+		return c.linked("book_author_link", "author", t.Value), nil
+
+	case query.Series:
+		return c.linked("book_series_link", "series", t.Value), nil
+
+	case query.Tag:
+		return fmt.Sprintf(`t.id IN (SELECT bt.book
+			FROM book_tag_link bt
+				INNER JOIN tag tg ON tg.id=bt.tag
+			WHERE lower(tg.name)=%s OR substr(lower(tg.name), 1, length(%s)+1)=%s || '.')`,
+			c.bind(t.Value), c.bind(t.Value), c.bind(t.Value),
+		), nil
+
+	case query.Publisher:
+		return fmt.Sprintf("t.publisher LIKE %s ESCAPE '\\'", c.bind(likePattern(t.Value))), nil
+
+	case query.Isbn:
+		isbn := strings.ReplaceAll(t.Value, "-", "")
+		return fmt.Sprintf(`t.id IN (
+			SELECT bookId FROM isbn10 WHERE isbn=%s
+			UNION SELECT bookId FROM isbn13 WHERE isbn=%s)`,
+			c.bind(isbn), c.bind(isbn),
+		), nil
+
+	case query.Format:
+		return fmt.Sprintf("t.id IN (SELECT bookId FROM format WHERE lower(filepath) LIKE %s ESCAPE '\\')",
+			c.bind("%."+likeEscape(t.Value)),
+		), nil
+
+	case query.Identifier:
+		scheme, value, err := dusk.ParseIdentifier(t.Value)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("t.id IN (SELECT bookId FROM identifier WHERE scheme=%s AND value=%s)",
+			c.bind(scheme), c.bind(value),
+		), nil
+
+	case query.Status:
+		return fmt.Sprintf("t.status=%s", c.bind(query.StatusIndex(t.Value))), nil
+
+	case query.Ownership:
+		return fmt.Sprintf("t.ownership=%s", c.bind(query.OwnershipIndex(t.Value))), nil
+
+	case query.Has:
+		return c.has(t.Value)
+
+	case query.Rating:
+		return c.compare("t.rating", t)
+	case query.Pages:
+		return c.compare("t.numOfPages", t)
+	case query.Progress:
+		return c.compare("t.progress", t)
+
+	case query.Added:
+		return c.compareDate("t.dateAdded", t)
+	case query.Started:
+		return c.compareDate("t.dateStarted", t)
+	case query.Completed:
+		return c.compareDate("t.dateCompleted", t)
+	case query.Published:
+		return c.compareDate("t.datePublished", t)
+
+	default:
+		return "", fmt.Errorf("unsupported query field %q", t.Field)
+	}
+}
+
+// has matches books with any value for the given field
+func (c *queryCompiler) has(value string) (string, error) {
+	switch value {
+	case "cover":
+		return "t.cover IS NOT NULL", nil
+	case "format":
+		return "t.id IN (SELECT bookId FROM format)", nil
+	case "isbn":
+		return "t.id IN (SELECT bookId FROM isbn10 UNION SELECT bookId FROM isbn13)", nil
+	case "series":
+		return "t.id IN (SELECT book FROM book_series_link)", nil
+	case "description":
+		return "IFNULL(t.description, '')!=''", nil
+	default:
+		return "", fmt.Errorf("unsupported value %q for field %q", value, query.Has)
+	}
+}
+
+// linked matches books linked to an author or series by name
+func (c *queryCompiler) linked(linkTable, column, value string) string {
+	if utf8.RuneCountInString(value) < minFtsLength {
+		return fmt.Sprintf(`t.id IN (SELECT l.book
+			FROM %[1]s l
+				INNER JOIN %[2]s x ON x.id=l.%[2]s
+			WHERE x.name LIKE %[3]s ESCAPE '\')`,
+			linkTable, column, c.bind(likePattern(value)),
+		)
+	}
+
+	return fmt.Sprintf(`t.id IN (SELECT l.book
+		FROM %[1]s l
+		WHERE l.%[2]s IN
+			(SELECT rowid FROM %[2]s_fts WHERE %[2]s_fts MATCH %[3]s))`,
+		linkTable, column, c.bind(ftsPhrase(value)),
+	)
+}
+
+func (c *queryCompiler) compare(column string, t query.Term) (string, error) {
+	n, err := strconv.Atoi(t.Value)
+	if err != nil {
+		return "", fmt.Errorf("invalid number %q: %w", t.Value, err)
+	}
+
+	op := string(t.Op)
+	if t.Op == query.Eq {
+		op = "="
+	}
+	return fmt.Sprintf("%s%s%s", column, op, c.bind(n)), nil
+}
+
+// Dates are stored as text of varying precision. Only the date portion is
+// compared against the range covered by the term's value.
+func (c *queryCompiler) compareDate(column string, t query.Term) (string, error) {
+	from, to, err := query.DateRange(t.Value)
+	if err != nil {
+		return "", err
+	}
+
+	date := fmt.Sprintf("substr(%s, 1, 10)", column)
+	f := from.Format(time.DateOnly)
+	e := to.Format(time.DateOnly)
+
+	switch t.Op {
+	case query.Gt:
+		return fmt.Sprintf("%s>=%s", date, c.bind(e)), nil
+	case query.Gte:
+		return fmt.Sprintf("%s>=%s", date, c.bind(f)), nil
+	case query.Lt:
+		return fmt.Sprintf("%s<%s", date, c.bind(f)), nil
+	case query.Lte:
+		return fmt.Sprintf("%s<%s", date, c.bind(e)), nil
+	default:
+		return fmt.Sprintf("(%s>=%s AND %s<%s)", date, c.bind(f), date, c.bind(e)), nil
+	}
+}
+
+// escape FTS query as a single phrase
+func ftsPhrase(value string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(value, `"`, `""`))
+}
+
+// escape the LIKE wildcards of value, which is matched with ESCAPE '\'
+func likeEscape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+func likePattern(value string) string {
+	return "%" + likeEscape(value) + "%"
+}
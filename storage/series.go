@@ -4,11 +4,14 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/kencx/dusk"
 	"github.com/kencx/dusk/filters"
+	"github.com/kencx/dusk/null"
 	"github.com/kencx/dusk/page"
+	"github.com/kencx/dusk/util"
 )
 
 func (s *Store) GetSeries(id int64) (*dusk.Series, error) {
@@ -23,6 +26,16 @@ func (s *Store) GetSeries(id int64) (*dusk.Series, error) {
 		if err != nil {
 			return nil, fmt.Errorf("[db] failed to retrieve series %d: %w", id, err)
 		}
+
+		books, err := getBooksFromSeries(tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to retrieve books from series %d: %w", id, err)
+		}
+		for i := range books {
+			books[i].Series = null.StringFrom(series.Name)
+		}
+		series.Books = books
+
 		return &series, nil
 	})
 
@@ -32,31 +45,42 @@ func (s *Store) GetSeries(id int64) (*dusk.Series, error) {
 	return i.(*dusk.Series), nil
 }
 
-func (s *Store) GetAllSeries() ([]*dusk.Series, error) {
+func (s *Store) GetAllSeries(f *filters.Search) (*page.Page[dusk.Series], error) {
 	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
-		var series []*dusk.Series
-		stmt := `SELECT id, name FROM series;`
+		var dest []SeriesQueryRow
 
-		err := tx.Select(&series, stmt)
+		err := querySeries(tx, f, &dest)
 		if err != nil {
 			return nil, fmt.Errorf("[db] failed to query series: %w", err)
 		}
-		if len(series) == 0 {
-			return nil, dusk.ErrNoRows
+
+		result, err := newSeriesPage(dest, f)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to create new series page: %w", err)
 		}
-		return series, nil
+		return result, nil
 	})
 
 	if err != nil {
 		return nil, err
 	}
-	return i.([]*dusk.Series), nil
+	return i.(*page.Page[dusk.Series]), nil
 }
 
 func (s *Store) GetAllBooksFromSeries(id int64, f *filters.Book) (*page.Page[dusk.Book], error) {
 	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
-		var dest []BookQueryRow
-		query := buildPagedStmt(&f.Base, "book_view", `WHERE b.id IN (SELECT bookId FROM series WHERE id=$1)`)
+		var dest []struct {
+			BookQueryRow
+			Position int64 `db:"position"`
+		}
+
+		// position ranks books in the series' reading order, which can be
+		// sorted on in place of the book columns
+		table := `(SELECT b.*,
+				ROW_NUMBER() OVER(ORDER BY bs.position IS NULL, bs.position, b.title) AS position
+			FROM book_view b
+			JOIN book_series_link bs ON bs.book=b.id AND bs.series=$1)`
+		query := buildPagedStmt(&f.Base, table, "")
 
 		slog.Info("Running SQL query",
 			slog.String("stmt", query),
@@ -70,7 +94,12 @@ func (s *Store) GetAllBooksFromSeries(id int64, f *filters.Book) (*page.Page[dus
 			return nil, fmt.Errorf("[db] failed to retrieve books from series %d: %w", id, err)
 		}
 
-		result, err := newBookPage(dest, f)
+		rows := make([]BookQueryRow, len(dest))
+		for i, row := range dest {
+			rows[i] = row.BookQueryRow
+		}
+
+		result, err := newBookPage(rows, f)
 		if err != nil {
 			return nil, err
 		}
@@ -83,12 +112,42 @@ func (s *Store) GetAllBooksFromSeries(id int64, f *filters.Book) (*page.Page[dus
 	return i.(*page.Page[dusk.Book]), nil
 }
 
+func (s *Store) CreateSeries(a *dusk.Series) (*dusk.Series, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		// unlike insertSeries, creating a series that exists is an error
+		stmt := `INSERT INTO series (name) VALUES ($1);`
+		res, err := tx.Exec(stmt, a.Name)
+		if err != nil {
+			if isUniqueConstraintErr(err) {
+				return nil, fmt.Errorf("[db] failed to create series %q: %w", a.Name, dusk.ErrUniqueConstraint)
+			}
+			return nil, fmt.Errorf("[db] failed to create series: %w", err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to create series: %w", err)
+		}
+
+		a.Id = id
+		return a, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Series), nil
+}
+
 func (s *Store) UpdateSeries(id int64, a *dusk.Series) (*dusk.Series, error) {
 	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
 		stmt := `UPDATE series SET name=$1 WHERE id=$2`
 		res, err := tx.Exec(stmt, a.Name, id)
 
 		if err != nil {
+			if isUniqueConstraintErr(err) {
+				return nil, fmt.Errorf("[db] failed to rename series %d: %w", id, dusk.ErrUniqueConstraint)
+			}
 			return nil, fmt.Errorf("[db] failed to update series %d: %w", id, err)
 		}
 		count, err := res.RowsAffected()
@@ -98,6 +157,8 @@ func (s *Store) UpdateSeries(id int64, a *dusk.Series) (*dusk.Series, error) {
 		if count == 0 {
 			return nil, dusk.ErrNoChange
 		}
+
+		a.Id = id
 		return a, nil
 	})
 
@@ -132,7 +193,7 @@ func (s *Store) UpdateSeriesByName(name string, a *dusk.Series) (*dusk.Series, e
 }
 
 // Series with existing books CAN be deleted. Their deletion will cause the series to be
-// unlinked from all relevant books.
+// unlinked from all relevant books by sqlite with CASCADE.
 func (s *Store) DeleteSeries(id int64) error {
 	_, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
 		stmt := `DELETE FROM series WHERE id=$1;`
@@ -154,12 +215,36 @@ func (s *Store) DeleteSeries(id int64) error {
 	return err
 }
 
-func getSeriesFromBook(tx *sqlx.Tx, bookId int64) (*dusk.Series, error) {
-	var series dusk.Series
-	stmt := `SELECT id, name
-		FROM series
-		WHERE bookId=$1
-        ORDER BY id`
+type seriesLink struct {
+	Id       int64      `db:"id"`
+	Name     string     `db:"name"`
+	Position null.Float `db:"position"`
+}
+
+func querySeries(tx *sqlx.Tx, filters *filters.Search, dest *[]SeriesQueryRow) error {
+	query, params := buildSearchQuery("series", "series", filters)
+
+	slog.Info("Running SQL query",
+		slog.String("stmt", util.TrimMultiLine(query)),
+		slog.Any("params", params),
+	)
+	err := tx.Select(dest, query, params...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// get the primary series of a book. This is the first series that the book was linked
+// to.
+func getSeriesFromBook(tx *sqlx.Tx, bookId int64) (*seriesLink, error) {
+	var series seriesLink
+	stmt := `SELECT s.id, s.name, bs.position
+		FROM book_series_link bs
+		JOIN series s ON s.id=bs.series
+		WHERE bs.book=$1
+		ORDER BY bs.rowid
+		LIMIT 1`
 
 	if err := tx.QueryRowx(stmt, bookId).StructScan(&series); err != nil {
 		if err == sql.ErrNoRows {
@@ -170,10 +255,40 @@ func getSeriesFromBook(tx *sqlx.Tx, bookId int64) (*dusk.Series, error) {
 	return &series, nil
 }
 
+// get all books in series in reading order. Books with no position are ordered last
+// by title.
+func getBooksFromSeries(tx *sqlx.Tx, id int64) ([]dusk.Book, error) {
+	var dest []struct {
+		BookRow
+		Position null.Float `db:"position"`
+	}
+	stmt := `SELECT b.*, bs.position
+		FROM book_series_link bs
+		JOIN book_view b ON b.id=bs.book
+		WHERE bs.series=$1
+		ORDER BY bs.position IS NULL, bs.position, b.title`
+
+	if err := tx.Select(&dest, stmt, id); err != nil {
+		return nil, err
+	}
+
+	var books []dusk.Book
+	for _, row := range dest {
+		row.Author = strings.Split(row.AuthorString, ",")
+		row.Tag = row.TagString.Split(",")
+		row.Isbn10 = row.Isbn10String.Split(",")
+		row.Isbn13 = row.Isbn13String.Split(",")
+		row.Formats = row.FormatString.Split(",")
+		row.Book.SeriesPosition = row.Position
+		books = append(books, *row.Book)
+	}
+	return books, nil
+}
+
 // Insert given series. If series already exists, return its id instead
-func insertSeries(tx *sqlx.Tx, bookId int64, t string) (int64, error) {
-	stmt := `INSERT OR IGNORE INTO series (bookId, name) VALUES ($1, $2);`
-	res, err := tx.Exec(stmt, bookId, t)
+func insertSeries(tx *sqlx.Tx, name string) (int64, error) {
+	stmt := `INSERT OR IGNORE INTO series (name) VALUES ($1);`
+	res, err := tx.Exec(stmt, name)
 	if err != nil {
 		return -1, err
 	}
@@ -188,7 +303,7 @@ func insertSeries(tx *sqlx.Tx, bookId int64, t string) (int64, error) {
 		// series.name is unique
 		var id int64
 		stmt := `SELECT id FROM series WHERE name=$1;`
-		err := tx.Get(&id, stmt, t)
+		err := tx.Get(&id, stmt, name)
 		if err != nil {
 			return -1, fmt.Errorf("failed to query existing series: %w", err)
 		}
@@ -203,9 +318,29 @@ func insertSeries(tx *sqlx.Tx, bookId int64, t string) (int64, error) {
 	}
 }
 
-func deleteBookFromSeries(tx *sqlx.Tx, bookId, id int64) error {
-	stmt := `DELETE FROM series WHERE id=$1 AND bookId=$2;`
-	res, err := tx.Exec(stmt, id, bookId)
+func linkBookToSeries(tx *sqlx.Tx, bookId, id int64, position null.Float) error {
+	stmt := `INSERT INTO book_series_link (book, series, position) VALUES ($1, $2, $3)
+	ON CONFLICT (book, series) DO UPDATE SET position=excluded.position;`
+	if _, err := tx.Exec(stmt, bookId, id, position); err != nil {
+		return fmt.Errorf("link book %d to series %d in book_series_link failed: %w", bookId, id, err)
+	}
+	return nil
+}
+
+// Replace the primary series of a book in place, preserving its link order. Any
+// existing link between the book and the new series is replaced.
+func relinkBookToSeries(tx *sqlx.Tx, bookId, oldId, newId int64, position null.Float) error {
+	stmt := `UPDATE OR REPLACE book_series_link SET series=$1, position=$2
+		WHERE book=$3 AND series=$4;`
+	if _, err := tx.Exec(stmt, newId, position, bookId, oldId); err != nil {
+		return fmt.Errorf("relink book %d from series %d to series %d failed: %w", bookId, oldId, newId, err)
+	}
+	return nil
+}
+
+func unlinkBookFromSeries(tx *sqlx.Tx, bookId, id int64) error {
+	stmt := `DELETE FROM book_series_link WHERE book=$1 AND series=$2;`
+	res, err := tx.Exec(stmt, bookId, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete book %d from series %d: %w", bookId, id, err)
 	}
@@ -220,3 +355,23 @@ func deleteBookFromSeries(tx *sqlx.Tx, bookId, id int64) error {
 	}
 	return nil
 }
+
+// delete all series that are not linked to any existing books
+func deleteSeriesWithNoBooks(tx *sqlx.Tx) error {
+	stmt := `DELETE FROM series WHERE id NOT IN
+				(SELECT series FROM book_series_link);`
+	res, err := tx.Exec(stmt)
+	if err != nil {
+		return fmt.Errorf("failed to delete series with no books: %w", err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete series with no books: %w", err)
+	}
+
+	if count != 0 {
+		slog.Debug("[db] deleted series", slog.Int64("count", count))
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/kencx/dusk"
@@ -12,8 +13,8 @@ func TestGetSeries(t *testing.T) {
 	got, err := ts.GetSeries(testSeries1.Id)
 	is.NoErr(err)
 
-	want := testSeries1
-	is.Equal(got, want)
+	is.Equal(got.Id, testSeries1.Id)
+	is.Equal(got.Name, testSeries1.Name)
 }
 
 func TestGetSeriesNotExists(t *testing.T) {
@@ -35,32 +36,32 @@ func TestGetAllSeries(t *testing.T) {
 	defer resetDB()
 
 	is := is.New(t)
-	got, err := ts.GetAllSeries()
+	got, err := ts.GetAllSeries(testFilters())
 	is.NoErr(err)
 
 	want := allTestSeries
-	is.Equal(got, want)
+	is.Equal(len(got.Items), len(want))
+	for i := range want {
+		is.Equal(got.Items[i].Id, want[i].Id)
+		is.Equal(got.Items[i].Name, want[i].Name)
+	}
 }
 
 func TestGetAllSeriesEmpty(t *testing.T) {
 	defer resetDB()
 
 	// delete all data
-	if err := ts.MigrateUp(resetSchemaPath); err != nil {
+	if err := ts.execFile(resetSchemaPath); err != nil {
 		t.Errorf("failed to reset database")
 	}
 
-	got, err := ts.GetAllSeries()
-	if err == nil {
-		t.Errorf("expected error: ErrNoRows")
-	}
-
-	if err != dusk.ErrNoRows {
+	got, err := ts.GetAllSeries(testFilters())
+	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
 
-	if got != nil {
-		t.Errorf("got %v, want nil", got)
+	if len(got.Items) != 0 {
+		t.Errorf("got %v, want no items", got.Items)
 	}
 }
 
@@ -68,8 +69,9 @@ func TestGetAllBooksFromSeries(t *testing.T) {
 	defer resetDB()
 
 	is := is.New(t)
-	got, err := ts.GetAllBooksFromSeries(testSeries1.Id)
+	result, err := ts.GetAllBooksFromSeries(testSeries1.Id, testBookFilters())
 	is.NoErr(err)
+	got := result.Items
 
 	want := dusk.Books{testBook2}
 	is.True(len(got) == len(want))
@@ -80,6 +82,46 @@ func TestGetAllBooksFromSeries(t *testing.T) {
 	}
 }
 
+func TestGetAllBooksFromSeriesByPosition(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	stmt := `INSERT INTO book_series_link (series, book, position) VALUES ($1, $2, 2), ($1, $3, 1);`
+	_, err := ts.db.Exec(stmt, testSeries1.Id, testBook1.Id, testBook3.Id)
+	is.NoErr(err)
+
+	f := testBookFilters()
+	f.Sort = "position"
+	f.SortSafeList = append(f.SortSafeList, "position")
+
+	result, err := ts.GetAllBooksFromSeries(testSeries1.Id, f)
+	is.NoErr(err)
+
+	// books without a position come last
+	var got []int64
+	for _, b := range result.Items {
+		got = append(got, b.Id)
+	}
+	is.Equal(got, []int64{testBook3.Id, testBook1.Id, testBook2.Id})
+}
+
+func TestCreateSeries(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	got, err := ts.CreateSeries(&dusk.Series{Name: "series 2"})
+	is.NoErr(err)
+	is.True(got.Id != testSeries1.Id)
+}
+
+func TestCreateSeriesExisting(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	_, err := ts.CreateSeries(&dusk.Series{Name: testSeries1.Name})
+	is.True(errors.Is(err, dusk.ErrUniqueConstraint))
+}
+
 func TestUpdateSeries(t *testing.T) {
 	defer resetDB()
 
@@ -0,0 +1,398 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func (s *Store) GetReadingSessions(bookId int64) ([]dusk.ReadingSession, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		sessions, err := getSessionsFromBook(tx, bookId)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to retrieve reading sessions from book %d: %w", bookId, err)
+		}
+		return sessions, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.([]dusk.ReadingSession), nil
+}
+
+func (s *Store) GetReadingSession(id int64) (*dusk.ReadingSession, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		session, err := getSession(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		return session, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.ReadingSession), nil
+}
+
+func (s *Store) CreateReadingSession(bookId int64, rs *dusk.ReadingSession) (*dusk.ReadingSession, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		if err := insertSession(tx, bookId, rs); err != nil {
+			if isForeignKeyConstraintErr(err) {
+				return nil, dusk.ErrDoesNotExist
+			}
+			return nil, fmt.Errorf("[db] failed to create reading session for book %d: %w", bookId, err)
+		}
+		if err := syncBookWithSessions(tx, bookId, nil); err != nil {
+			return nil, fmt.Errorf("[db] %w", err)
+		}
+		return rs, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.ReadingSession), nil
+}
+
+func (s *Store) UpdateReadingSession(id int64, rs *dusk.ReadingSession) (*dusk.ReadingSession, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		current, err := getSession(tx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		rs.Id = id
+		rs.BookId = current.BookId
+		if err := updateSession(tx, rs); err != nil {
+			return nil, fmt.Errorf("[db] failed to update reading session %d: %w", id, err)
+		}
+		if err := syncBookWithSessions(tx, rs.BookId, nil); err != nil {
+			return nil, fmt.Errorf("[db] %w", err)
+		}
+
+		rs.Progress = current.Progress
+		return rs, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.ReadingSession), nil
+}
+
+func (s *Store) DeleteReadingSession(id int64) error {
+	_, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		current, err := getSession(tx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		// progress snapshots are deleted by sqlite with CASCADE
+		stmt := `DELETE FROM reading_session WHERE id=$1;`
+		if _, err := tx.Exec(stmt, id); err != nil {
+			return nil, fmt.Errorf("[db] failed to delete reading session %d: %w", id, err)
+		}
+		if err := syncBookWithSessions(tx, current.BookId, nil); err != nil {
+			return nil, fmt.Errorf("[db] %w", err)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// AddReadingProgress records a progress snapshot for the reading session. The
+// timestamp defaults to the current time.
+func (s *Store) AddReadingProgress(sessionId int64, p *dusk.ReadingProgress) (*dusk.ReadingProgress, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		current, err := getSession(tx, sessionId)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := insertProgress(tx, sessionId, p); err != nil {
+			return nil, fmt.Errorf("[db] failed to add progress to reading session %d: %w", sessionId, err)
+		}
+		if err := syncBookWithSessions(tx, current.BookId, nil); err != nil {
+			return nil, fmt.Errorf("[db] %w", err)
+		}
+		return p, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.ReadingProgress), nil
+}
+
+func getSession(tx *sqlx.Tx, id int64) (*dusk.ReadingSession, error) {
+	var session dusk.ReadingSession
+	stmt := `SELECT * FROM reading_session WHERE id=$1;`
+
+	if err := tx.QueryRowx(stmt, id).StructScan(&session); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, dusk.ErrDoesNotExist
+		}
+		return nil, fmt.Errorf("[db] failed to retrieve reading session %d: %w", id, err)
+	}
+
+	progress, err := getProgressFromSessions(tx, []int64{id})
+	if err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve progress of reading session %d: %w", id, err)
+	}
+	session.Progress = progress[id]
+	return &session, nil
+}
+
+// get all reading sessions of book in chronological order
+func getSessionsFromBook(tx *sqlx.Tx, bookId int64) ([]dusk.ReadingSession, error) {
+	var sessions []dusk.ReadingSession
+	stmt := `SELECT * FROM reading_session WHERE bookId=$1 ORDER BY id;`
+
+	if err := tx.Select(&sessions, stmt, bookId); err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, s := range sessions {
+		ids = append(ids, s.Id)
+	}
+
+	progress, err := getProgressFromSessions(tx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range sessions {
+		sessions[i].Progress = progress[s.Id]
+	}
+	return sessions, nil
+}
+
+func getProgressFromSessions(tx *sqlx.Tx, sessionIds []int64) (map[int64][]dusk.ReadingProgress, error) {
+	result := make(map[int64][]dusk.ReadingProgress)
+	if len(sessionIds) == 0 {
+		return result, nil
+	}
+
+	var progress []dusk.ReadingProgress
+	query, args, err := sqlx.In(`SELECT * FROM reading_progress
+		WHERE sessionId IN (?)
+		ORDER BY timestamp, id;`, sessionIds)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Select(&progress, tx.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	for _, p := range progress {
+		result[p.SessionId] = append(result[p.SessionId], p)
+	}
+	return result, nil
+}
+
+func insertSession(tx *sqlx.Tx, bookId int64, rs *dusk.ReadingSession) error {
+	rs.BookId = bookId
+	stmt := `INSERT INTO reading_session (bookId, state, dateStarted, dateCompleted, notes)
+		VALUES (:bookId, :state, :dateStarted, :dateCompleted, :notes);`
+
+	res, err := tx.NamedExec(stmt, rs)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	rs.Id = id
+
+	for i := range rs.Progress {
+		if err := insertProgress(tx, id, &rs.Progress[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func updateSession(tx *sqlx.Tx, rs *dusk.ReadingSession) error {
+	stmt := `UPDATE reading_session
+		SET
+			state=:state,
+			dateStarted=:dateStarted,
+			dateCompleted=:dateCompleted,
+			notes=:notes
+		WHERE id=:id;`
+
+	res, err := tx.NamedExec(stmt, rs)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return dusk.ErrNoChange
+	}
+	return nil
+}
+
+func insertProgress(tx *sqlx.Tx, sessionId int64, p *dusk.ReadingProgress) error {
+	p.SessionId = sessionId
+	if p.Timestamp.IsZero() {
+		p.Timestamp = time.Now()
+	}
+
+	stmt := `INSERT INTO reading_progress (sessionId, progress, timestamp)
+		VALUES (:sessionId, :progress, :timestamp);`
+
+	res, err := tx.NamedExec(stmt, p)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	p.Id = id
+	return nil
+}
+
+// syncBookWithSessions updates the status, dates and progress of the book from
+// its latest reading session. If b is not nil, its fields are updated to match.
+func syncBookWithSessions(tx *sqlx.Tx, bookId int64, b *dusk.Book) error {
+	sessions, err := getSessionsFromBook(tx, bookId)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve reading sessions from book %d: %w", bookId, err)
+	}
+
+	var (
+		status        = dusk.StatusFromSessions(sessions)
+		dateStarted   null.Time
+		dateCompleted null.Time
+		progress      int
+	)
+
+	if len(sessions) > 0 {
+		latest := sessions[len(sessions)-1]
+		dateStarted = latest.DateStarted
+		progress = latest.LatestProgress()
+		if latest.State == dusk.SessionFinished {
+			progress = 100
+		}
+	}
+
+	// date of the last finished read
+	for _, s := range sessions {
+		if s.State == dusk.SessionFinished && s.DateCompleted.Valid {
+			dateCompleted = s.DateCompleted
+		}
+	}
+
+	stmt := `UPDATE book
+		SET status=$1, dateStarted=$2, dateCompleted=$3, progress=$4
+		WHERE id=$5;`
+	if _, err := tx.Exec(stmt, status, dateStarted, dateCompleted, progress, bookId); err != nil {
+		return fmt.Errorf("failed to sync book %d with reading sessions: %w", bookId, err)
+	}
+
+	if b != nil {
+		b.Status = status
+		b.DateStarted = dateStarted
+		b.DateCompleted = dateCompleted
+		b.Progress = progress
+		b.Sessions = sessions
+	}
+	return nil
+}
+
+// applyStatusToSessions records a change in the book's status in its reading
+// sessions:
+//   - starting to read an unread or finished book begins a new session
+//   - marking a book as read finishes the current session or records a new
+//     finished session
+//   - marking a book as unread abandons the current session
+//
+// Finished sessions are kept, so a book that has been read stays read until
+// they are deleted.
+func applyStatusToSessions(tx *sqlx.Tx, bookId int64, b *dusk.Book) error {
+	sessions, err := getSessionsFromBook(tx, bookId)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve reading sessions from book %d: %w", bookId, err)
+	}
+
+	var latest *dusk.ReadingSession
+	if len(sessions) > 0 {
+		latest = &sessions[len(sessions)-1]
+	}
+
+	switch b.Status {
+	case dusk.Unread:
+		if latest != nil && latest.State == dusk.SessionReading {
+			latest.State = dusk.SessionAbandoned
+			return updateSession(tx, latest)
+		}
+
+	case dusk.Reading:
+		if latest == nil || latest.State != dusk.SessionReading {
+			rs := &dusk.ReadingSession{
+				State:       dusk.SessionReading,
+				DateStarted: b.DateStarted,
+			}
+			if !rs.DateStarted.Valid {
+				rs.DateStarted = null.TimeFrom(time.Now())
+			}
+			if b.Progress > 0 {
+				rs.Progress = []dusk.ReadingProgress{{Progress: min(b.Progress, 100)}}
+			}
+			return insertSession(tx, bookId, rs)
+		}
+
+		if b.DateStarted.Valid && !b.DateStarted.Equal(latest.DateStarted) {
+			latest.DateStarted = b.DateStarted
+			if err := updateSession(tx, latest); err != nil {
+				return err
+			}
+		}
+		if b.Progress != latest.LatestProgress() {
+			return insertProgress(tx, latest.Id, &dusk.ReadingProgress{Progress: min(b.Progress, 100)})
+		}
+
+	case dusk.Read:
+		if latest == nil || latest.State == dusk.SessionAbandoned {
+			rs := &dusk.ReadingSession{
+				State:         dusk.SessionFinished,
+				DateStarted:   b.DateStarted,
+				DateCompleted: b.DateCompleted,
+			}
+			return insertSession(tx, bookId, rs)
+		}
+
+		if latest.State == dusk.SessionReading {
+			latest.State = dusk.SessionFinished
+			latest.DateCompleted = b.DateCompleted
+			if !latest.DateCompleted.Valid {
+				latest.DateCompleted = null.TimeFrom(time.Now())
+			}
+			return updateSession(tx, latest)
+		}
+
+		if (b.DateStarted.Valid && !b.DateStarted.Equal(latest.DateStarted)) ||
+			(b.DateCompleted.Valid && !b.DateCompleted.Equal(latest.DateCompleted)) {
+			if b.DateStarted.Valid {
+				latest.DateStarted = b.DateStarted
+			}
+			if b.DateCompleted.Valid {
+				latest.DateCompleted = b.DateCompleted
+			}
+			return updateSession(tx, latest)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/kencx/dusk"
+	"github.com/matryer/is"
+)
+
+func TestUpdateBookStatusUnread(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     []dusk.ReadStatus
+		want       []dusk.SessionState
+		wantStatus dusk.ReadStatus
+	}{{
+		name:       "reading",
+		status:     []dusk.ReadStatus{dusk.Reading, dusk.Unread},
+		want:       []dusk.SessionState{dusk.SessionAbandoned},
+		wantStatus: dusk.Unread,
+	}, {
+		// finished reads are kept
+		name:       "read",
+		status:     []dusk.ReadStatus{dusk.Read, dusk.Unread},
+		want:       []dusk.SessionState{dusk.SessionFinished},
+		wantStatus: dusk.Read,
+	}, {
+		// an abandoned re-read keeps the previous read
+		name:       "re-reading",
+		status:     []dusk.ReadStatus{dusk.Read, dusk.Reading, dusk.Unread},
+		want:       []dusk.SessionState{dusk.SessionFinished, dusk.SessionAbandoned},
+		wantStatus: dusk.Read,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer resetDB()
+			is := is.New(t)
+
+			b, err := ts.CreateBook(&dusk.Book{Title: "Book 5", Author: []string{testAuthor1.Name}})
+			is.NoErr(err)
+
+			for _, status := range tt.status {
+				b.Status = status
+				b, err = ts.UpdateBook(b.Id, b)
+				is.NoErr(err)
+			}
+
+			got, err := ts.GetBook(b.Id)
+			is.NoErr(err)
+			is.Equal(got.Status, tt.wantStatus)
+
+			sessions, err := ts.GetReadingSessions(b.Id)
+			is.NoErr(err)
+			is.Equal(len(sessions), len(tt.want))
+			for i, s := range sessions {
+				is.Equal(s.State, tt.want[i])
+			}
+		})
+	}
+}
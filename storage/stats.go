@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kencx/dusk"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const topStatsLimit = 10
+
+// finished reading sessions with a known completion date, filtered by year if
+// $1 is not empty. Dates are stored as text so the year is compared as a prefix.
+const finishedSessions = `WITH finished AS (
+		SELECT rs.bookId, rs.dateStarted, rs.dateCompleted
+		FROM reading_session rs
+		WHERE rs.state=1
+			AND rs.dateCompleted IS NOT NULL
+			AND ($1='' OR substr(rs.dateCompleted, 1, 4)=$1)
+	)`
+
+// GetStats computes reading statistics from finished reading sessions. If year
+// is 0, statistics of all time are returned.
+func (s *Store) GetStats(year int) (*dusk.Stats, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stats := &dusk.Stats{Year: year}
+
+		var y string
+		if year > 0 {
+			y = strconv.Itoa(year)
+		}
+
+		if err := getTotalStats(tx, y, stats); err != nil {
+			return nil, fmt.Errorf("[db] failed to query total stats: %w", err)
+		}
+
+		periods, err := getPeriodStats(tx, y)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to query periodic stats: %w", err)
+		}
+		stats.Periods = periods
+
+		days, err := getDaysToFinish(tx, y)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to query time to finish: %w", err)
+		}
+		stats.TimeToFinish = dusk.TimeToFinish(days)
+
+		stats.TopAuthors, err = getTopCounts(tx, y, "book_author_link", "author")
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to query top authors: %w", err)
+		}
+		stats.TopTags, err = getTopCounts(tx, y, "book_tag_link", "tag")
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to query top tags: %w", err)
+		}
+
+		stats.Decades, err = getDecadeStats(tx, y)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to query publication decades: %w", err)
+		}
+
+		activity, err := getActivityDays(tx)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to query reading activity: %w", err)
+		}
+		stats.Streak = dusk.ReadingStreak(activity, time.Now())
+
+		stmt := `SELECT DISTINCT CAST(substr(dateCompleted, 1, 4) AS INTEGER) AS year
+			FROM reading_session
+			WHERE state=1 AND dateCompleted IS NOT NULL
+			ORDER BY year DESC;`
+		if err := tx.Select(&stats.Years, stmt); err != nil {
+			return nil, fmt.Errorf("[db] failed to query years: %w", err)
+		}
+		return stats, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Stats), nil
+}
+
+func getTotalStats(tx *sqlx.Tx, year string, stats *dusk.Stats) error {
+	stmt := finishedSessions + `
+		SELECT COUNT(*), COALESCE(SUM(b.numOfPages), 0), COALESCE(AVG(NULLIF(b.rating, 0)), 0)
+		FROM finished f
+			INNER JOIN book b ON b.id=f.bookId;`
+
+	return tx.QueryRowx(stmt, year).Scan(&stats.BooksRead, &stats.PagesRead, &stats.AverageRating)
+}
+
+// books and pages read per month of a year, or per year of all time
+func getPeriodStats(tx *sqlx.Tx, year string) ([]dusk.PeriodCount, error) {
+	length := 4
+	if year != "" {
+		length = 7
+	}
+
+	var periods []dusk.PeriodCount
+	stmt := finishedSessions + `
+		SELECT substr(f.dateCompleted, 1, $2) AS period,
+			COUNT(*) AS books,
+			COALESCE(SUM(b.numOfPages), 0) AS pages
+		FROM finished f
+			INNER JOIN book b ON b.id=f.bookId
+		GROUP BY period
+		ORDER BY period;`
+
+	if err := tx.Select(&periods, stmt, year, length); err != nil {
+		return nil, err
+	}
+	return periods, nil
+}
+
+func getDaysToFinish(tx *sqlx.Tx, year string) ([]int, error) {
+	var days []int
+	stmt := finishedSessions + `
+		SELECT CAST(julianday(substr(f.dateCompleted, 1, 10)) - julianday(substr(f.dateStarted, 1, 10)) AS INTEGER)
+		FROM finished f
+		WHERE f.dateStarted IS NOT NULL;`
+
+	if err := tx.Select(&days, stmt, year); err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+// most read authors or tags
+func getTopCounts(tx *sqlx.Tx, year, linkTable, table string) ([]dusk.Count, error) {
+	var counts []dusk.Count
+	stmt := finishedSessions + fmt.Sprintf(`
+		SELECT x.name AS name, COUNT(*) AS count
+		FROM finished f
+			INNER JOIN %[1]s l ON l.book=f.bookId
+			INNER JOIN %[2]s x ON x.id=l.%[2]s
+		GROUP BY x.id
+		ORDER BY count DESC, x.name
+		LIMIT $2;`, linkTable, table)
+
+	if err := tx.Select(&counts, stmt, year, topStatsLimit); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func getDecadeStats(tx *sqlx.Tx, year string) ([]dusk.Count, error) {
+	var counts []dusk.Count
+	stmt := finishedSessions + `
+		SELECT (CAST(substr(b.datePublished, 1, 4) AS INTEGER) / 10 * 10) || 's' AS name,
+			COUNT(*) AS count
+		FROM finished f
+			INNER JOIN book b ON b.id=f.bookId
+		WHERE b.datePublished IS NOT NULL
+		GROUP BY name
+		ORDER BY name;`
+
+	if err := tx.Select(&counts, stmt, year); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// days on which a reading session was started, updated or finished
+func getActivityDays(tx *sqlx.Tx) ([]time.Time, error) {
+	var days []string
+	stmt := `SELECT substr(timestamp, 1, 10) AS day FROM reading_progress
+		UNION SELECT substr(dateStarted, 1, 10) FROM reading_session WHERE dateStarted IS NOT NULL
+		UNION SELECT substr(dateCompleted, 1, 10) FROM reading_session WHERE dateCompleted IS NOT NULL;`
+
+	if err := tx.Select(&days, stmt); err != nil {
+		return nil, err
+	}
+
+	var result []time.Time
+	for _, d := range days {
+		t, err := time.Parse(time.DateOnly, d)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q: %w", d, err)
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
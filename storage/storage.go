@@ -48,7 +48,8 @@ func (s *Store) Close() error {
 	return errors.New("db: database does not exist")
 }
 
-func (s *Store) MigrateUp(filePath string) error {
+// execFile executes an embedded sql file outside of the versioned migrations.
+func (s *Store) execFile(filePath string) error {
 	schema, err := migrationFs.ReadFile(fmt.Sprintf("migrations/%s", filePath))
 	if err != nil {
 		return fmt.Errorf("db: cannot read sql file %q: %w", filePath, err)
@@ -57,6 +58,6 @@ func (s *Store) MigrateUp(filePath string) error {
 		return fmt.Errorf("db: failed to execute sql file %q: %w", filePath, err)
 	}
 
-	slog.Debug("Database schema loaded", "schema", filePath)
+	slog.Debug("Database sql file executed", "file", filePath)
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"log"
 	"os"
+	"slices"
 	"testing"
 
 	"github.com/jmoiron/sqlx"
@@ -15,7 +16,6 @@ var (
 	testdb  *sqlx.DB
 	testDSN string
 
-	testSchemaPath  = "schema.sql"
 	resetSchemaPath = "reset.sql"
 )
 
@@ -41,7 +41,7 @@ func testMainWrapper(m *testing.M) int {
 	}
 	ts = New(db)
 
-	err = ts.MigrateUp(testSchemaPath)
+	err = ts.MigrateUp()
 	if err != nil {
 		log.Print(err)
 	}
@@ -72,7 +72,7 @@ func TestOpen(t *testing.T) {
 }
 
 func resetDB() {
-	if err := ts.MigrateUp(resetSchemaPath); err != nil {
+	if err := ts.execFile(resetSchemaPath); err != nil {
 		log.Print(err)
 	}
 
@@ -86,3 +86,31 @@ func prettyPrint(i interface{}) string {
 	s, _ := json.MarshalIndent(i, "", "\t")
 	return string(s)
 }
+
+func TestBackfillAuthorSortNames(t *testing.T) {
+	defer resetDB()
+
+	stmt := `INSERT INTO author (name) VALUES ('Ludwig van Beethoven'), ('Homer');`
+	if _, err := ts.db.Exec(stmt); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := ts.db.MustBegin()
+	if err := backfillAuthorSortNames(tx); err != nil {
+		tx.Rollback()
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	stmt = `SELECT sortName FROM author WHERE name IN ('Ludwig van Beethoven', 'Homer') ORDER BY id;`
+	if err := ts.db.Select(&got, stmt); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"van Beethoven, Ludwig", "Homer"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
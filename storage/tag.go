@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/kencx/dusk"
 	"github.com/kencx/dusk/filters"
@@ -14,17 +15,7 @@ import (
 
 func (s *Store) GetTag(id int64) (*dusk.Tag, error) {
 	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
-		var tag dusk.Tag
-		stmt := `SELECT * FROM tag WHERE id=$1;`
-
-		err := tx.QueryRowx(stmt, id).StructScan(&tag)
-		if err == sql.ErrNoRows {
-			return nil, dusk.ErrDoesNotExist
-		}
-		if err != nil {
-			return nil, fmt.Errorf("[db] failed to retrieve tag %d: %w", id, err)
-		}
-		return &tag, nil
+		return getTag(tx, id)
 	})
 
 	if err != nil {
@@ -77,10 +68,15 @@ func (s *Store) GetAllTags(f *filters.Search) (*page.Page[dusk.Tag], error) {
 	return i.(*page.Page[dusk.Tag]), nil
 }
 
+// GetAllBooksFromTag returns the books with the tag or any of its descendants
 func (s *Store) GetAllBooksFromTag(id int64, f *filters.Book) (*page.Page[dusk.Book], error) {
 	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
 		var dest []BookQueryRow
-		query := buildPagedStmt(&f.Base, "book_view", `WHERE t.id IN (SELECT book FROM book_tag_link WHERE tag=$1)`)
+		query := buildPagedStmt(&f.Base, "book_view", `WHERE t.id IN (SELECT bt.book
+			FROM book_tag_link bt
+				INNER JOIN tag tg ON tg.id=bt.tag
+				INNER JOIN tag p ON p.id=$1
+			WHERE tg.id=p.id OR substr(tg.name, 1, length(p.name)+1)=p.name || '.')`)
 
 		slog.Info("Running SQL query",
 			slog.String("stmt", query),
@@ -109,6 +105,7 @@ func (s *Store) GetAllBooksFromTag(id int64, f *filters.Book) (*page.Page[dusk.B
 
 func (s *Store) CreateTag(t *dusk.Tag) (*dusk.Tag, error) {
 	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		t.Name = dusk.NormaliseTagName(t.Name)
 		id, err := insertTag(tx, t.Name)
 		if err != nil {
 			return nil, fmt.Errorf("[db] failed to create tag: %w", err)
@@ -123,12 +120,31 @@ func (s *Store) CreateTag(t *dusk.Tag) (*dusk.Tag, error) {
 	return i.(*dusk.Tag), nil
 }
 
+// UpdateTag renames the tag and all of its descendants, e.g. renaming fiction
+// to novels renames fiction.scifi to novels.scifi. Any missing parents of the
+// new name are created.
 func (s *Store) UpdateTag(id int64, a *dusk.Tag) (*dusk.Tag, error) {
 	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		var old string
+		if err := tx.Get(&old, `SELECT name FROM tag WHERE id=$1;`, id); err != nil {
+			if err == sql.ErrNoRows {
+				return nil, dusk.ErrNoChange
+			}
+			return nil, fmt.Errorf("[db] failed to update tag %d: %w", id, err)
+		}
+
+		a.Name = dusk.NormaliseTagName(a.Name)
+		if a.IsDescendantOf(old) {
+			return nil, fmt.Errorf("[db] failed to rename tag %d: %w", id, dusk.ErrTagCycle)
+		}
+
 		stmt := `UPDATE tag SET name=$1 WHERE id=$2`
 		res, err := tx.Exec(stmt, a.Name, id)
 
 		if err != nil {
+			if isUniqueConstraintErr(err) {
+				return nil, fmt.Errorf("[db] failed to rename tag %d: %w", id, dusk.ErrUniqueConstraint)
+			}
 			return nil, fmt.Errorf("[db] failed to update tag %d: %w", id, err)
 		}
 		count, err := res.RowsAffected()
@@ -138,6 +154,21 @@ func (s *Store) UpdateTag(id int64, a *dusk.Tag) (*dusk.Tag, error) {
 		if count == 0 {
 			return nil, dusk.ErrNoChange
 		}
+
+		stmt = `UPDATE tag SET name=$1 || substr(name, length($2)+1)
+			WHERE substr(name, 1, length($2)+1)=$2 || '.';`
+		if _, err := tx.Exec(stmt, a.Name, old); err != nil {
+			if isUniqueConstraintErr(err) {
+				return nil, fmt.Errorf("[db] failed to rename descendants of tag %d: %w", id, dusk.ErrUniqueConstraint)
+			}
+			return nil, fmt.Errorf("[db] failed to rename descendants of tag %d: %w", id, err)
+		}
+
+		if err := insertTagAncestors(tx, a.Name); err != nil {
+			return nil, fmt.Errorf("[db] failed to update tag %d: %w", id, err)
+		}
+
+		a.Id = id
 		return a, nil
 	})
 
@@ -172,8 +203,135 @@ func (s *Store) DeleteTag(id int64) error {
 	return err
 }
 
+// MergeTags moves the books and child tags of the given tags to the tag id and
+// deletes them. Books linked to both tags are only linked once. Child tags
+// with the same name as an existing child are merged into it.
+func (s *Store) MergeTags(id int64, ids []int64) (*dusk.Tag, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		tag, err := getTag(tx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, otherId := range ids {
+			if otherId == id {
+				return nil, fmt.Errorf("[db] failed to merge tag %d: cannot merge tag with itself", id)
+			}
+			other, err := getTag(tx, otherId)
+			if err != nil {
+				return nil, err
+			}
+
+			// the tag may have been renamed by an earlier merge
+			if tag, err = getTag(tx, id); err != nil {
+				return nil, err
+			}
+			if err := mergeTag(tx, tag, other); err != nil {
+				return nil, err
+			}
+		}
+		return getTag(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Tag), nil
+}
+
+// DeleteUnusedTags deletes all tags without books, including the books of
+// their descendants, and returns the number of deleted tags.
+func (s *Store) DeleteUnusedTags() (int64, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stmt := `DELETE FROM tag WHERE NOT EXISTS (SELECT 1
+			FROM book_tag_link bt
+				INNER JOIN tag tg ON tg.id=bt.tag
+			WHERE tg.id=tag.id OR substr(tg.name, 1, length(tag.name)+1)=tag.name || '.');`
+		res, err := tx.Exec(stmt)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete unused tags: %w", err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete unused tags: %w", err)
+		}
+		return count, nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return i.(int64), nil
+}
+
+func getTag(tx *sqlx.Tx, id int64) (*dusk.Tag, error) {
+	var tag dusk.Tag
+	stmt := `SELECT * FROM tag_view WHERE id=$1;`
+
+	err := tx.QueryRowx(stmt, id).StructScan(&tag)
+	if err == sql.ErrNoRows {
+		return nil, dusk.ErrDoesNotExist
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve tag %d: %w", id, err)
+	}
+	return &tag, nil
+}
+
+// mergeTag merges other and its children into tag
+func mergeTag(tx *sqlx.Tx, tag, other *dusk.Tag) error {
+	if tag.IsDescendantOf(other.Name) {
+		return fmt.Errorf("[db] failed to merge tag %d into tag %d: %w", other.Id, tag.Id, dusk.ErrTagCycle)
+	}
+
+	var children []*dusk.Tag
+	stmt := `SELECT * FROM tag WHERE substr(name, 1, length($1)+1)=$1 || '.' ORDER BY name;`
+	if err := tx.Select(&children, stmt, other.Name); err != nil {
+		return fmt.Errorf("[db] failed to retrieve children of tag %d: %w", other.Id, err)
+	}
+
+	for _, child := range children {
+		if child.Parent() != other.Name {
+			continue
+		}
+
+		name := tag.Name + strings.TrimPrefix(child.Name, other.Name)
+		var existing dusk.Tag
+		err := tx.Get(&existing, `SELECT * FROM tag WHERE name=$1;`, name)
+		if err == nil {
+			if err := mergeTag(tx, &existing, child); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != sql.ErrNoRows {
+			return fmt.Errorf("[db] failed to retrieve tag %q: %w", name, err)
+		}
+
+		stmt = `UPDATE tag SET name=$1 || substr(name, length($2)+1)
+			WHERE name=$2 OR substr(name, 1, length($2)+1)=$2 || '.';`
+		if _, err := tx.Exec(stmt, name, child.Name); err != nil {
+			return fmt.Errorf("[db] failed to move tag %d under tag %d: %w", child.Id, tag.Id, err)
+		}
+	}
+
+	stmt = `INSERT OR IGNORE INTO book_tag_link (book, tag)
+		SELECT book, $1 FROM book_tag_link WHERE tag=$2;`
+	if _, err := tx.Exec(stmt, tag.Id, other.Id); err != nil {
+		return fmt.Errorf("[db] failed to link books of tag %d to tag %d: %w", other.Id, tag.Id, err)
+	}
+
+	// delete cascaded to book_tag_link table
+	stmt = `DELETE FROM tag WHERE id=$1;`
+	if _, err := tx.Exec(stmt, other.Id); err != nil {
+		return fmt.Errorf("[db] failed to delete tag %d: %w", other.Id, err)
+	}
+	return nil
+}
+
 func queryTags(tx *sqlx.Tx, filters *filters.Search, dest *[]TagQueryRow) error {
-	query, params := buildSearchQuery("tag", filters)
+	query, params := buildSearchQuery("tag_view", "tag", filters)
 
 	slog.Info("Running SQL query",
 		slog.String("stmt", query),
@@ -186,8 +344,14 @@ func queryTags(tx *sqlx.Tx, filters *filters.Search, dest *[]TagQueryRow) error
 	return nil
 }
 
-// Insert given tag. If tag already exists, return its id instead
+// Insert given tag and any missing parents. If tag already exists, return its
+// id instead
 func insertTag(tx *sqlx.Tx, t string) (int64, error) {
+	t = dusk.NormaliseTagName(t)
+	if err := insertTagAncestors(tx, t); err != nil {
+		return -1, err
+	}
+
 	stmt := `INSERT OR IGNORE INTO tag (name) VALUES ($1);`
 	res, err := tx.Exec(stmt, t)
 	if err != nil {
@@ -219,6 +383,17 @@ func insertTag(tx *sqlx.Tx, t string) (int64, error) {
 	}
 }
 
+// insert the missing parents of a tag so that the hierarchy is complete
+func insertTagAncestors(tx *sqlx.Tx, t string) error {
+	stmt := `INSERT OR IGNORE INTO tag (name) VALUES ($1);`
+	for _, name := range (dusk.Tag{Name: t}).Ancestors() {
+		if _, err := tx.Exec(stmt, name); err != nil {
+			return fmt.Errorf("failed to insert parent tag %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
 // Insert given slice of tags and returns slice of tag IDs. If tag already
 // exists, its ID is appended to the result
 func insertTags(tx *sqlx.Tx, tags []string) ([]int64, error) {
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/kencx/dusk"
@@ -13,8 +14,8 @@ func TestGetTag(t *testing.T) {
 	got, err := ts.GetTag(testTag1.Id)
 	is.NoErr(err)
 
-	want := testTag1
-	is.Equal(got, want)
+	is.Equal(got.Id, testTag1.Id)
+	is.Equal(got.Name, testTag1.Name)
 }
 
 func TestGetTagNotExists(t *testing.T) {
@@ -44,32 +45,32 @@ func TestGetTagNotExists(t *testing.T) {
 
 func TestGetAllTags(t *testing.T) {
 	is := is.New(t)
-	got, err := ts.GetAllTags()
+	got, err := ts.GetAllTags(testFilters())
 	is.NoErr(err)
 
 	want := allTestTags
-	is.Equal(got, want)
+	is.Equal(len(got.Items), len(want))
+	for i := range want {
+		is.Equal(got.Items[i].Id, want[i].Id)
+		is.Equal(got.Items[i].Name, want[i].Name)
+	}
 }
 
 func TestGetAllTagEmpty(t *testing.T) {
 	defer resetDB()
 
 	// delete all data
-	if err := ts.MigrateUp(resetSchemaPath); err != nil {
+	if err := ts.execFile(resetSchemaPath); err != nil {
 		t.Errorf("failed to reset database")
 	}
 
-	got, err := ts.GetAllTags()
-	if err == nil {
-		t.Errorf("expected error: ErrNoRows")
-	}
-
-	if err != dusk.ErrNoRows {
+	got, err := ts.GetAllTags(testFilters())
+	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
 
-	if got != nil {
-		t.Errorf("got %v, want nil", got)
+	if len(got.Items) != 0 {
+		t.Errorf("got %v, want no items", got.Items)
 	}
 }
 
@@ -78,8 +79,9 @@ func TestGetAllBooksFromTag(t *testing.T) {
 
 	is := is.New(t)
 
-	got, err := ts.GetAllBooksFromTag(testTag1.Id)
+	result, err := ts.GetAllBooksFromTag(testTag1.Id, testBookFilters())
 	is.NoErr(err)
+	got := result.Items
 
 	want := dusk.Books{testBook1}
 	is.True(len(got) == len(want))
@@ -94,9 +96,10 @@ func TestCreateTag(t *testing.T) {
 	is := is.New(t)
 	want := &dusk.Tag{Name: "FooBar"}
 
+	// tag names are lowercased
 	got, err := ts.CreateTag(want)
 	is.NoErr(err)
-	is.Equal(got.Name, want.Name)
+	is.Equal(got.Name, "foobar")
 }
 
 func TestCreateTagDuplicates(t *testing.T) {
@@ -211,3 +214,178 @@ func TestDeleteTagNotExists(t *testing.T) {
 		t.Errorf("expected error: tag not exists")
 	}
 }
+
+// tagId returns the id of the tag with the given name
+func tagId(t *testing.T, name string) int64 {
+	t.Helper()
+
+	var id int64
+	if err := ts.db.Get(&id, `SELECT id FROM tag WHERE name=$1;`, name); err != nil {
+		t.Fatalf("tag %q: %v", name, err)
+	}
+	return id
+}
+
+func TestGetAllBooksFromTagDescendants(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	books := map[string]*dusk.Book{}
+	for _, tag := range []string{"Fiction", "fiction.SciFi", "fiction-adjacent"} {
+		b, err := ts.CreateBook(&dusk.Book{
+			Title:  tag,
+			Author: []string{testAuthor1.Name},
+			Tag:    []string{tag},
+		})
+		is.NoErr(err)
+		books[tag] = b
+	}
+
+	result, err := ts.GetAllBooksFromTag(tagId(t, "fiction"), testBookFilters())
+	is.NoErr(err)
+	is.Equal(len(result.Items), 2)
+	is.Equal(result.Items[0].Id, books["Fiction"].Id)
+	is.Equal(result.Items[1].Id, books["fiction.SciFi"].Id)
+}
+
+func TestUpdateTagDescendants(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	_, err := ts.CreateBook(&dusk.Book{
+		Title:  "Book 5",
+		Author: []string{testAuthor1.Name},
+		Tag:    []string{"Fiction.SciFi", "fiction.fantasy"},
+	})
+	is.NoErr(err)
+
+	_, err = ts.UpdateTag(tagId(t, "fiction"), &dusk.Tag{Name: "Novels"})
+	is.NoErr(err)
+
+	var got []string
+	is.NoErr(ts.db.Select(&got, `SELECT name FROM tag WHERE name LIKE '%fiction%' OR name LIKE 'novels%' ORDER BY name;`))
+	is.Equal(got, []string{"novels", "novels.fantasy", "novels.scifi"})
+}
+
+func TestNormaliseTagNames(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	for _, name := range []string{"Fiction", "fiction", "Fiction.SciFi"} {
+		_, err := ts.db.Exec(`INSERT INTO tag (name) VALUES ($1);`, name)
+		is.NoErr(err)
+	}
+	_, err := ts.db.Exec(`INSERT INTO book_tag_link (book, tag) VALUES ($1, $2), ($3, $4), ($3, $5);`,
+		testBook1.Id, tagId(t, "Fiction"), testBook2.Id, tagId(t, "fiction"), tagId(t, "Fiction.SciFi"))
+	is.NoErr(err)
+
+	tx := ts.db.MustBegin()
+	if err := normaliseTagNames(tx); err != nil {
+		tx.Rollback()
+		t.Fatal(err)
+	}
+	is.NoErr(tx.Commit())
+
+	var names []string
+	is.NoErr(ts.db.Select(&names, `SELECT name FROM tag WHERE lower(name) LIKE 'fiction%' ORDER BY name;`))
+	is.Equal(names, []string{"fiction", "fiction.scifi"})
+
+	// books of merged tags are kept
+	result, err := ts.GetAllBooksFromTag(tagId(t, "fiction"), testBookFilters())
+	is.NoErr(err)
+	is.Equal(len(result.Items), 2)
+}
+
+func TestMergeTags(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	books := map[string]int64{}
+	for title, tags := range map[string][]string{
+		"Book A": {"fiction.scifi"},
+		"Book B": {"novels.scifi"},
+		"Book C": {"fiction", "novels"},
+		"Book D": {"novels.horror"},
+		"Book E": {"fiction.fantasy"},
+	} {
+		b, err := ts.CreateBook(&dusk.Book{Title: title, Author: []string{testAuthor1.Name}, Tag: tags})
+		is.NoErr(err)
+		books[title] = b.Id
+	}
+
+	fiction := tagId(t, "fiction")
+	got, err := ts.MergeTags(fiction, []int64{tagId(t, "novels")})
+	is.NoErr(err)
+	is.Equal(got.Name, "fiction")
+
+	// overlapping children are merged and the others are moved
+	var names []string
+	is.NoErr(ts.db.Select(&names, `SELECT name FROM tag WHERE name LIKE 'fiction%' OR name LIKE 'novels%' ORDER BY name;`))
+	is.Equal(names, []string{"fiction", "fiction.fantasy", "fiction.horror", "fiction.scifi"})
+
+	var scifi []int64
+	is.NoErr(ts.db.Select(&scifi, `SELECT book FROM book_tag_link WHERE tag=$1 ORDER BY book;`, tagId(t, "fiction.scifi")))
+	is.Equal(len(scifi), 2)
+
+	var horror []int64
+	is.NoErr(ts.db.Select(&horror, `SELECT book FROM book_tag_link WHERE tag=$1;`, tagId(t, "fiction.horror")))
+	is.Equal(horror, []int64{books["Book D"]})
+
+	// a book linked to both tags is linked once
+	var count int
+	is.NoErr(ts.db.Get(&count, `SELECT COUNT(*) FROM book_tag_link WHERE book=$1;`, books["Book C"]))
+	is.Equal(count, 1)
+
+	result, err := ts.GetAllBooksFromTag(fiction, testBookFilters())
+	is.NoErr(err)
+	is.Equal(len(result.Items), 5)
+}
+
+func TestMergeTagsInvalid(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	_, err := ts.CreateBook(&dusk.Book{
+		Title:  "Book 5",
+		Author: []string{testAuthor1.Name},
+		Tag:    []string{"fiction.scifi"},
+	})
+	is.NoErr(err)
+
+	fiction := tagId(t, "fiction")
+	_, err = ts.MergeTags(fiction, []int64{fiction})
+	is.True(err != nil)
+
+	// a tag cannot be merged into its own descendant
+	_, err = ts.MergeTags(tagId(t, "fiction.scifi"), []int64{fiction})
+	is.True(errors.Is(err, dusk.ErrTagCycle))
+
+	_, err = ts.MergeTags(fiction, []int64{-1})
+	is.True(errors.Is(err, dusk.ErrDoesNotExist))
+}
+
+func TestDeleteUnusedTags(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	for _, name := range []string{"unused", "unused.child"} {
+		_, err := ts.CreateTag(&dusk.Tag{Name: name})
+		is.NoErr(err)
+	}
+	_, err := ts.CreateBook(&dusk.Book{
+		Title:  "Book 5",
+		Author: []string{testAuthor1.Name},
+		Tag:    []string{"parent.child"},
+	})
+	is.NoErr(err)
+
+	count, err := ts.DeleteUnusedTags()
+	is.NoErr(err)
+
+	// a tag is used if it or any of its descendants has books
+	is.Equal(count, int64(2))
+
+	var names []string
+	is.NoErr(ts.db.Select(&names, `SELECT name FROM tag ORDER BY name;`))
+	is.Equal(names, []string{"parent", "parent.child", testTag1.Name, testTag2.Name, testTag3.Name})
+}
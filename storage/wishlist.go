@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// GetWishlist returns all books on the wishlist with their details, by
+// priority and then by date added.
+func (s *Store) GetWishlist() ([]dusk.Book, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		var dest []struct {
+			BookRow
+			Price    null.Float  `db:"wishlist_price"`
+			Priority int         `db:"wishlist_priority"`
+			Notes    null.String `db:"wishlist_notes"`
+		}
+		stmt := `SELECT b.*,
+				w.price AS wishlist_price,
+				COALESCE(w.priority, 0) AS wishlist_priority,
+				w.notes AS wishlist_notes
+			FROM book_view b
+				LEFT JOIN wishlist w ON w.bookId=b.id
+			WHERE b.ownership=$1
+			ORDER BY wishlist_priority DESC, b.dateAdded, b.id;`
+
+		if err := tx.Select(&dest, stmt, dusk.Wishlisted); err != nil {
+			return nil, fmt.Errorf("[db] failed to retrieve wishlist: %w", err)
+		}
+
+		var books []dusk.Book
+		for _, row := range dest {
+			b := row.Book
+			b.Author = strings.Split(row.AuthorString, ",")
+			b.Tag = row.TagString.Split(",")
+			b.Isbn10 = row.Isbn10String.Split(",")
+			b.Isbn13 = row.Isbn13String.Split(",")
+			b.Formats = row.FormatString.Split(",")
+			b.Series = row.SeriesString
+			b.SeriesPosition = row.SeriesPosition
+			b.Wishlist = &dusk.WishlistItem{
+				Price:    row.Price,
+				Priority: row.Priority,
+				Notes:    row.Notes,
+			}
+			books = append(books, *b)
+		}
+		return books, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.([]dusk.Book), nil
+}
+
+func getWishlistItemFromBook(tx *sqlx.Tx, bookId int64) (*dusk.WishlistItem, error) {
+	var w dusk.WishlistItem
+	stmt := `SELECT price, priority, notes FROM wishlist WHERE bookId=$1;`
+
+	if err := tx.QueryRowx(stmt, bookId).StructScan(&w); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, dusk.ErrDoesNotExist
+		}
+		return nil, err
+	}
+	return &w, nil
+}
+
+// updateBookWishlist stores the wishlist details of a book on the wishlist. They
+// are removed only when the book leaves the wishlist, while a book without
+// wishlist details keeps its current ones.
+func updateBookWishlist(tx *sqlx.Tx, id int64, b *dusk.Book) error {
+	if b.Ownership != dusk.Wishlisted {
+		if _, err := tx.Exec(`DELETE FROM wishlist WHERE bookId=$1;`, id); err != nil {
+			return fmt.Errorf("[db] failed to remove book %d from wishlist: %w", id, err)
+		}
+		b.Wishlist = nil
+		return nil
+	}
+
+	if b.Wishlist == nil {
+		current, err := getWishlistItemFromBook(tx, id)
+		if err != nil && !errors.Is(err, dusk.ErrDoesNotExist) {
+			return fmt.Errorf("[db] failed to retrieve wishlist of book %d: %w", id, err)
+		}
+		b.Wishlist = current
+		return nil
+	}
+
+	stmt := `INSERT INTO wishlist (bookId, price, priority, notes)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bookId) DO UPDATE SET
+			price=excluded.price,
+			priority=excluded.priority,
+			notes=excluded.notes;`
+	if _, err := tx.Exec(stmt, id, b.Wishlist.Price, b.Wishlist.Priority, b.Wishlist.Notes); err != nil {
+		return fmt.Errorf("[db] failed to update wishlist of book %d: %w", id, err)
+	}
+	return nil
+}
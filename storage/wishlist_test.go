@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/null"
+	"github.com/matryer/is"
+)
+
+func TestUpdateBookWishlist(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	want := &dusk.WishlistItem{Price: null.FloatFrom(12.5), Priority: 2, Notes: null.StringFrom("gift")}
+
+	b, err := ts.CreateBook(&dusk.Book{
+		Title:     "Wishlisted",
+		Author:    []string{testAuthor1.Name},
+		Ownership: dusk.Wishlisted,
+		Wishlist:  want,
+	})
+	is.NoErr(err)
+
+	// a book without wishlist details keeps its current ones
+	b.Wishlist = nil
+	got, err := ts.UpdateBook(b.Id, b)
+	is.NoErr(err)
+	is.Equal(got.Wishlist, want)
+
+	b, err = ts.GetBook(b.Id)
+	is.NoErr(err)
+	is.Equal(b.Wishlist, want)
+
+	// leaving the wishlist removes them
+	b.Ownership = dusk.Owned
+	_, err = ts.UpdateBook(b.Id, b)
+	is.NoErr(err)
+
+	b, err = ts.GetBook(b.Id)
+	is.NoErr(err)
+	is.Equal(b.Wishlist, nil)
+}
@@ -0,0 +1,375 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/kencx/dusk"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const workStmt = `SELECT w.id, w.title, w.rating, w.openlibrary, COUNT(bw.book) AS editionCount
+	FROM work w
+		LEFT JOIN book_work_link bw ON bw.work=w.id`
+
+func (s *Store) GetWork(id int64) (*dusk.Work, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		return getWork(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Work), nil
+}
+
+// GetAllWorks returns all works by title, without their editions.
+func (s *Store) GetAllWorks() ([]dusk.Work, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		var works []dusk.Work
+		stmt := workStmt + ` GROUP BY w.id ORDER BY w.title;`
+
+		if err := tx.Select(&works, stmt); err != nil {
+			return nil, fmt.Errorf("[db] failed to retrieve works: %w", err)
+		}
+		return works, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.([]dusk.Work), nil
+}
+
+// UpdateWork updates the title, rating and Open Library key of a work. The
+// rating is shared with all its editions.
+func (s *Store) UpdateWork(id int64, w *dusk.Work) (*dusk.Work, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stmt := `UPDATE work SET title=$1, rating=$2, openlibrary=$3 WHERE id=$4;`
+		res, err := tx.Exec(stmt, w.Title, w.Rating, w.OpenLibrary, id)
+		if err != nil {
+			if isUniqueConstraintErr(err) {
+				return nil, fmt.Errorf("[db] failed to update work %d: %w", id, dusk.ErrUniqueConstraint)
+			}
+			return nil, fmt.Errorf("[db] failed to update work %d: %w", id, err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to update work %d: %w", id, err)
+		}
+		if count == 0 {
+			return nil, dusk.ErrNoChange
+		}
+
+		stmt = `UPDATE book SET rating=$1
+			WHERE id IN (SELECT book FROM book_work_link WHERE work=$2);`
+		if _, err := tx.Exec(stmt, w.Rating, id); err != nil {
+			return nil, fmt.Errorf("[db] failed to update rating of editions of work %d: %w", id, err)
+		}
+		return getWork(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Work), nil
+}
+
+// Deleting a work does not delete its editions.
+func (s *Store) DeleteWork(id int64) error {
+	_, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		// delete cascaded to book_work_link table
+		stmt := `DELETE FROM work WHERE id=$1;`
+		res, err := tx.Exec(stmt, id)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete work %d: %w", id, err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to delete work %d: %w", id, err)
+		}
+		if count == 0 {
+			return nil, dusk.ErrNoChange
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// AddBookToWork makes the book an edition of the work, moving it from any
+// other work.
+func (s *Store) AddBookToWork(id, bookId int64) (*dusk.Work, error) {
+	i, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		if _, err := getWork(tx, id); err != nil {
+			return nil, err
+		}
+		b, err := getBook(tx, bookId)
+		if err != nil {
+			return nil, err
+		}
+
+		b.Work = &dusk.Work{Id: id}
+		if err := updateBookWork(tx, b); err != nil {
+			return nil, err
+		}
+		if err := deleteWorksWithNoBooks(tx); err != nil {
+			return nil, fmt.Errorf("[db] %w", err)
+		}
+		return getWork(tx, id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return i.(*dusk.Work), nil
+}
+
+// RemoveBookFromWork removes the edition from the work. A work with no
+// remaining editions is deleted.
+func (s *Store) RemoveBookFromWork(id, bookId int64) error {
+	_, err := Tx(s.db, func(tx *sqlx.Tx) (any, error) {
+		stmt := `DELETE FROM book_work_link WHERE work=$1 AND book=$2;`
+		res, err := tx.Exec(stmt, id, bookId)
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to remove book %d from work %d: %w", bookId, id, err)
+		}
+
+		count, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("[db] failed to remove book %d from work %d: %w", bookId, id, err)
+		}
+		if count == 0 {
+			return nil, dusk.ErrNoChange
+		}
+
+		if err := deleteWorksWithNoBooks(tx); err != nil {
+			return nil, fmt.Errorf("[db] %w", err)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func getWork(tx *sqlx.Tx, id int64) (*dusk.Work, error) {
+	var w dusk.Work
+	stmt := workStmt + ` WHERE w.id=$1 GROUP BY w.id;`
+
+	err := tx.QueryRowx(stmt, id).StructScan(&w)
+	if err == sql.ErrNoRows {
+		return nil, dusk.ErrDoesNotExist
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve work %d: %w", id, err)
+	}
+
+	editions, err := getBooksFromWork(tx, id)
+	if err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve editions of work %d: %w", id, err)
+	}
+	w.Editions = editions
+
+	sessions, err := getSessionsFromWork(tx, id)
+	if err != nil {
+		return nil, fmt.Errorf("[db] failed to retrieve reading sessions of work %d: %w", id, err)
+	}
+	w.Sessions = sessions
+	return &w, nil
+}
+
+// get the work of a book, without its editions
+func getWorkFromBook(tx *sqlx.Tx, bookId int64) (*dusk.Work, error) {
+	var w dusk.Work
+	stmt := workStmt + `
+		WHERE w.id=(SELECT work FROM book_work_link WHERE book=$1)
+		GROUP BY w.id;`
+
+	err := tx.QueryRowx(stmt, bookId).StructScan(&w)
+	if err == sql.ErrNoRows {
+		return nil, dusk.ErrDoesNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// get all editions of work by date published. Editions with no date are
+// ordered last by title.
+func getBooksFromWork(tx *sqlx.Tx, id int64) ([]dusk.Book, error) {
+	var dest []BookRow
+	stmt := `SELECT b.*
+		FROM book_work_link bw
+		JOIN book_view b ON b.id=bw.book
+		WHERE bw.work=$1
+		ORDER BY b.datePublished IS NULL, b.datePublished, b.title`
+
+	if err := tx.Select(&dest, stmt, id); err != nil {
+		return nil, err
+	}
+
+	var books []dusk.Book
+	for _, row := range dest {
+		row.Author = strings.Split(row.AuthorString, ",")
+		row.Tag = row.TagString.Split(",")
+		row.Isbn10 = row.Isbn10String.Split(",")
+		row.Isbn13 = row.Isbn13String.Split(",")
+		row.Formats = row.FormatString.Split(",")
+		row.Series = row.SeriesString
+		row.Book.SeriesPosition = row.SeriesPosition
+		books = append(books, *row.Book)
+	}
+	return books, nil
+}
+
+// get the reading sessions of all editions of work in chronological order
+func getSessionsFromWork(tx *sqlx.Tx, id int64) ([]dusk.ReadingSession, error) {
+	var sessions []dusk.ReadingSession
+	stmt := `SELECT * FROM reading_session
+		WHERE bookId IN (SELECT book FROM book_work_link WHERE work=$1)
+		ORDER BY COALESCE(dateStarted, dateCompleted) IS NULL,
+			COALESCE(dateStarted, dateCompleted), id;`
+
+	if err := tx.Select(&sessions, stmt, id); err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, s := range sessions {
+		ids = append(ids, s.Id)
+	}
+
+	progress, err := getProgressFromSessions(tx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range sessions {
+		sessions[i].Progress = progress[s.Id]
+	}
+	return sessions, nil
+}
+
+// Insert given work. A work with an id must already exist, while a work with
+// an existing Open Library key is not inserted again. Return the id of the
+// work.
+func insertWork(tx *sqlx.Tx, w *dusk.Work) (int64, error) {
+	if w.Id != 0 {
+		var exists bool
+		if err := tx.Get(&exists, `SELECT EXISTS (SELECT 1 FROM work WHERE id=$1);`, w.Id); err != nil {
+			return -1, fmt.Errorf("failed to retrieve work %d: %w", w.Id, err)
+		}
+		if !exists {
+			return -1, fmt.Errorf("failed to retrieve work %d: %w", w.Id, dusk.ErrDoesNotExist)
+		}
+		return w.Id, nil
+	}
+
+	if w.OpenLibrary.ValueOrZero() != "" {
+		var id int64
+		err := tx.Get(&id, `SELECT id FROM work WHERE openlibrary=$1;`, w.OpenLibrary)
+		if err == nil {
+			return id, nil
+		}
+		if err != sql.ErrNoRows {
+			return -1, fmt.Errorf("failed to retrieve work %q: %w", w.OpenLibrary.String, err)
+		}
+	}
+
+	stmt := `INSERT INTO work (title, rating, openlibrary) VALUES ($1, $2, $3);`
+	res, err := tx.Exec(stmt, w.Title, w.Rating, w.OpenLibrary)
+	if err != nil {
+		return -1, fmt.Errorf("failed to insert work %q: %w", w.Title, err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, fmt.Errorf("failed to insert work %q: %w", w.Title, err)
+	}
+	return id, nil
+}
+
+// updateBookWork links the book to b.Work, creating the work if it does not
+// exist. A book with no work keeps its current work, and is only removed from
+// it with RemoveBookFromWork. The editions of a work share its rating: a newly
+// linked edition that is unrated takes the rating of the work, while rating or
+// clearing the rating of an edition rates the work and all other editions.
+func updateBookWork(tx *sqlx.Tx, b *dusk.Book) error {
+	current, err := getWorkFromBook(tx, b.Id)
+	if err != nil && !errors.Is(err, dusk.ErrDoesNotExist) {
+		return fmt.Errorf("[db] failed to get work from book %d: %w", b.Id, err)
+	}
+
+	work := b.Work
+	if work == nil {
+		if current == nil {
+			return nil
+		}
+		work = current
+	}
+
+	if work.Title == "" {
+		work.Title = b.Title
+	}
+	workId, err := insertWork(tx, work)
+	if err != nil {
+		return fmt.Errorf("[db] %w", err)
+	}
+
+	linked := current == nil || current.Id != workId
+	if linked {
+		stmt := `INSERT INTO book_work_link (book, work) VALUES ($1, $2)
+			ON CONFLICT (book) DO UPDATE SET work=excluded.work;`
+		if _, err := tx.Exec(stmt, b.Id, workId); err != nil {
+			return fmt.Errorf("[db] failed to link book %d to work %d: %w", b.Id, workId, err)
+		}
+
+		if b.Rating == 0 {
+			stmt := `SELECT rating FROM work WHERE id=$1;`
+			if err := tx.Get(&b.Rating, stmt, workId); err != nil {
+				return fmt.Errorf("[db] failed to retrieve rating of work %d: %w", workId, err)
+			}
+		}
+	}
+
+	if linked || b.Rating != current.Rating {
+		stmts := []string{
+			`UPDATE work SET rating=$1 WHERE id=$2;`,
+			`UPDATE book SET rating=$1 WHERE id IN (SELECT book FROM book_work_link WHERE work=$2);`,
+		}
+		for _, stmt := range stmts {
+			if _, err := tx.Exec(stmt, b.Rating, workId); err != nil {
+				return fmt.Errorf("[db] failed to share rating of work %d: %w", workId, err)
+			}
+		}
+	}
+
+	b.Work, err = getWorkFromBook(tx, b.Id)
+	if err != nil {
+		return fmt.Errorf("[db] failed to get work from book %d: %w", b.Id, err)
+	}
+	return nil
+}
+
+// delete all works that have no editions
+func deleteWorksWithNoBooks(tx *sqlx.Tx) error {
+	stmt := `DELETE FROM work WHERE id NOT IN
+				(SELECT work FROM book_work_link);`
+	res, err := tx.Exec(stmt)
+	if err != nil {
+		return fmt.Errorf("failed to delete works with no books: %w", err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete works with no books: %w", err)
+	}
+
+	if count != 0 {
+		slog.Debug("[db] deleted works", slog.Int64("count", count))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/kencx/dusk"
+	"github.com/matryer/is"
+)
+
+// create two rated editions of a work
+func createTestEditions(t *testing.T) (*dusk.Book, *dusk.Book) {
+	t.Helper()
+	is := is.New(t)
+
+	first, err := ts.CreateBook(&dusk.Book{
+		Title:  "Edition 1",
+		Author: []string{testAuthor1.Name},
+		Rating: 8,
+		Work:   &dusk.Work{Title: "Work"},
+	})
+	is.NoErr(err)
+
+	second, err := ts.CreateBook(&dusk.Book{
+		Title:  "Edition 2",
+		Author: []string{testAuthor1.Name},
+		Work:   &dusk.Work{Id: first.Work.Id},
+	})
+	is.NoErr(err)
+	is.Equal(second.Rating, 8)
+	return first, second
+}
+
+func TestUpdateBookWithoutWork(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	first, second := createTestEditions(t)
+
+	// a book without a work keeps its current work and rating
+	b, err := ts.GetBook(first.Id)
+	is.NoErr(err)
+	b.Work = nil
+	b.Title = "Edition 1 (revised)"
+	_, err = ts.UpdateBook(b.Id, b)
+	is.NoErr(err)
+
+	got, err := ts.GetBook(first.Id)
+	is.NoErr(err)
+	is.True(got.Work != nil)
+	is.Equal(got.Work.Id, first.Work.Id)
+	is.Equal(got.Rating, 8)
+
+	other, err := ts.GetBook(second.Id)
+	is.NoErr(err)
+	is.Equal(other.Rating, 8)
+}
+
+func TestUpdateBookClearRating(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	first, second := createTestEditions(t)
+
+	b, err := ts.GetBook(first.Id)
+	is.NoErr(err)
+	b.Rating = 0
+	_, err = ts.UpdateBook(b.Id, b)
+	is.NoErr(err)
+
+	// the rating is cleared for the work and all its editions
+	for _, id := range []int64{first.Id, second.Id} {
+		got, err := ts.GetBook(id)
+		is.NoErr(err)
+		is.Equal(got.Rating, 0)
+		is.Equal(got.Work.Rating, 0)
+	}
+}
+
+func TestMergeBooksWork(t *testing.T) {
+	defer resetDB()
+
+	is := is.New(t)
+	first, _ := createTestEditions(t)
+
+	b, err := ts.GetBook(testBook1.Id)
+	is.NoErr(err)
+	is.True(b.Work == nil)
+
+	// the merged book takes the work of the other book
+	got, err := ts.MergeBooks(testBook1.Id, first.Id, b)
+	is.NoErr(err)
+	is.True(got.Work != nil)
+	is.Equal(got.Work.Id, first.Work.Id)
+	is.Equal(got.Work.EditionCount, 2)
+}
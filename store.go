@@ -1,6 +1,8 @@
 package dusk
 
 import (
+	"time"
+
 	"github.com/kencx/dusk/filters"
 	"github.com/kencx/dusk/page"
 )
@@ -11,6 +13,35 @@ type Store interface {
 	CreateBook(b *Book) (*Book, error)
 	UpdateBook(id int64, b *Book) (*Book, error)
 	DeleteBook(id int64) error
+	MergeBooks(id, otherId int64, b *Book) (*Book, error)
+	GetWishlist() ([]Book, error)
+
+	GetReadingSessions(bookId int64) ([]ReadingSession, error)
+	GetReadingSession(id int64) (*ReadingSession, error)
+	CreateReadingSession(bookId int64, rs *ReadingSession) (*ReadingSession, error)
+	UpdateReadingSession(id int64, rs *ReadingSession) (*ReadingSession, error)
+	DeleteReadingSession(id int64) error
+	AddReadingProgress(sessionId int64, p *ReadingProgress) (*ReadingProgress, error)
+
+	GetCopies(bookId int64) ([]Copy, error)
+	GetCopy(id int64) (*Copy, error)
+	CreateCopy(bookId int64, c *Copy) (*Copy, error)
+	UpdateCopy(id int64, c *Copy) (*Copy, error)
+	DeleteCopy(id int64) error
+	GetActiveLoans() ([]Loan, error)
+	GetLoan(id int64) (*Loan, error)
+	CreateLoan(copyId int64, l *Loan) (*Loan, error)
+	UpdateLoan(id int64, l *Loan) (*Loan, error)
+	ReturnLoan(id int64, date time.Time) (*Loan, error)
+	DeleteLoan(id int64) error
+
+	GetHighlights(bookId int64) ([]Highlight, error)
+	GetHighlight(id int64) (*Highlight, error)
+	CreateHighlight(bookId int64, h *Highlight) (*Highlight, error)
+	AddHighlights(bookId int64, highlights []Highlight) (int, error)
+	UpdateHighlight(id int64, h *Highlight) (*Highlight, error)
+	DeleteHighlight(id int64) error
+	SearchHighlights(filters *filters.Search) (*page.Page[Highlight], error)
 
 	GetAuthor(id int64) (*Author, error)
 	GetAuthorsFromBook(id int64) ([]Author, error)
@@ -19,6 +50,7 @@ type Store interface {
 	CreateAuthor(a *Author) (*Author, error)
 	UpdateAuthor(id int64, a *Author) (*Author, error)
 	DeleteAuthor(id int64) error
+	MergeAuthors(id, otherId int64) (*Author, error)
 
 	GetTag(id int64) (*Tag, error)
 	GetTagsFromBook(id int64) ([]Tag, error)
@@ -27,4 +59,56 @@ type Store interface {
 	CreateTag(t *Tag) (*Tag, error)
 	UpdateTag(id int64, t *Tag) (*Tag, error)
 	DeleteTag(id int64) error
+	MergeTags(id int64, ids []int64) (*Tag, error)
+	DeleteUnusedTags() (int64, error)
+
+	GetCollection(id int64) (*Collection, error)
+	GetAllCollections() ([]Collection, error)
+	GetCollectionsFromBook(id int64) ([]Collection, error)
+	CreateCollection(c *Collection) (*Collection, error)
+	UpdateCollection(id int64, c *Collection) (*Collection, error)
+	DeleteCollection(id int64) error
+	AddBookToCollection(id, bookId int64) (*Collection, error)
+	RemoveBookFromCollection(id, bookId int64) error
+	ReorderCollection(id int64, bookIds []int64) (*Collection, error)
+
+	GetSmartCollection(id int64) (*SmartCollection, error)
+	GetAllSmartCollections() ([]SmartCollection, error)
+	GetAllBooksFromSmartCollection(id int64, filters *filters.Book) (*page.Page[Book], error)
+	CreateSmartCollection(c *SmartCollection) (*SmartCollection, error)
+	UpdateSmartCollection(id int64, c *SmartCollection) (*SmartCollection, error)
+	DeleteSmartCollection(id int64) error
+
+	GetWork(id int64) (*Work, error)
+	GetAllWorks() ([]Work, error)
+	UpdateWork(id int64, w *Work) (*Work, error)
+	DeleteWork(id int64) error
+	AddBookToWork(id, bookId int64) (*Work, error)
+	RemoveBookFromWork(id, bookId int64) error
+
+	GetSeries(id int64) (*Series, error)
+	GetAllSeries(filters *filters.Search) (*page.Page[Series], error)
+	GetAllBooksFromSeries(id int64, filters *filters.Book) (*page.Page[Book], error)
+	CreateSeries(s *Series) (*Series, error)
+	UpdateSeries(id int64, s *Series) (*Series, error)
+	DeleteSeries(id int64) error
+
+	GetStats(year int) (*Stats, error)
+
+	GetGoal(year int) (*Goal, error)
+	GetAllGoals() ([]Goal, error)
+	SetGoal(g *Goal) (*Goal, error)
+	DeleteGoal(year int) error
+
+	GetImportedBook(source, sourceId string) (*Book, error)
+	SetImportedBook(source, sourceId string, bookId int64) error
+	GetDuplicateCandidates(isbns, authors []string) (Books, error)
+
+	GetJob(id int64) (*Job, error)
+	GetAllJobs() ([]Job, error)
+	CreateJob(j *Job) (*Job, error)
+	UpdateJob(id int64, j *Job) (*Job, error)
+	UpdateJobItem(id int64, item *JobItem) error
+	RetryJob(id int64) (*Job, error)
+	DeleteJob(id int64) error
 }
@@ -2,27 +2,122 @@ package dusk
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/kencx/dusk/validator"
 	"github.com/kennygrant/sanitize"
 )
 
+// TagSeparator separates the levels of hierarchical tags, e.g.
+// fiction.scifi.space-opera
+const TagSeparator = "."
+
 type Tag struct {
 	Id   int64  `json:"id"`
 	Name string `json:"name"`
+
+	// number of books linked directly to the tag
+	BookCount int `json:"book_count,omitempty" db:"bookCount"`
 }
 
 func (a Tag) Slugify() string {
-	return sanitize.Path(fmt.Sprintf("%s-%d", a.Name, a.Id))
+	name := strings.ReplaceAll(a.Name, TagSeparator, "-")
+	return sanitize.Path(fmt.Sprintf("%s-%d", name, a.Id))
 }
 
 func (t Tag) Valid() validator.ErrMap {
 	err := validator.New()
 	err.Check(t.Name != "", "name", "value is missing")
+	err.Check(!slices.Contains(strings.Split(t.Name, TagSeparator), ""), "name", "must not have empty levels")
 	return err
 }
 
+// Parent returns the name of the parent tag or an empty string if the tag is
+// at the top level.
 func (t Tag) Parent() string {
-	return strings.SplitN(t.Name, ".", 2)[0]
+	i := strings.LastIndex(t.Name, TagSeparator)
+	if i == -1 {
+		return ""
+	}
+	return t.Name[:i]
+}
+
+// Ancestors returns the names of all parent tags, top level first.
+func (t Tag) Ancestors() []string {
+	var result []string
+	for i, r := range t.Name {
+		if string(r) == TagSeparator {
+			result = append(result, t.Name[:i])
+		}
+	}
+	return result
+}
+
+// Leaf returns the last level of the tag's name.
+func (t Tag) Leaf() string {
+	return t.Name[strings.LastIndex(t.Name, TagSeparator)+1:]
+}
+
+// IsDescendantOf reports whether the tag is nested under the given tag name.
+func (t Tag) IsDescendantOf(name string) bool {
+	return strings.HasPrefix(t.Name, name+TagSeparator)
+}
+
+// NormaliseTagName trims the whitespace around each level of the tag name and
+// lowercases it, as tags are matched regardless of case.
+func NormaliseTagName(name string) string {
+	levels := strings.Split(strings.TrimSpace(name), TagSeparator)
+	for i, l := range levels {
+		levels[i] = strings.ToLower(strings.TrimSpace(l))
+	}
+	return strings.Join(levels, TagSeparator)
+}
+
+type TagNode struct {
+	Tag
+	Children []*TagNode
+}
+
+// TagTree nests the given tags under their parents, sorted by name. Parents
+// that are missing from tags are added without an id.
+func TagTree(tags []Tag) []*TagNode {
+	nodes := make(map[string]*TagNode)
+	var roots []*TagNode
+
+	var add func(t Tag) *TagNode
+	add = func(t Tag) *TagNode {
+		if n, ok := nodes[t.Name]; ok {
+			if n.Id == 0 {
+				n.Id = t.Id
+			}
+			return n
+		}
+
+		n := &TagNode{Tag: t}
+		nodes[t.Name] = n
+		if parent := t.Parent(); parent != "" {
+			p := add(Tag{Name: parent})
+			p.Children = append(p.Children, n)
+		} else {
+			roots = append(roots, n)
+		}
+		return n
+	}
+
+	for _, t := range tags {
+		add(t)
+	}
+
+	sortNodes(roots)
+	return roots
+}
+
+func sortNodes(nodes []*TagNode) {
+	slices.SortFunc(nodes, func(a, b *TagNode) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, n := range nodes {
+		sortNodes(n.Children)
+	}
 }
@@ -1,6 +1,7 @@
 package dusk
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -21,6 +22,12 @@ func TestValidateTag(t *testing.T) {
 			Name: "",
 		},
 		err: map[string]string{"name": "value is missing"},
+	}, {
+		name: "empty level",
+		tag: &Tag{
+			Name: "fiction..scifi",
+		},
+		err: map[string]string{"name": "must not have empty levels"},
 	}}
 
 	for _, tt := range tests {
@@ -54,3 +61,53 @@ func TestValidateTag(t *testing.T) {
 		})
 	}
 }
+
+func TestTagHierarchy(t *testing.T) {
+	tag := Tag{Name: "fiction.scifi.space-opera"}
+
+	if got := tag.Parent(); got != "fiction.scifi" {
+		t.Errorf("got parent %q, want %q", got, "fiction.scifi")
+	}
+	if got := tag.Ancestors(); !reflect.DeepEqual(got, []string{"fiction", "fiction.scifi"}) {
+		t.Errorf("got ancestors %v", got)
+	}
+	if got := tag.Leaf(); got != "space-opera" {
+		t.Errorf("got leaf %q, want %q", got, "space-opera")
+	}
+	if !tag.IsDescendantOf("fiction") || tag.IsDescendantOf("fict") {
+		t.Errorf("wrong descendant of fiction")
+	}
+
+	root := Tag{Name: "fiction"}
+	if root.Parent() != "" || root.Ancestors() != nil || root.Leaf() != "fiction" {
+		t.Errorf("got parent %q, ancestors %v, leaf %q for top level tag", root.Parent(), root.Ancestors(), root.Leaf())
+	}
+
+	if got := NormaliseTagName(" Fiction . SciFi "); got != "fiction.scifi" {
+		t.Errorf("got normalised name %q, want %q", got, "fiction.scifi")
+	}
+}
+
+func TestTagTree(t *testing.T) {
+	tree := TagTree([]Tag{
+		{Id: 3, Name: "fiction.scifi.space-opera"},
+		{Id: 1, Name: "fiction"},
+		{Id: 4, Name: "fiction-adjacent"},
+		{Id: 2, Name: "fiction.fantasy"},
+	})
+
+	if len(tree) != 2 || tree[0].Name != "fiction" || tree[1].Name != "fiction-adjacent" {
+		t.Fatalf("got roots %v, want fiction and fiction-adjacent", tree)
+	}
+
+	fiction := tree[0]
+	if len(fiction.Children) != 2 || fiction.Children[0].Id != 2 || fiction.Children[1].Name != "fiction.scifi" {
+		t.Fatalf("got children %v of fiction", fiction.Children)
+	}
+
+	// missing parents are added without an id
+	scifi := fiction.Children[1]
+	if scifi.Id != 0 || len(scifi.Children) != 1 || scifi.Children[0].Id != 3 {
+		t.Errorf("got scifi %v", scifi)
+	}
+}
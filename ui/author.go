@@ -4,16 +4,19 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
 
 	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/filters"
 	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/http/response"
 	"github.com/kencx/dusk/page"
 	"github.com/kencx/dusk/ui/views"
 	"github.com/kencx/dusk/validator"
 )
 
 func (s *Handler) authorList(rw http.ResponseWriter, r *http.Request) {
-	filters := initSearchFilters(r)
+	filters := initSearchFilters(r, filters.AuthorSafeList())
 	if errMap := validator.Validate(filters.Base); errMap != nil {
 		slog.Error("[ui] failed to validate query params", slog.Any("err", errMap.Error()))
 		views.AuthorSearchResults(page.Page[dusk.Author]{}, errors.New("validate error")).Render(r.Context(), rw)
@@ -37,7 +40,7 @@ func (s *Handler) authorSearch(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	filters := initSearchFilters(r)
+	filters := initSearchFilters(r, filters.AuthorSafeList())
 	if errMap := validator.Validate(filters.Base); errMap != nil {
 		slog.Error("[ui] failed to validate query params", slog.Any("err", errMap.Error()))
 		views.AuthorSearchResults(page.Page[dusk.Author]{}, errors.New("validate error")).Render(r.Context(), rw)
@@ -74,15 +77,100 @@ func (s *Handler) authorPage(rw http.ResponseWriter, r *http.Request) {
 	author, err := s.db.GetAuthor(id)
 	if err != nil {
 		slog.Error("[ui] failed to get author", slog.Int64("id", id), slog.Any("err", err))
-		views.NewAuthor(s.base, dusk.Author{}, page.Page[dusk.Book]{}, filters.Base, err).Render(rw, r)
+		views.NewAuthor(s.base, dusk.Author{}, nil, page.Page[dusk.Book]{}, filters.Base, err).Render(rw, r)
 		return
 	}
 
 	books, err := s.db.GetAllBooksFromAuthor(author.Id, filters)
 	if err != nil {
 		slog.Error("[ui] failed to get books from author", slog.Int64("id", id), slog.Any("err", err))
-		views.NewAuthor(s.base, dusk.Author{}, page.Page[dusk.Book]{}, filters.Base, err).Render(rw, r)
+		views.NewAuthor(s.base, dusk.Author{}, nil, page.Page[dusk.Book]{}, filters.Base, err).Render(rw, r)
 		return
 	}
-	views.NewAuthor(s.base, *author, *books, filters.Base, nil).Render(rw, r)
+
+	var others []dusk.Author
+	all, err := s.db.GetAllAuthors(nil)
+	if err != nil {
+		slog.Warn("[ui] failed to get all authors", slog.Any("err", err))
+	} else {
+		for _, a := range all.Items {
+			if a.Id != author.Id {
+				others = append(others, a)
+			}
+		}
+	}
+	views.NewAuthor(s.base, *author, others, *books, filters.Base, nil).Render(rw, r)
+}
+
+func (s *Handler) mergeAuthor(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	otherId, err := strconv.ParseInt(r.FormValue("other"), 10, 64)
+	if err != nil || otherId == id {
+		SendToastMessage(rw, r, "Invalid author to merge")
+		return
+	}
+
+	author, err := s.db.MergeAuthors(id, otherId)
+	if err != nil {
+		slog.Error("[ui] failed to merge authors", slog.Int64("id", id), slog.Int64("other", otherId), slog.Any("err", err))
+		if errors.Is(err, dusk.ErrUniqueConstraint) {
+			SendToastMessage(rw, r, "Author names conflict with another author")
+			return
+		}
+		SendToastMessage(rw, r, "Failed to merge authors")
+		return
+	}
+
+	slog.Info("[ui] Merged authors", slog.Int64("id", id), slog.Int64("other", otherId))
+	response.HxRedirect(rw, r, "/a/"+author.Slugify())
+}
+
+// fetchAuthor fills in the missing profile of an author from the fetchers
+func (s *Handler) fetchAuthor(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	author, err := s.db.GetAuthor(id)
+	if err != nil {
+		slog.Error("[ui] failed to get author", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Author not found")
+		return
+	}
+
+	m, err := s.f.FetchAuthor(author)
+	if err != nil {
+		slog.Error("[ui] failed to fetch author", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "No profile found for "+author.Name)
+		return
+	}
+	m.FillAuthor(author)
+
+	var newPhoto bool
+	if !author.Photo.Valid && m.PhotoUrl != "" {
+		if err := s.fs.UploadAuthorPhotoFromUrl(m.PhotoUrl, author); err != nil {
+			slog.Warn("[ui] failed to download author photo", slog.Int64("id", id), slog.Any("err", err))
+		} else {
+			newPhoto = true
+		}
+	}
+
+	if _, err := s.db.UpdateAuthor(id, author); err != nil {
+		slog.Error("[ui] failed to update author", slog.Int64("id", id), slog.Any("err", err))
+		if newPhoto {
+			if err := s.fs.DeleteAuthorPhoto(author); err != nil {
+				slog.Warn("[ui] failed to delete author photo", slog.Int64("id", id), slog.Any("err", err))
+			}
+		}
+		SendToastMessage(rw, r, "Failed to update author")
+		return
+	}
+
+	slog.Info("[ui] Fetched author profile", slog.Int64("id", id))
+	response.HxRedirect(rw, r, "/a/"+author.Slugify())
 }
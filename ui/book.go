@@ -2,18 +2,22 @@ package ui
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/araddon/dateparse"
 	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/dedup"
 	"github.com/kencx/dusk/http/request"
 	"github.com/kencx/dusk/http/response"
 	"github.com/kencx/dusk/null"
 	"github.com/kencx/dusk/page"
+	"github.com/kencx/dusk/query"
 	"github.com/kencx/dusk/ui/partials"
 	"github.com/kencx/dusk/ui/views"
 	"github.com/kencx/dusk/validator"
@@ -29,9 +33,15 @@ func (s *Handler) bookSearch(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	filters := initBookFilters(r)
-	if errMap := validator.Validate(filters.Base); errMap != nil {
+	if errMap := validator.Validate(filters); errMap != nil {
 		slog.Error("[ui] failed to validate query params", slog.Any("err", errMap.Error()))
-		partials.BookSearchResults(page.Page[dusk.Book]{}, filters.Base, errors.New("validate error")).Render(r.Context(), rw)
+
+		// show the user why their search query is invalid
+		err := errors.New("validate error")
+		if _, qerr := filters.Query(); qerr != nil {
+			err = qerr
+		}
+		partials.BookSearchResults(page.Page[dusk.Book]{}, filters.Base, err).Render(r.Context(), rw)
 		return
 	}
 
@@ -224,6 +234,119 @@ func (s *Handler) deleteBook(rw http.ResponseWriter, r *http.Request) {
 	response.HxRedirect(rw, r, "/")
 }
 
+// Render the possible duplicates of a book or, given another book, the fields
+// to keep when merging it into this book
+func (s *Handler) bookMergePage(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	book, err := s.db.GetBook(id)
+	if err != nil {
+		slog.Error("[ui] failed to find book", slog.Int64("id", id), slog.Any("err", err))
+		views.NewBookMerge(s.base, nil, nil, nil, err).Render(rw, r)
+		return
+	}
+
+	if !r.URL.Query().Has("other") {
+		candidates, err := dedup.Find(s.db, book)
+		if err != nil {
+			slog.Error("[ui] failed to find duplicates", slog.Int64("id", id), slog.Any("err", err))
+		}
+		views.NewBookMerge(s.base, book, nil, candidates, err).Render(rw, r)
+		return
+	}
+
+	otherId := int64(request.QueryInt(r.URL.Query(), "other", 0))
+	if otherId == id {
+		views.NewBookMerge(s.base, book, nil, nil, errors.New("cannot merge a book with itself")).Render(rw, r)
+		return
+	}
+
+	other, err := s.db.GetBook(otherId)
+	if err != nil {
+		slog.Error("[ui] failed to find book", slog.Int64("id", otherId), slog.Any("err", err))
+		views.NewBookMerge(s.base, book, nil, nil, fmt.Errorf("book %d not found", otherId)).Render(rw, r)
+		return
+	}
+	views.NewBookMerge(s.base, book, other, nil, nil).Render(rw, r)
+}
+
+// Merge another book into this book. The other book's files are moved first
+// and moved back if the database cannot be updated.
+func (s *Handler) mergeBook(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		slog.Error("[ui] failed to parse form", slog.Int64("id", id), slog.Any("err", err))
+		return
+	}
+
+	otherId, err := strconv.ParseInt(r.FormValue("other"), 10, 64)
+	if err != nil || otherId == id {
+		views.NewBookMerge(s.base, nil, nil, nil, errors.New("invalid book to merge")).Render(rw, r)
+		return
+	}
+
+	book, err := s.db.GetBook(id)
+	if err != nil {
+		slog.Error("[ui] failed to get book", slog.Int64("id", id), slog.Any("err", err))
+		views.NewBookMerge(s.base, nil, nil, nil, err).Render(rw, r)
+		return
+	}
+	other, err := s.db.GetBook(otherId)
+	if err != nil {
+		slog.Error("[ui] failed to get book", slog.Int64("id", otherId), slog.Any("err", err))
+		views.NewBookMerge(s.base, book, nil, nil, err).Render(rw, r)
+		return
+	}
+
+	var fields []string
+	for key := range r.Form {
+		if field, ok := strings.CutPrefix(key, "field-"); ok && r.FormValue(key) == "take" {
+			fields = append(fields, field)
+		}
+	}
+
+	merged := *book
+	merged.Merge(other, fields)
+	if errMap := validator.Validate(merged); errMap != nil {
+		slog.Error("[ui] failed to validate book", slog.Int64("id", id), slog.String("err", errMap.Error()))
+		views.NewBookMerge(s.base, book, other, nil, errMap).Render(rw, r)
+		return
+	}
+
+	// files are moved into the directory of the book before it is merged
+	move, err := s.fs.MoveBookFiles(other, book, slices.Contains(fields, "cover"))
+	if err != nil {
+		slog.Error("[ui] failed to move book files", slog.Int64("id", otherId), slog.Any("err", err))
+		views.NewBookMerge(s.base, book, other, nil, err).Render(rw, r)
+		return
+	}
+	merged.Formats, merged.Cover = book.Formats, book.Cover
+
+	result, err := s.db.MergeBooks(id, otherId, &merged)
+	if err != nil {
+		slog.Error("[ui] failed to merge books", slog.Int64("id", id), slog.Int64("other", otherId), slog.Any("err", err))
+		if rerr := move.Rollback(); rerr != nil {
+			slog.Error("[ui] failed to restore book files", slog.Int64("id", otherId), slog.Any("err", rerr))
+		}
+		views.NewBookMerge(s.base, book, other, nil, err).Render(rw, r)
+		return
+	}
+
+	if err := move.Commit(); err != nil {
+		slog.Warn("[ui] failed to clean up merged book files", slog.Int64("id", otherId), slog.Any("err", err))
+	}
+
+	slog.Info("[ui] Merged books", slog.Int64("id", id), slog.Int64("other", otherId))
+	response.HxRedirect(rw, r, "/b/"+result.Slugify())
+}
+
 func parseBookForm(r *http.Request, b *dusk.Book) *dusk.Book {
 	if request.HasValue(r.Form, "title") {
 		b.Title = r.FormValue("title")
@@ -263,6 +386,14 @@ func parseBookForm(r *http.Request, b *dusk.Book) *dusk.Book {
 		b.Series = null.StringFrom(r.FormValue("series"))
 	}
 
+	if request.HasOptionalValue(r.Form, "seriesPosition") {
+		if position, err := strconv.ParseFloat(r.FormValue("seriesPosition"), 64); err == nil {
+			b.SeriesPosition = null.NewFloat(position, true)
+		} else {
+			b.SeriesPosition = null.NewFloat(0, false)
+		}
+	}
+
 	if request.HasOptionalValue(r.Form, "numOfPages") {
 		pages, _ := strconv.Atoi(r.FormValue("numOfPages"))
 		b.NumOfPages = pages
@@ -297,6 +428,12 @@ func parseBookForm(r *http.Request, b *dusk.Book) *dusk.Book {
 		b.Status = status
 	}
 
+	if request.HasValue(r.Form, "ownership") {
+		if i := query.OwnershipIndex(strings.ToLower(r.FormValue("ownership"))); i != -1 {
+			b.Ownership = dusk.Ownership(i)
+		}
+	}
+
 	if request.HasOptionalValue(r.Form, "dateAdded") {
 		dp, _ := dateparse.ParseAny(r.FormValue("dateAdded"))
 		b.DateAdded = null.TimeFrom(dp)
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/kencx/dusk/integration/calibre"
+	"github.com/kencx/dusk/ui/views"
+)
+
+func (s *Handler) calibrePage(rw http.ResponseWriter, r *http.Request) {
+	views.NewImportIndex(s.base, "calibre", nil).Render(rw, r)
+}
+
+func (s *Handler) calibre(rw http.ResponseWriter, r *http.Request) {
+	dir := strings.TrimSpace(r.FormValue("calibre"))
+	if dir == "" {
+		views.CalibreError(errors.New("path to calibre library is required")).Render(r.Context(), rw)
+		return
+	}
+
+	books, err := calibre.ReadLibrary(dir)
+	if err != nil {
+		slog.Error("[calibre] failed to read library", slog.Any("err", err))
+		views.CalibreError(err).Render(r.Context(), rw)
+		return
+	}
+
+	items, err := calibre.JobItems(books)
+	if err != nil {
+		slog.Error("[calibre] failed to create job", slog.Any("err", err))
+		views.CalibreError(err).Render(r.Context(), rw)
+		return
+	}
+
+	job, err := s.runner.Submit(calibre.JobKind, items)
+	if err != nil {
+		slog.Error("[calibre] failed to create job", slog.Any("err", err))
+		views.CalibreError(err).Render(r.Context(), rw)
+		return
+	}
+
+	views.JobProgress(job).Render(r.Context(), rw)
+}
@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/http/response"
+	"github.com/kencx/dusk/null"
+	"github.com/kencx/dusk/ui/views"
+	"github.com/kencx/dusk/validator"
+)
+
+func (s *Handler) collectionList(rw http.ResponseWriter, r *http.Request) {
+	collections, err := s.db.GetAllCollections()
+	if err != nil {
+		slog.Error("[ui] failed to get all collections", slog.Any("err", err))
+		views.NewCollectionList(s.base, nil, err).Render(rw, r)
+		return
+	}
+	views.NewCollectionList(s.base, collections, nil).Render(rw, r)
+}
+
+func (s *Handler) createCollection(rw http.ResponseWriter, r *http.Request) {
+	c := collectionFromForm(r)
+	if errMap := validator.Validate(c); errMap != nil {
+		SendToastMessage(rw, r, "Collection name is missing")
+		return
+	}
+
+	result, err := s.db.CreateCollection(&c)
+	if err != nil {
+		slog.Error("[ui] failed to create collection", slog.Any("err", err))
+		if errors.Is(err, dusk.ErrUniqueConstraint) {
+			SendToastMessage(rw, r, "Collection already exists")
+			return
+		}
+		SendToastMessage(rw, r, "Failed to create collection")
+		return
+	}
+
+	slog.Info("[ui] Created collection", slog.Int64("id", result.Id), slog.String("name", result.Name))
+	response.HxRedirect(rw, r, "/c/"+result.Slugify())
+}
+
+func (s *Handler) collectionPage(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	c, err := s.db.GetCollection(id)
+	if err != nil {
+		slog.Error("[ui] failed to get collection", slog.Int64("id", id), slog.Any("err", err))
+		views.NewCollection(s.base, nil, err).Render(rw, r)
+		return
+	}
+	views.NewCollection(s.base, c, nil).Render(rw, r)
+}
+
+func (s *Handler) updateCollection(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	c := collectionFromForm(r)
+	if errMap := validator.Validate(c); errMap != nil {
+		SendToastMessage(rw, r, "Collection name is missing")
+		return
+	}
+
+	result, err := s.db.UpdateCollection(id, &c)
+	if err != nil {
+		slog.Error("[ui] failed to update collection", slog.Int64("id", id), slog.Any("err", err))
+		if errors.Is(err, dusk.ErrUniqueConstraint) {
+			SendToastMessage(rw, r, "Collection already exists")
+			return
+		}
+		SendToastMessage(rw, r, "Failed to update collection")
+		return
+	}
+
+	slog.Info("[ui] Updated collection", slog.Int64("id", id))
+	response.HxRedirect(rw, r, "/c/"+result.Slugify())
+}
+
+func (s *Handler) deleteCollection(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	if err := s.db.DeleteCollection(id); err != nil {
+		slog.Error("[ui] failed to delete collection", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Failed to delete collection")
+		return
+	}
+
+	slog.Info("[ui] Deleted collection", slog.Int64("id", id))
+	response.HxRedirect(rw, r, "/collections")
+}
+
+// reorderCollection saves the order of the books after they are dragged
+func (s *Handler) reorderCollection(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		SendToastMessage(rw, r, "Invalid book order")
+		return
+	}
+
+	var bookIds []int64
+	for _, v := range r.Form["books"] {
+		bookId, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			SendToastMessage(rw, r, "Invalid book order")
+			return
+		}
+		bookIds = append(bookIds, bookId)
+	}
+
+	if _, err := s.db.ReorderCollection(id, bookIds); err != nil {
+		slog.Error("[ui] failed to reorder collection", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Failed to save book order")
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Handler) removeCollectionBook(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	bookId, err := strconv.ParseInt(chi.URLParam(r, "book"), 10, 64)
+	if err != nil {
+		SendToastMessage(rw, r, "Invalid book")
+		return
+	}
+
+	if err := s.db.RemoveBookFromCollection(id, bookId); err != nil {
+		slog.Error("[ui] failed to remove book from collection", slog.Int64("id", id), slog.Int64("book", bookId), slog.Any("err", err))
+		SendToastMessage(rw, r, "Failed to remove book")
+		return
+	}
+
+	// remove the book from the list
+	rw.WriteHeader(http.StatusOK)
+}
+
+// Render the collections of a book with a form to add it to other collections
+func (s *Handler) bookCollections(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	book, err := s.db.GetBook(id)
+	if err != nil {
+		slog.Error("[ui] failed to get book", slog.Int64("id", id), slog.Any("err", err))
+		return
+	}
+	s.renderBookCollections(rw, r, book)
+}
+
+func (s *Handler) addBookToCollection(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	book, err := s.db.GetBook(id)
+	if err != nil {
+		slog.Error("[ui] failed to get book", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Book not found")
+		return
+	}
+
+	collectionId, err := strconv.ParseInt(r.FormValue("collection"), 10, 64)
+	if err != nil {
+		SendToastMessage(rw, r, "Invalid collection")
+		return
+	}
+
+	if _, err := s.db.AddBookToCollection(collectionId, id); err != nil && !errors.Is(err, dusk.ErrUniqueConstraint) {
+		slog.Error("[ui] failed to add book to collection", slog.Int64("id", id), slog.Int64("collection", collectionId), slog.Any("err", err))
+		SendToastMessage(rw, r, "Failed to add book to collection")
+		return
+	}
+
+	slog.Info("[ui] Added book to collection", slog.Int64("id", id), slog.Int64("collection", collectionId))
+	s.renderBookCollections(rw, r, book)
+}
+
+func (s *Handler) renderBookCollections(rw http.ResponseWriter, r *http.Request, book *dusk.Book) {
+	in, err := s.db.GetCollectionsFromBook(book.Id)
+	if err != nil {
+		slog.Error("[ui] failed to get collections of book", slog.Int64("id", book.Id), slog.Any("err", err))
+		return
+	}
+
+	all, err := s.db.GetAllCollections()
+	if err != nil {
+		slog.Error("[ui] failed to get all collections", slog.Any("err", err))
+		return
+	}
+
+	var others []dusk.Collection
+	for _, c := range all {
+		if !slices.ContainsFunc(in, func(o dusk.Collection) bool { return o.Id == c.Id }) {
+			others = append(others, c)
+		}
+	}
+	views.BookCollections(book, in, others).Render(r.Context(), rw)
+}
+
+func collectionFromForm(r *http.Request) dusk.Collection {
+	c := dusk.Collection{Name: strings.TrimSpace(r.FormValue("name"))}
+	if desc := strings.TrimSpace(r.FormValue("description")); desc != "" {
+		c.Description = null.StringFrom(desc)
+	}
+	return c
+}
@@ -0,0 +1,240 @@
+package ui
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/araddon/dateparse"
+	"github.com/go-chi/chi/v5"
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/null"
+	"github.com/kencx/dusk/ui/views"
+	"github.com/kencx/dusk/validator"
+)
+
+func (s *Handler) loanList(rw http.ResponseWriter, r *http.Request) {
+	s.renderLoanList(rw, r, false)
+}
+
+func (s *Handler) overdueLoanList(rw http.ResponseWriter, r *http.Request) {
+	s.renderLoanList(rw, r, true)
+}
+
+func (s *Handler) renderLoanList(rw http.ResponseWriter, r *http.Request, overdue bool) {
+	loans, err := s.db.GetActiveLoans()
+	if err != nil {
+		slog.Error("[ui] failed to get active loans", slog.Any("err", err))
+		views.NewLoanList(s.base, nil, overdue, err).Render(rw, r)
+		return
+	}
+
+	if overdue {
+		var result []dusk.Loan
+		now := time.Now()
+		for _, l := range loans {
+			if l.Overdue(now) {
+				result = append(result, l)
+			}
+		}
+		loans = result
+	}
+	views.NewLoanList(s.base, loans, overdue, nil).Render(rw, r)
+}
+
+// returnLoan records the return of a copy from the loan list
+func (s *Handler) returnLoan(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	if _, err := s.db.ReturnLoan(id, time.Now()); err != nil && !errors.Is(err, dusk.ErrNoChange) {
+		slog.Error("[ui] failed to return loan", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Failed to return copy")
+		return
+	}
+
+	slog.Info("[ui] Returned loan", slog.Int64("id", id))
+	// remove the loan from the list
+	rw.WriteHeader(http.StatusOK)
+}
+
+// Render the physical copies of a book with their loans
+func (s *Handler) bookCopies(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+	s.renderBookCopies(rw, r, id)
+}
+
+func (s *Handler) addCopy(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		SendToastMessage(rw, r, "Invalid copy")
+		return
+	}
+
+	var c dusk.Copy
+	if request.HasValue(r.Form, "condition") {
+		c.Condition = null.StringFrom(r.FormValue("condition"))
+	}
+	if request.HasValue(r.Form, "location") {
+		c.Location = null.StringFrom(strings.TrimSpace(r.FormValue("location")))
+	}
+	if request.HasValue(r.Form, "dateAcquired") {
+		da, err := dateparse.ParseAny(r.FormValue("dateAcquired"))
+		if err != nil {
+			SendToastMessage(rw, r, "Invalid date acquired")
+			return
+		}
+		c.DateAcquired = null.TimeFrom(da)
+	}
+	if request.HasValue(r.Form, "price") {
+		price, err := strconv.ParseFloat(r.FormValue("price"), 64)
+		if err != nil {
+			SendToastMessage(rw, r, "Invalid price")
+			return
+		}
+		c.Price = null.FloatFrom(price)
+	}
+	if request.HasValue(r.Form, "notes") {
+		c.Notes = null.StringFrom(r.FormValue("notes"))
+	}
+
+	if errMap := validator.Validate(c); errMap != nil {
+		if _, ok := errMap["condition"]; ok {
+			SendToastMessage(rw, r, "Invalid condition")
+			return
+		}
+		SendToastMessage(rw, r, "Price must not be negative")
+		return
+	}
+
+	if _, err := s.db.CreateCopy(id, &c); err != nil {
+		slog.Error("[ui] failed to add copy", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Failed to add copy")
+		return
+	}
+
+	slog.Info("[ui] Added copy", slog.Int64("id", id))
+	s.renderBookCopies(rw, r, id)
+}
+
+func (s *Handler) deleteCopy(rw http.ResponseWriter, r *http.Request) {
+	id, c := s.fetchBookCopy(rw, r)
+	if c == nil {
+		return
+	}
+
+	if err := s.db.DeleteCopy(c.Id); err != nil {
+		slog.Error("[ui] failed to delete copy", slog.Int64("id", c.Id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Failed to delete copy")
+		return
+	}
+
+	slog.Info("[ui] Deleted copy", slog.Int64("id", c.Id))
+	s.renderBookCopies(rw, r, id)
+}
+
+// lendCopy lends the copy from today, with an optional due date
+func (s *Handler) lendCopy(rw http.ResponseWriter, r *http.Request) {
+	id, c := s.fetchBookCopy(rw, r)
+	if c == nil {
+		return
+	}
+
+	l := dusk.Loan{
+		Borrower: strings.TrimSpace(r.FormValue("borrower")),
+		DateLent: null.TimeFrom(time.Now().UTC().Truncate(24 * time.Hour)),
+	}
+	if request.HasValue(r.Form, "dateDue") {
+		dd, err := dateparse.ParseAny(r.FormValue("dateDue"))
+		if err != nil {
+			SendToastMessage(rw, r, "Invalid due date")
+			return
+		}
+		l.DateDue = null.TimeFrom(dd)
+	}
+
+	if errMap := validator.Validate(l); errMap != nil {
+		if _, ok := errMap["borrower"]; ok {
+			SendToastMessage(rw, r, "Borrower is missing")
+			return
+		}
+		SendToastMessage(rw, r, "Due date must not be in the past")
+		return
+	}
+
+	if _, err := s.db.CreateLoan(c.Id, &l); err != nil {
+		slog.Error("[ui] failed to lend copy", slog.Int64("id", c.Id), slog.Any("err", err))
+		if errors.Is(err, dusk.ErrCopyLent) {
+			SendToastMessage(rw, r, "Copy is already lent out")
+			return
+		}
+		SendToastMessage(rw, r, "Failed to lend copy")
+		return
+	}
+
+	slog.Info("[ui] Lent copy", slog.Int64("id", c.Id))
+	s.renderBookCopies(rw, r, id)
+}
+
+func (s *Handler) returnCopy(rw http.ResponseWriter, r *http.Request) {
+	id, c := s.fetchBookCopy(rw, r)
+	if c == nil {
+		return
+	}
+
+	if l := c.Loan(); l != nil {
+		if _, err := s.db.ReturnLoan(l.Id, time.Now()); err != nil {
+			slog.Error("[ui] failed to return copy", slog.Int64("id", c.Id), slog.Any("err", err))
+			SendToastMessage(rw, r, "Failed to return copy")
+			return
+		}
+	}
+
+	slog.Info("[ui] Returned copy", slog.Int64("id", c.Id))
+	s.renderBookCopies(rw, r, id)
+}
+
+// fetchBookCopy returns the id of the book and its copy given in the url. The
+// copy is nil if it does not belong to the book.
+func (s *Handler) fetchBookCopy(rw http.ResponseWriter, r *http.Request) (int64, *dusk.Copy) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return id, nil
+	}
+
+	copyId, err := strconv.ParseInt(chi.URLParam(r, "copy"), 10, 64)
+	if err != nil {
+		SendToastMessage(rw, r, "Invalid copy")
+		return id, nil
+	}
+
+	c, err := s.db.GetCopy(copyId)
+	if err != nil || c.BookId != id {
+		slog.Error("[ui] failed to get copy", slog.Int64("id", copyId), slog.Any("err", err))
+		SendToastMessage(rw, r, "Copy not found")
+		return id, nil
+	}
+	return id, c
+}
+
+func (s *Handler) renderBookCopies(rw http.ResponseWriter, r *http.Request, id int64) {
+	book, err := s.db.GetBook(id)
+	if err != nil {
+		slog.Error("[ui] failed to get book", slog.Int64("id", id), slog.Any("err", err))
+		return
+	}
+	views.BookCopies(book, time.Now()).Render(r.Context(), rw)
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/kencx/dusk/http/response"
+	"github.com/kencx/dusk/integration/goodreads"
+	"github.com/kencx/dusk/ui/views"
+)
+
+func (s *Handler) exportGoodreads(rw http.ResponseWriter, r *http.Request) {
+	f := initBookFilters(r)
+	if errMap := f.Valid(); len(errMap) > 0 {
+		slog.Error("[ui] invalid export filters", slog.Any("err", errMap))
+		views.NewImportIndex(s.base, "goodreads", errMap).Render(rw, r)
+		return
+	}
+
+	// the details of copies are not part of a Goodreads export
+	copies := r.URL.Query().Get("copies") == "true"
+
+	var buf bytes.Buffer
+	if err := goodreads.Export(&buf, s.db, f, copies); err != nil {
+		slog.Error("[goodreads] failed to export csv", slog.Any("err", err))
+		views.NewImportIndex(s.base, "goodreads", err).Render(rw, r)
+		return
+	}
+
+	response.Custom(rw, r, http.StatusOK, map[string]string{
+		"Content-Type":        "text/csv; charset=utf-8",
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", goodreads.ExportFilename),
+	}, buf.Bytes())
+}
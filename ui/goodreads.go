@@ -28,31 +28,20 @@ func (s *Handler) goodreads(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// TODO run as background job OR show load bar and prevent user from navigating away
 	// TODO download book covers
-	var errMap = make(map[int]error)
-
-	// handling duplicates
-	// for _, book := range books {
-	// TODO when re-importing csvs, books without any isbn will NOT fail the isbn
-	// constraint requirement and be imported twice
-
-	// _, err := s.db.GetBookEqual(book)
-	// if err != nil {
-	// 	slog.Error("[goodreads] failed to get duplicates", slog.Any("err", err))
-	// }
-
-	// if len(dups) > 0 { }
-	// }
-
-	for i, book := range books {
-		b, err := s.db.CreateBook(book)
-		if err != nil {
-			slog.Error("[goodreads] failed to create book", slog.Any("err", err))
-			errMap[i] = err
-		}
-		book.Id = b.Id
+	items, err := goodreads.JobItems(books)
+	if err != nil {
+		slog.Error("[goodreads] failed to create job", slog.Any("err", err))
+		views.GoodreadsError(err).Render(r.Context(), rw)
+		return
+	}
+
+	job, err := s.runner.Submit(goodreads.JobKind, items)
+	if err != nil {
+		slog.Error("[goodreads] failed to create job", slog.Any("err", err))
+		views.GoodreadsError(err).Render(r.Context(), rw)
+		return
 	}
 
-	views.GoodreadsResults(books, errMap).Render(r.Context(), rw)
+	views.JobProgress(job).Render(r.Context(), rw)
 }
@@ -0,0 +1,197 @@
+package ui
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/integration/highlights"
+	"github.com/kencx/dusk/null"
+	"github.com/kencx/dusk/page"
+	"github.com/kencx/dusk/ui/views"
+	"github.com/kencx/dusk/validator"
+)
+
+// highlightList lists the highlights of all books, or only those matching ?q=
+func (s *Handler) highlightList(rw http.ResponseWriter, r *http.Request) {
+	filters := initHighlightFilters(r)
+	filters.Search = strings.TrimSpace(filters.Search)
+	if errMap := validator.Validate(filters.Base); errMap != nil {
+		slog.Error("[ui] failed to validate query params", slog.Any("err", errMap.Error()))
+		views.NewHighlightList(s.base, page.Page[dusk.Highlight]{}, *filters, errors.New("validate error")).Render(rw, r)
+		return
+	}
+
+	result, err := s.db.SearchHighlights(filters)
+	if err != nil {
+		slog.Error("[ui] failed to search highlights", slog.String("q", filters.Search), slog.Any("err", err))
+		views.NewHighlightList(s.base, page.Page[dusk.Highlight]{}, *filters, err).Render(rw, r)
+		return
+	}
+	views.NewHighlightList(s.base, *result, *filters, nil).Render(rw, r)
+}
+
+func (s *Handler) highlightSearch(rw http.ResponseWriter, r *http.Request) {
+	// If not htmx request, return the full page instead of partial.
+	// Required to support hx-push-urls
+	if request.IsHtmxRequest(r) {
+		s.highlightList(rw, r)
+		return
+	}
+
+	filters := initHighlightFilters(r)
+	filters.Search = strings.TrimSpace(filters.Search)
+	if errMap := validator.Validate(filters.Base); errMap != nil {
+		slog.Error("[ui] failed to validate query params", slog.Any("err", errMap.Error()))
+		views.HighlightResults(page.Page[dusk.Highlight]{}, filters.Search, errors.New("validate error")).Render(r.Context(), rw)
+		return
+	}
+
+	result, err := s.db.SearchHighlights(filters)
+	if err != nil {
+		slog.Error("[ui] failed to search highlights", slog.String("q", filters.Search), slog.Any("err", err))
+		views.HighlightResults(page.Page[dusk.Highlight]{}, filters.Search, err).Render(r.Context(), rw)
+		return
+	}
+	views.HighlightResults(*result, filters.Search, nil).Render(r.Context(), rw)
+}
+
+func (s *Handler) bookHighlights(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+	s.renderBookHighlights(rw, r, id)
+}
+
+func (s *Handler) addHighlight(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		SendToastMessage(rw, r, "Invalid highlight")
+		return
+	}
+
+	h := dusk.Highlight{
+		Text:     strings.TrimSpace(r.FormValue("text")),
+		Location: null.StringFrom(strings.TrimSpace(r.FormValue("location"))),
+		Chapter:  null.StringFrom(strings.TrimSpace(r.FormValue("chapter"))),
+		Note:     null.StringFrom(strings.TrimSpace(r.FormValue("note"))),
+	}
+	if request.HasValue(r.Form, "page") {
+		page, err := strconv.Atoi(r.FormValue("page"))
+		if err != nil {
+			SendToastMessage(rw, r, "Invalid page")
+			return
+		}
+		h.Page = page
+	}
+
+	if errMap := validator.Validate(h); errMap != nil {
+		if _, ok := errMap["text"]; ok {
+			SendToastMessage(rw, r, "Highlight or note is missing")
+			return
+		}
+		SendToastMessage(rw, r, "Page must not be negative")
+		return
+	}
+
+	if _, err := s.db.CreateHighlight(id, &h); err != nil {
+		slog.Error("[ui] failed to add highlight", slog.Int64("id", id), slog.Any("err", err))
+		if errors.Is(err, dusk.ErrUniqueConstraint) {
+			SendToastMessage(rw, r, "Highlight already exists")
+			return
+		}
+		SendToastMessage(rw, r, "Failed to add highlight")
+		return
+	}
+
+	slog.Info("[ui] Added highlight", slog.Int64("id", id))
+	s.renderBookHighlights(rw, r, id)
+}
+
+func (s *Handler) deleteHighlight(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	highlightId, err := strconv.ParseInt(chi.URLParam(r, "highlight"), 10, 64)
+	if err != nil {
+		SendToastMessage(rw, r, "Invalid highlight")
+		return
+	}
+
+	h, err := s.db.GetHighlight(highlightId)
+	if err != nil || h.BookId != id {
+		slog.Error("[ui] failed to get highlight", slog.Int64("id", highlightId), slog.Any("err", err))
+		SendToastMessage(rw, r, "Highlight not found")
+		return
+	}
+
+	if err := s.db.DeleteHighlight(h.Id); err != nil {
+		slog.Error("[ui] failed to delete highlight", slog.Int64("id", h.Id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Failed to delete highlight")
+		return
+	}
+
+	slog.Info("[ui] Deleted highlight", slog.Int64("id", h.Id))
+	s.renderBookHighlights(rw, r, id)
+}
+
+func (s *Handler) renderBookHighlights(rw http.ResponseWriter, r *http.Request, id int64) {
+	book, err := s.db.GetBook(id)
+	if err != nil {
+		slog.Error("[ui] failed to get book", slog.Int64("id", id), slog.Any("err", err))
+		return
+	}
+
+	result, err := s.db.GetHighlights(id)
+	if err != nil {
+		slog.Error("[ui] failed to get highlights", slog.Int64("id", id), slog.Any("err", err))
+		return
+	}
+	views.BookHighlights(book, result).Render(r.Context(), rw)
+}
+
+// importHighlights imports the highlights of a Kindle My Clippings.txt or
+// KOReader JSON export into their books with an import job
+func (s *Handler) importHighlights(rw http.ResponseWriter, r *http.Request) {
+	f, err := request.ReadFile(rw, r, "highlights", "text/")
+	if err != nil {
+		slog.Error("[highlights] failed to import file", slog.Any("err", err))
+		views.HighlightImportError(err).Render(r.Context(), rw)
+		return
+	}
+
+	clippings, err := highlights.Read(f)
+	if err != nil {
+		slog.Error("[highlights] failed to read file", slog.Any("err", err))
+		views.HighlightImportError(err).Render(r.Context(), rw)
+		return
+	}
+
+	items, err := highlights.JobItems(clippings)
+	if err != nil {
+		slog.Error("[highlights] failed to create job", slog.Any("err", err))
+		views.HighlightImportError(err).Render(r.Context(), rw)
+		return
+	}
+
+	job, err := s.runner.Submit(highlights.JobKind, items)
+	if err != nil {
+		slog.Error("[highlights] failed to create job", slog.Any("err", err))
+		views.HighlightImportError(err).Render(r.Context(), rw)
+		return
+	}
+
+	views.JobProgress(job).Render(r.Context(), rw)
+}
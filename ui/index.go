@@ -4,6 +4,7 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/kencx/dusk"
 	"github.com/kencx/dusk/page"
@@ -14,20 +15,30 @@ import (
 // Render index page and book library
 func (s *Handler) index(rw http.ResponseWriter, r *http.Request) {
 	filters := initBookFilters(r)
-	if errMap := validator.Validate(filters.Base); errMap != nil {
+	// books on the wishlist are not part of the library unless asked for
+	if filters.Ownership == "" {
+		filters.Ownership = "-wishlist"
+	}
+	if errMap := validator.Validate(filters); errMap != nil {
 		slog.Error("[ui] failed to validate query params", slog.Any("err", errMap.Error()))
-		views.NewIndex(s.base, page.Page[dusk.Book]{}, filters.Base, errors.New("validation error")).Render(rw, r)
+		views.NewIndex(s.base, page.Page[dusk.Book]{}, filters.Base, nil, errors.New("validation error")).Render(rw, r)
 		return
 	}
 
 	p, err := s.db.GetAllBooks(filters)
 	if err != nil {
 		slog.Error("[ui] failed to load index page", slog.Any("err", err))
-		views.NewIndex(s.base, page.Page[dusk.Book]{}, filters.Base, err).Render(rw, r)
+		views.NewIndex(s.base, page.Page[dusk.Book]{}, filters.Base, nil, err).Render(rw, r)
 		return
 	}
 
-	views.NewIndex(s.base, *p, filters.Base, nil).Render(rw, r)
+	// the goal widget is optional
+	goal, err := s.db.GetGoal(time.Now().Year())
+	if err != nil && !errors.Is(err, dusk.ErrDoesNotExist) {
+		slog.Error("[ui] failed to load reading goal", slog.Any("err", err))
+	}
+
+	views.NewIndex(s.base, *p, filters.Base, goal, nil).Render(rw, r)
 }
 
 func (s *Handler) notFound(rw http.ResponseWriter, r *http.Request) {
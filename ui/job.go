@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/ui/partials"
+	"github.com/kencx/dusk/ui/views"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (s *Handler) jobList(rw http.ResponseWriter, r *http.Request) {
+	jobs, err := s.db.GetAllJobs()
+	if err != nil {
+		slog.Error("[ui] failed to get all jobs", slog.Any("err", err))
+		views.NewJobList(s.base, nil, err).Render(rw, r)
+		return
+	}
+	views.NewJobList(s.base, jobs, nil).Render(rw, r)
+}
+
+func (s *Handler) jobPage(rw http.ResponseWriter, r *http.Request) {
+	id := jobId(r)
+
+	job, err := s.db.GetJob(id)
+	if err != nil {
+		if errors.Is(err, dusk.ErrDoesNotExist) {
+			s.notFound(rw, r)
+			return
+		}
+		slog.Error("[ui] failed to get job", slog.Int64("id", id), slog.Any("err", err))
+		views.NewJob(s.base, nil, err).Render(rw, r)
+		return
+	}
+	views.NewJob(s.base, job, nil).Render(rw, r)
+}
+
+func (s *Handler) jobProgress(rw http.ResponseWriter, r *http.Request) {
+	s.renderJobProgress(rw, r, jobId(r))
+}
+
+func (s *Handler) cancelJob(rw http.ResponseWriter, r *http.Request) {
+	id := jobId(r)
+
+	// the job may have finished in the meantime
+	if _, err := s.runner.Cancel(id); err != nil && !errors.Is(err, dusk.ErrNoChange) {
+		slog.Error("[ui] failed to cancel job", slog.Int64("id", id), slog.Any("err", err))
+		partials.Error(err).Render(r.Context(), rw)
+		return
+	}
+	s.renderJobProgress(rw, r, id)
+}
+
+func (s *Handler) retryJob(rw http.ResponseWriter, r *http.Request) {
+	id := jobId(r)
+
+	if _, err := s.runner.Retry(id); err != nil && !errors.Is(err, dusk.ErrNoChange) {
+		slog.Error("[ui] failed to retry job", slog.Int64("id", id), slog.Any("err", err))
+		partials.Error(err).Render(r.Context(), rw)
+		return
+	}
+	s.renderJobProgress(rw, r, id)
+}
+
+func (s *Handler) renderJobProgress(rw http.ResponseWriter, r *http.Request, id int64) {
+	job, err := s.db.GetJob(id)
+	if err != nil {
+		slog.Error("[ui] failed to get job", slog.Int64("id", id), slog.Any("err", err))
+		partials.Error(err).Render(r.Context(), rw)
+		return
+	}
+	views.JobProgress(job).Render(r.Context(), rw)
+}
+
+// jobId returns the id url param. The route only matches digits.
+func jobId(r *http.Request) int64 {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	return id
+}
@@ -16,7 +16,8 @@ var (
 		SortDirection: "ASC",
 		SortSafeList:  filters.DefaultSafeList(),
 	}
-	defaultBookSort = "title"
+	defaultBookSort      = "title"
+	defaultHighlightSort = "rank"
 )
 
 func defaultSearchFilters() *filters.Search {
@@ -32,10 +33,11 @@ func defaultBookFilters() *filters.Book {
 		},
 	}
 	bf.Sort = defaultBookSort
+	bf.SortSafeList = filters.BookSafeList()
 	return bf
 }
 
-func initSearchFilters(r *http.Request) *filters.Search {
+func initSearchFilters(r *http.Request, safeList []string) *filters.Search {
 	qs := r.URL.Query()
 
 	// TODO trim, escape and filter special chars
@@ -46,11 +48,18 @@ func initSearchFilters(r *http.Request) *filters.Search {
 			Limit:         request.QueryInt(qs, page.Limit, defaultFilters.Limit),
 			Sort:          request.QueryString(qs, page.Sort, defaultFilters.Sort),
 			SortDirection: request.QueryString(qs, page.SortDirection, defaultFilters.SortDirection),
-			SortSafeList:  filters.DefaultSafeList(),
+			SortSafeList:  safeList,
 		},
 	}
 }
 
+// initHighlightFilters ranks highlights by best match unless sorted otherwise
+func initHighlightFilters(r *http.Request) *filters.Search {
+	f := initSearchFilters(r, filters.HighlightSafeList())
+	f.Sort = request.QueryString(r.URL.Query(), page.Sort, defaultHighlightSort)
+	return f
+}
+
 func initBookFilters(r *http.Request) *filters.Book {
 	qs := r.URL.Query()
 
@@ -60,6 +69,9 @@ func initBookFilters(r *http.Request) *filters.Book {
 		Author: request.QueryString(qs, "author", ""),
 		Tag:    request.QueryString(qs, "tag", ""),
 		Series: request.QueryString(qs, "series", ""),
+
+		Identifier: request.QueryString(qs, "identifier", ""),
+		Ownership:  request.QueryString(qs, "ownership", ""),
 		Search: filters.Search{
 			Search: request.QueryString(qs, "q", ""),
 			Base: filters.Base{
@@ -67,7 +79,7 @@ func initBookFilters(r *http.Request) *filters.Book {
 				Limit:         request.QueryInt(qs, page.Limit, defaultFilters.Limit),
 				Sort:          request.QueryString(qs, page.Sort, defaultBookSort),
 				SortDirection: request.QueryString(qs, page.SortDirection, defaultFilters.SortDirection),
-				SortSafeList:  filters.DefaultSafeList(),
+				SortSafeList:  filters.BookSafeList(),
 			},
 		},
 	}
@@ -41,7 +41,7 @@ func Library(page page.Page[dusk.Book], filters filters.Base, err error) templ.C
 				return templ_7745c5c3_Err
 			}
 		} else {
-			templ_7745c5c3_Err = ItemSearch("/b/search", ".library__results", filters).Render(ctx, templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = ItemSearch("/b/search", ".library__results", `Search or filter, eg. author:"le guin" tag:scifi rating>=8`, filters).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -29,14 +29,14 @@ func Sidebar(revision string) templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<aside class=\"sidebar\"><div class=\"sidebar__header\"><h1 class=\"sidebar__title\"><a href=\"/\" class=\"sidebar__title-link\"><div class=\"sidebar__icon\">D</div>Dusk</a></h1><p class=\"sidebar__subtitle\"></p></div><nav><ul class=\"sidebar__nav\"><li class=\"sidebar__nav-item\"><a href=\"/\" class=\"sidebar__nav-link sidebar__nav-link--active\">Library</a></li><li class=\"sidebar__nav-item\"><a href=\"/import\" class=\"sidebar__nav-link\">Add a book</a></li><li class=\"sidebar__nav-item\"><a href=\"/authors\" class=\"sidebar__nav-link\">Authors</a></li><li class=\"sidebar__nav-item\"><a href=\"/tags\" class=\"sidebar__nav-link\">Tags</a></li><li class=\"sidebar__nav-item\"><a href=\"#\" class=\"sidebar__nav-link\">Currently Reading</a></li><li class=\"sidebar__nav-item\"><a href=\"#\" class=\"sidebar__nav-link\">Want to Read</a></li><li class=\"sidebar__nav-item\"><a href=\"#\" class=\"sidebar__nav-link\">Finished</a></li><li class=\"sidebar__nav-item\"><a href=\"#\" class=\"sidebar__nav-link\">Options</a></li></ul></nav><div class=\"sidebar__stats\"><div class=\"sidebar__stat\"><span class=\"sidebar__stat-label\">Total Books</span> <span class=\"sidebar__stat-value\">127</span></div><div class=\"sidebar__stat\"><span class=\"sidebar__stat-label\">Read This Year</span> <span class=\"sidebar__stat-value\">23</span></div><div class=\"sidebar__stat\"><span class=\"sidebar__stat-label\">Currently Reading</span> <span class=\"sidebar__stat-value\">3</span></div></div><div class=\"sidebar__footer\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<aside class=\"sidebar\"><div class=\"sidebar__header\"><h1 class=\"sidebar__title\"><a href=\"/\" class=\"sidebar__title-link\"><div class=\"sidebar__icon\">D</div>Dusk</a></h1><p class=\"sidebar__subtitle\"></p></div><nav><ul class=\"sidebar__nav\"><li class=\"sidebar__nav-item\"><a href=\"/\" class=\"sidebar__nav-link sidebar__nav-link--active\">Library</a></li><li class=\"sidebar__nav-item\"><a href=\"/import\" class=\"sidebar__nav-link\">Add a book</a></li><li class=\"sidebar__nav-item\"><a href=\"/authors\" class=\"sidebar__nav-link\">Authors</a></li><li class=\"sidebar__nav-item\"><a href=\"/tags\" class=\"sidebar__nav-link\">Tags</a></li><li class=\"sidebar__nav-item\"><a href=\"/works\" class=\"sidebar__nav-link\">Works</a></li><li class=\"sidebar__nav-item\"><a href=\"/highlights\" class=\"sidebar__nav-link\">Highlights</a></li><li class=\"sidebar__nav-item\"><a href=\"/wishlist\" class=\"sidebar__nav-link\">Wishlist</a></li><li class=\"sidebar__nav-item\"><a href=\"/loans\" class=\"sidebar__nav-link\">Lent out</a></li><li class=\"sidebar__nav-item\"><a href=\"/loans/overdue\" class=\"sidebar__nav-link\">Overdue</a></li><li class=\"sidebar__nav-item\"><a href=\"/collections\" class=\"sidebar__nav-link\">Collections</a></li><li class=\"sidebar__nav-item\"><a href=\"/smart\" class=\"sidebar__nav-link\">Smart Collections</a></li><li hx-get=\"/smart/sidebar\" hx-trigger=\"load\" hx-swap=\"outerHTML\"></li><li class=\"sidebar__nav-item\"><a href=\"/stats\" class=\"sidebar__nav-link\">Statistics</a></li><li class=\"sidebar__nav-item\"><a href=\"/jobs\" class=\"sidebar__nav-link\">Jobs</a></li><li class=\"sidebar__nav-item\"><a href=\"#\" class=\"sidebar__nav-link\">Currently Reading</a></li><li class=\"sidebar__nav-item\"><a href=\"#\" class=\"sidebar__nav-link\">Want to Read</a></li><li class=\"sidebar__nav-item\"><a href=\"#\" class=\"sidebar__nav-link\">Finished</a></li><li class=\"sidebar__nav-item\"><a href=\"#\" class=\"sidebar__nav-link\">Options</a></li></ul></nav><div class=\"sidebar__stats\"><div class=\"sidebar__stat\"><span class=\"sidebar__stat-label\">Total Books</span> <span class=\"sidebar__stat-value\">127</span></div><div class=\"sidebar__stat\"><span class=\"sidebar__stat-label\">Read This Year</span> <span class=\"sidebar__stat-value\">23</span></div><div class=\"sidebar__stat\"><span class=\"sidebar__stat-label\">Currently Reading</span> <span class=\"sidebar__stat-value\">3</span></div></div><div class=\"sidebar__footer\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var2 string
 		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(revision)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `partials/sidebar.templ`, Line: 57, Col: 13}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `partials/sidebar.templ`, Line: 85, Col: 13}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
 		if templ_7745c5c3_Err != nil {
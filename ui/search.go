@@ -8,13 +8,26 @@ import (
 	"time"
 
 	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/dedup"
+	"github.com/kencx/dusk/filters"
 	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/http/response"
 	"github.com/kencx/dusk/null"
 	"github.com/kencx/dusk/ui/views"
 	"github.com/kencx/dusk/util"
 	"github.com/kencx/dusk/validator"
+
+	"github.com/a-h/templ"
 )
 
+// resend the search result with the chosen duplicate action
+var searchDuplicateAttrs = templ.Attributes{
+	"hx-post":              "/search/add",
+	"hx-target":            "#toast-container",
+	"hx-swap":              "beforeend",
+	"hx-on::after-request": "this.closest('.duplicate').remove()",
+}
+
 func (s *Handler) searchPage(rw http.ResponseWriter, r *http.Request) {
 	views.NewImportIndex(s.base, "search", nil).Render(rw, r)
 }
@@ -27,7 +40,7 @@ func (s *Handler) search(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	filters := initSearchFilters(r)
+	filters := initSearchFilters(r, filters.DefaultSafeList())
 	if errMap := validator.Validate(filters); errMap != nil {
 		slog.Error("[ui] failed to validate query params", slog.Any("err", errMap.Error()))
 		views.SearchError(errors.New("Invalid parameters")).Render(r.Context(), rw)
@@ -73,9 +86,13 @@ func (s *Handler) searchAddResult(rw http.ResponseWriter, r *http.Request) {
 	isbn := r.FormValue("result")
 
 	var readStatus dusk.ReadStatus
+	ownership := dusk.Owned
 	switch r.FormValue("read-status") {
 	case "unread":
 		readStatus = dusk.Unread
+	case "wishlist":
+		readStatus = dusk.Unread
+		ownership = dusk.Wishlisted
 	case "read":
 		readStatus = dusk.Read
 	case "reading":
@@ -98,6 +115,10 @@ func (s *Handler) searchAddResult(rw http.ResponseWriter, r *http.Request) {
 	b := metadata.Items[0].ToBook()
 	b.DateAdded = null.TimeFrom(time.Now())
 	b.Status = readStatus
+	b.Ownership = ownership
+	if ownership == dusk.Wishlisted {
+		b.Wishlist = &dusk.WishlistItem{}
+	}
 
 	errMap := validator.Validate(b)
 	if len(errMap) > 0 {
@@ -106,20 +127,37 @@ func (s *Handler) searchAddResult(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	book, err := s.db.CreateBook(b)
+	action, err := dedup.ParseAction(r.FormValue("duplicate"))
 	if err != nil {
-		if errors.Is(err, dusk.ErrIsbnExists) {
-			slog.Error("failed to create book", slog.Any("err", err))
-			SendToastMessage(rw, r, "Book already exists!")
-			return
-		}
+		views.SearchError(err).Render(r.Context(), rw)
+		return
+	}
 
+	book, err := dedup.Add(s.db, b, action)
+
+	var dupErr *dedup.Error
+	switch {
+	case errors.As(err, &dupErr):
+		vals := map[string]string{"result": isbn, "read-status": r.FormValue("read-status")}
+		response.AddHxRetarget(rw, "#search__duplicate", "innerHTML")
+		views.DuplicatePrompt(b, dupErr.Matches, searchDuplicateAttrs, vals).Render(r.Context(), rw)
+		return
+	case errors.Is(err, dusk.ErrSkipped):
+		rawMessage := fmt.Sprintf(`Book <a href="/b/%s">%s</a> already exists`, book.Slugify(), book.Title)
+		SendToastRawMessage(rw, r, rawMessage)
+		return
+	case errors.Is(err, dusk.ErrIsbnExists), errors.Is(err, dusk.ErrIdentifierExists):
+		slog.Error("failed to create book", slog.Any("err", err))
+		SendToastMessage(rw, r, "Book already exists!")
+		return
+	case err != nil:
 		slog.Error("failed to create book", slog.Any("err", err))
 		views.SearchError(err).Render(r.Context(), rw)
 		return
 	}
 
-	if b.Cover.Valid {
+	// a merged book only takes the new cover if it had none
+	if b.Cover.Valid && book.Cover == b.Cover {
 		if err := s.fs.UploadCoverFromUrl(b.Cover.ValueOrZero(), book); err != nil {
 			slog.Warn("failed to download cover", slog.Any("err", err))
 			SendToastMessage(rw, r, "Failed to download cover!")
@@ -133,5 +171,8 @@ func (s *Handler) searchAddResult(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	rawMessage := fmt.Sprintf(`Book <a href="/b/%s">%s</a> added`, book.Slugify(), book.Title)
+	if book.Ownership == dusk.Wishlisted {
+		rawMessage += ` to the <a href="/wishlist">wishlist</a>`
+	}
 	SendToastRawMessage(rw, r, rawMessage)
 }
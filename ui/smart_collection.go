@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/http/response"
+	"github.com/kencx/dusk/page"
+	"github.com/kencx/dusk/ui/views"
+	"github.com/kencx/dusk/validator"
+)
+
+func (s *Handler) smartCollectionList(rw http.ResponseWriter, r *http.Request) {
+	collections, err := s.db.GetAllSmartCollections()
+	if err != nil {
+		slog.Error("[ui] failed to get all smart collections", slog.Any("err", err))
+		views.NewSmartCollectionList(s.base, nil, err).Render(rw, r)
+		return
+	}
+	views.NewSmartCollectionList(s.base, collections, nil).Render(rw, r)
+}
+
+// smartCollectionSidebar lists the smart collections in the sidebar
+func (s *Handler) smartCollectionSidebar(rw http.ResponseWriter, r *http.Request) {
+	collections, err := s.db.GetAllSmartCollections()
+	if err != nil {
+		slog.Error("[ui] failed to get all smart collections", slog.Any("err", err))
+		return
+	}
+	views.SmartCollectionSidebar(collections).Render(r.Context(), rw)
+}
+
+func (s *Handler) createSmartCollection(rw http.ResponseWriter, r *http.Request) {
+	c := smartCollectionFromForm(r)
+	if errMap := validator.Validate(c); errMap != nil {
+		SendToastMessage(rw, r, smartCollectionError(c, errMap))
+		return
+	}
+
+	result, err := s.db.CreateSmartCollection(&c)
+	if err != nil {
+		slog.Error("[ui] failed to create smart collection", slog.Any("err", err))
+		if errors.Is(err, dusk.ErrUniqueConstraint) {
+			SendToastMessage(rw, r, "Smart collection already exists")
+			return
+		}
+		SendToastMessage(rw, r, "Failed to create smart collection")
+		return
+	}
+
+	slog.Info("[ui] Created smart collection", slog.Int64("id", result.Id), slog.String("name", result.Name))
+	response.HxRedirect(rw, r, "/s/"+result.Slugify())
+}
+
+func (s *Handler) smartCollectionPage(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	filters := initBookFilters(r)
+	if errMap := validator.Validate(filters.Base); errMap != nil {
+		slog.Error("[ui] failed to validate query params", slog.Any("err", errMap.Error()))
+		views.NewSmartCollection(s.base, nil, page.Page[dusk.Book]{}, filters.Base, errors.New("validation error")).Render(rw, r)
+		return
+	}
+
+	c, err := s.db.GetSmartCollection(id)
+	if err != nil {
+		slog.Error("[ui] failed to get smart collection", slog.Int64("id", id), slog.Any("err", err))
+		views.NewSmartCollection(s.base, nil, page.Page[dusk.Book]{}, filters.Base, err).Render(rw, r)
+		return
+	}
+
+	books, err := s.db.GetAllBooksFromSmartCollection(id, filters)
+	if err != nil {
+		slog.Error("[ui] failed to get books from smart collection", slog.Int64("id", id), slog.Any("err", err))
+		views.NewSmartCollection(s.base, c, page.Page[dusk.Book]{}, filters.Base, err).Render(rw, r)
+		return
+	}
+	views.NewSmartCollection(s.base, c, *books, filters.Base, nil).Render(rw, r)
+}
+
+func (s *Handler) updateSmartCollection(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	c := smartCollectionFromForm(r)
+	if errMap := validator.Validate(c); errMap != nil {
+		SendToastMessage(rw, r, smartCollectionError(c, errMap))
+		return
+	}
+
+	result, err := s.db.UpdateSmartCollection(id, &c)
+	if err != nil {
+		slog.Error("[ui] failed to update smart collection", slog.Int64("id", id), slog.Any("err", err))
+		if errors.Is(err, dusk.ErrUniqueConstraint) {
+			SendToastMessage(rw, r, "Smart collection already exists")
+			return
+		}
+		SendToastMessage(rw, r, "Failed to update smart collection")
+		return
+	}
+
+	slog.Info("[ui] Updated smart collection", slog.Int64("id", id))
+	response.HxRedirect(rw, r, "/s/"+result.Slugify())
+}
+
+func (s *Handler) deleteSmartCollection(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	if err := s.db.DeleteSmartCollection(id); err != nil {
+		slog.Error("[ui] failed to delete smart collection", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Failed to delete smart collection")
+		return
+	}
+
+	slog.Info("[ui] Deleted smart collection", slog.Int64("id", id))
+	response.HxRedirect(rw, r, "/smart")
+}
+
+func smartCollectionFromForm(r *http.Request) dusk.SmartCollection {
+	return dusk.SmartCollection{
+		Name:  strings.TrimSpace(r.FormValue("name")),
+		Query: strings.TrimSpace(r.FormValue("query")),
+	}
+}
+
+func smartCollectionError(c dusk.SmartCollection, errMap validator.ErrMap) string {
+	if _, ok := errMap["name"]; ok {
+		return "Smart collection name is missing"
+	}
+	if c.Query == "" {
+		return "Smart collection query is missing"
+	}
+	return errMap["query"]
+}
@@ -1,14 +1,20 @@
 package state
 
 import (
-	"github.com/kencx/dusk/filters"
+	"github.com/kencx/dusk/validator"
 )
 
+// Filters are the query params of a list view, eg. *filters.Book
+type Filters interface {
+	validator.Validator
+	Empty() bool
+}
+
 type Base struct {
 	View    string
-	Filters filters.Filters
+	Filters Filters
 }
 
-func NewBase(view string, f filters.Filters) *Base {
+func NewBase(view string, f Filters) *Base {
 	return &Base{view, f}
 }
@@ -32,6 +32,7 @@ func defaultBookFilters() *filters.Book {
 		},
 	}
 	bf.Sort = defaultBookSort
+	bf.SortSafeList = filters.BookSafeList()
 	return bf
 }
 
@@ -67,7 +68,7 @@ func getBookFilters(r *http.Request) *filters.Book {
 				Limit:         request.QueryInt(qs, page.Limit, defaultFilters.Limit),
 				Sort:          request.QueryString(qs, page.Sort, defaultBookSort),
 				SortDirection: request.QueryString(qs, page.SortDirection, defaultFilters.SortDirection),
-				SortSafeList:  filters.DefaultSafeList(),
+				SortSafeList:  filters.BookSafeList(),
 			},
 		},
 	}
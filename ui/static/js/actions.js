@@ -46,3 +46,34 @@ document.body.addEventListener('htmx:afterRequest', function(evt) {
 		errorTarget.removeAttribute("hidden");
 	}
 });
+
+// Reorder the books of a collection by dragging them. Dropping a book triggers
+// the "reorder" event on its form, which saves the new order.
+document.addEventListener("dragstart", function(e) {
+	const item = e.target.closest && e.target.closest(".collection__book")
+	if (item) {
+		item.classList.add("collection__book--dragging")
+		e.dataTransfer.effectAllowed = "move"
+	}
+});
+
+document.addEventListener("dragover", function(e) {
+	const dragging = document.querySelector(".collection__book--dragging")
+	const over = e.target.closest && e.target.closest(".collection__book")
+	if (!dragging || !over || over === dragging || over.parentNode !== dragging.parentNode) {
+		return
+	}
+	e.preventDefault()
+
+	const rect = over.getBoundingClientRect()
+	const after = e.clientY > rect.top + rect.height / 2
+	over.parentNode.insertBefore(dragging, after ? over.nextSibling : over)
+});
+
+document.addEventListener("dragend", function(e) {
+	const item = e.target.closest && e.target.closest(".collection__book")
+	if (item) {
+		item.classList.remove("collection__book--dragging")
+		htmx.trigger(item.closest("form"), "reorder")
+	}
+});
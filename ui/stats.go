@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/ui/views"
+)
+
+func (s *Handler) statsPage(rw http.ResponseWriter, r *http.Request) {
+	year := request.QueryInt(r.URL.Query(), "year", 0)
+	if year < 0 || year > 9999 {
+		slog.Error("[ui] invalid stats year", slog.Int("year", year))
+		views.NewStats(s.base, nil, errors.New("invalid year")).Render(rw, r)
+		return
+	}
+
+	stats, err := s.db.GetStats(year)
+	if err != nil {
+		slog.Error("[ui] failed to get stats", slog.Int("year", year), slog.Any("err", err))
+		views.NewStats(s.base, nil, err).Render(rw, r)
+		return
+	}
+	views.NewStats(s.base, stats, nil).Render(rw, r)
+}
@@ -4,29 +4,44 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strconv"
 
 	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/filters"
 	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/http/response"
 	"github.com/kencx/dusk/page"
 	"github.com/kencx/dusk/ui/views"
 	"github.com/kencx/dusk/validator"
 )
 
 func (s *Handler) tagList(rw http.ResponseWriter, r *http.Request) {
-	filters := initSearchFilters(r)
+	filters := initSearchFilters(r, filters.TagSafeList())
 	if errMap := validator.Validate(filters.Base); errMap != nil {
 		slog.Error("[ui] failed to validate query params", slog.Any("err", errMap.Error()))
 		views.TagSearchResults(page.Page[dusk.Tag]{}, errors.New("validate error")).Render(r.Context(), rw)
 		return
 	}
 
-	tags, err := s.db.GetAllTags(filters)
+	// show the full tree unless searching
+	f := filters
+	if filters.Search == "" {
+		f = nil
+	}
+
+	tags, err := s.db.GetAllTags(f)
 	if err != nil {
 		slog.Error("[ui] failed to get all tags", slog.Any("err", err))
-		views.NewTagList(s.base, page.Page[dusk.Tag]{}, filters.Base, err).Render(rw, r)
+		views.NewTagList(s.base, page.Page[dusk.Tag]{}, nil, filters.Base, err).Render(rw, r)
 		return
 	}
-	views.NewTagList(s.base, *tags, filters.Base, nil).Render(rw, r)
+
+	var tree []*dusk.TagNode
+	if f == nil {
+		tree = dusk.TagTree(tags.Items)
+	}
+	views.NewTagList(s.base, *tags, tree, filters.Base, nil).Render(rw, r)
 }
 
 func (s *Handler) tagDataList(rw http.ResponseWriter, r *http.Request) {
@@ -48,13 +63,24 @@ func (s *Handler) tagSearch(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	filters := initSearchFilters(r)
+	filters := initSearchFilters(r, filters.TagSafeList())
 	if errMap := validator.Validate(filters.Base); errMap != nil {
 		slog.Error("[ui] failed to validate query params", slog.Any("err", errMap.Error()))
 		views.TagSearchResults(page.Page[dusk.Tag]{}, errors.New("validate error")).Render(r.Context(), rw)
 		return
 	}
 
+	if filters.Search == "" {
+		p, err := s.db.GetAllTags(nil)
+		if err != nil {
+			slog.Error("failed to get all tags", slog.Any("err", err))
+			views.TagSearchResults(page.Page[dusk.Tag]{}, err).Render(r.Context(), rw)
+			return
+		}
+		views.TagTreeResults(dusk.TagTree(p.Items)).Render(r.Context(), rw)
+		return
+	}
+
 	p, err := s.db.GetAllTags(filters)
 	if err != nil {
 		slog.Error("failed to get all tags", slog.Any("err", err))
@@ -80,15 +106,147 @@ func (s *Handler) tagPage(rw http.ResponseWriter, r *http.Request) {
 	tag, err := s.db.GetTag(id)
 	if err != nil {
 		slog.Error("[ui] failed to get tag", slog.Int64("id", id), slog.Any("err", err))
-		views.NewTag(s.base, dusk.Tag{}, page.Page[dusk.Book]{}, filters.Base, err).Render(rw, r)
+		views.NewTag(s.base, dusk.Tag{}, nil, nil, nil, page.Page[dusk.Book]{}, filters.Base, err).Render(rw, r)
 		return
 	}
 
+	all, err := s.db.GetAllTags(nil)
+	if err != nil {
+		slog.Error("[ui] failed to get all tags", slog.Any("err", err))
+		views.NewTag(s.base, dusk.Tag{}, nil, nil, nil, page.Page[dusk.Book]{}, filters.Base, err).Render(rw, r)
+		return
+	}
+	ancestors, children := tagRelatives(*tag, all.Items)
+	others := slices.DeleteFunc(all.Items, func(t dusk.Tag) bool {
+		return t.Id == tag.Id
+	})
+
 	books, err := s.db.GetAllBooksFromTag(tag.Id, filters)
 	if err != nil {
 		slog.Error("[ui] failed to get books from tag", slog.Int64("id", id), slog.Any("err", err))
-		views.NewTag(s.base, dusk.Tag{}, page.Page[dusk.Book]{}, filters.Base, err).Render(rw, r)
+		views.NewTag(s.base, dusk.Tag{}, nil, nil, nil, page.Page[dusk.Book]{}, filters.Base, err).Render(rw, r)
 		return
 	}
-	views.NewTag(s.base, *tag, *books, filters.Base, nil).Render(rw, r)
+	views.NewTag(s.base, *tag, ancestors, children, others, *books, filters.Base, nil).Render(rw, r)
+}
+
+func (s *Handler) updateTag(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	tag := dusk.Tag{Name: dusk.NormaliseTagName(r.FormValue("name"))}
+	if errMap := validator.Validate(tag); errMap != nil {
+		SendToastMessage(rw, r, "Invalid tag name")
+		return
+	}
+
+	result, err := s.db.UpdateTag(id, &tag)
+	if err != nil {
+		slog.Error("[ui] failed to update tag", slog.Int64("id", id), slog.Any("err", err))
+		switch {
+		case errors.Is(err, dusk.ErrTagCycle):
+			SendToastMessage(rw, r, "Tag cannot be nested under itself")
+		case errors.Is(err, dusk.ErrUniqueConstraint):
+			SendToastMessage(rw, r, "Tag already exists")
+		default:
+			SendToastMessage(rw, r, "Failed to rename tag")
+		}
+		return
+	}
+
+	slog.Info("[ui] Renamed tag", slog.Int64("id", id), slog.String("name", result.Name))
+	response.HxRedirect(rw, r, "/t/"+result.Slugify())
+}
+
+func (s *Handler) mergeTags(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		SendToastMessage(rw, r, "Invalid tags to merge")
+		return
+	}
+
+	var ids []int64
+	for _, v := range r.Form["tags"] {
+		otherId, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || otherId == id {
+			SendToastMessage(rw, r, "Invalid tags to merge")
+			return
+		}
+		ids = append(ids, otherId)
+	}
+	if len(ids) == 0 {
+		SendToastMessage(rw, r, "No tags to merge")
+		return
+	}
+
+	tag, err := s.db.MergeTags(id, ids)
+	if err != nil {
+		slog.Error("[ui] failed to merge tags", slog.Int64("id", id), slog.Any("others", ids), slog.Any("err", err))
+		if errors.Is(err, dusk.ErrTagCycle) {
+			SendToastMessage(rw, r, "Cannot merge a parent tag into its child")
+			return
+		}
+		SendToastMessage(rw, r, "Failed to merge tags")
+		return
+	}
+
+	slog.Info("[ui] Merged tags", slog.Int64("id", id), slog.Any("others", ids))
+	response.HxRedirect(rw, r, "/t/"+tag.Slugify())
+}
+
+func (s *Handler) deleteTag(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	if err := s.db.DeleteTag(id); err != nil {
+		slog.Error("[ui] failed to delete tag", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Failed to delete tag")
+		return
+	}
+
+	slog.Info("[ui] Deleted tag", slog.Int64("id", id))
+	response.HxRedirect(rw, r, "/tags")
+}
+
+func (s *Handler) deleteUnusedTags(rw http.ResponseWriter, r *http.Request) {
+	count, err := s.db.DeleteUnusedTags()
+	if err != nil {
+		slog.Error("[ui] failed to delete unused tags", slog.Any("err", err))
+		SendToastMessage(rw, r, "Failed to delete unused tags")
+		return
+	}
+
+	slog.Info("[ui] Deleted unused tags", slog.Int64("count", count))
+	response.HxRedirect(rw, r, "/tags")
+}
+
+// tagRelatives returns the ancestors of tag, from the top-level tag down, and
+// its immediate children. Missing ancestors have no id.
+func tagRelatives(tag dusk.Tag, all []dusk.Tag) ([]dusk.Tag, []dusk.Tag) {
+	byName := make(map[string]dusk.Tag, len(all))
+	var children []dusk.Tag
+	for _, t := range all {
+		byName[t.Name] = t
+		if t.Parent() == tag.Name {
+			children = append(children, t)
+		}
+	}
+
+	var ancestors []dusk.Tag
+	for _, name := range tag.Ancestors() {
+		t, ok := byName[name]
+		if !ok {
+			t = dusk.Tag{Name: name}
+		}
+		ancestors = append(ancestors, t)
+	}
+	return ancestors, children
 }
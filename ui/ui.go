@@ -5,21 +5,23 @@ import (
 	"github.com/kencx/dusk/file"
 	"github.com/kencx/dusk/http/response"
 	"github.com/kencx/dusk/integration"
+	"github.com/kencx/dusk/jobs"
 	"github.com/kencx/dusk/ui/shared"
 
 	"github.com/go-chi/chi/v5"
 )
 
 type Handler struct {
-	db   dusk.Store
-	fs   *file.Service
-	f    integration.Fetchers
-	base shared.Base
+	db     dusk.Store
+	fs     *file.Service
+	f      integration.Fetchers
+	runner *jobs.Runner
+	base   shared.Base
 }
 
-func Router(revision string, db dusk.Store, fs *file.Service, f integration.Fetchers) chi.Router {
+func Router(revision string, db dusk.Store, fs *file.Service, f integration.Fetchers, runner *jobs.Runner) chi.Router {
 	base := shared.NewBase(revision)
-	s := Handler{db, fs, f, base}
+	s := Handler{db, fs, f, runner, base}
 	ui := chi.NewRouter()
 
 	// middlewares
@@ -33,9 +35,24 @@ func Router(revision string, db dusk.Store, fs *file.Service, f integration.Fetc
 	ui.Route("/b", func(c chi.Router) {
 		c.Get("/{slug:[a-zA-Z0-9-]+}", s.bookPage)
 		c.Get("/{slug:[a-zA-Z0-9-]+}/edit", s.editBookForm)
+		c.Get("/{slug:[a-zA-Z0-9-]+}/merge", s.bookMergePage)
+		c.Post("/{slug:[a-zA-Z0-9-]+}/merge", s.mergeBook)
 		c.Put("/{slug:[a-zA-Z0-9-]+}", s.updateBook)
 		c.Put("/{slug:[a-zA-Z0-9-]+}/status", s.updateBookStatus)
 		c.Delete("/{slug:[a-zA-Z0-9-]+}", s.deleteBook)
+		c.Get("/{slug:[a-zA-Z0-9-]+}/collections", s.bookCollections)
+		c.Post("/{slug:[a-zA-Z0-9-]+}/collections", s.addBookToCollection)
+		c.Get("/{slug:[a-zA-Z0-9-]+}/work", s.bookWork)
+		c.Post("/{slug:[a-zA-Z0-9-]+}/work", s.addBookToWork)
+		c.Delete("/{slug:[a-zA-Z0-9-]+}/work", s.removeBookFromWork)
+		c.Get("/{slug:[a-zA-Z0-9-]+}/copies", s.bookCopies)
+		c.Post("/{slug:[a-zA-Z0-9-]+}/copies", s.addCopy)
+		c.Delete("/{slug:[a-zA-Z0-9-]+}/copies/{copy:[0-9]+}", s.deleteCopy)
+		c.Post("/{slug:[a-zA-Z0-9-]+}/copies/{copy:[0-9]+}/loans", s.lendCopy)
+		c.Post("/{slug:[a-zA-Z0-9-]+}/copies/{copy:[0-9]+}/return", s.returnCopy)
+		c.Get("/{slug:[a-zA-Z0-9-]+}/highlights", s.bookHighlights)
+		c.Post("/{slug:[a-zA-Z0-9-]+}/highlights", s.addHighlight)
+		c.Delete("/{slug:[a-zA-Z0-9-]+}/highlights/{highlight:[0-9]+}", s.deleteHighlight)
 		c.Get("/search", s.bookSearch)
 
 		// c.Get("/partials/rating", s.bookRatingPartial)
@@ -48,6 +65,8 @@ func Router(revision string, db dusk.Store, fs *file.Service, f integration.Fetc
 	ui.HandleFunc("/authors", s.authorList)
 	ui.Route("/a", func(c chi.Router) {
 		c.Get("/{slug:[a-zA-Z0-9-]+}", s.authorPage)
+		c.Post("/{slug:[a-zA-Z0-9-]+}/merge", s.mergeAuthor)
+		c.Post("/{slug:[a-zA-Z0-9-]+}/fetch", s.fetchAuthor)
 		// c.Put("/{slug:[a-zA-Z0-9-]+}", s.updateAuthor)
 		// c.Delete("/{slug:[a-zA-Z0-9-]+}", s.deleteAuthor)
 		c.Get("/search", s.authorSearch)
@@ -57,11 +76,63 @@ func Router(revision string, db dusk.Store, fs *file.Service, f integration.Fetc
 	ui.Route("/t", func(c chi.Router) {
 		c.Get("/{slug:[a-zA-Z0-9-]+}", s.tagPage)
 		c.Get("/all", s.tagDataList)
-		// c.Put("/{slug:[a-zA-Z0-9-]+}", s.updatetag)
-		// c.Delete("/{slug:[a-zA-Z0-9-]+}", s.deletetag)
+		c.Put("/{slug:[a-zA-Z0-9-]+}", s.updateTag)
+		c.Post("/{slug:[a-zA-Z0-9-]+}/merge", s.mergeTags)
+		c.Delete("/{slug:[a-zA-Z0-9-]+}", s.deleteTag)
+		c.Delete("/unused", s.deleteUnusedTags)
 		c.Get("/search", s.tagSearch)
 	})
 
+	ui.Get("/works", s.workList)
+	ui.Route("/w", func(c chi.Router) {
+		c.Get("/{slug:[a-zA-Z0-9-]+}", s.workPage)
+		c.Put("/{slug:[a-zA-Z0-9-]+}", s.updateWork)
+		c.Delete("/{slug:[a-zA-Z0-9-]+}", s.deleteWork)
+		c.Delete("/{slug:[a-zA-Z0-9-]+}/books/{book:[0-9]+}", s.removeWorkBook)
+	})
+
+	ui.Route("/wishlist", func(c chi.Router) {
+		c.Get("/", s.wishlistPage)
+		c.Put("/{id:[0-9]+}", s.updateWishlistItem)
+		c.Post("/{id:[0-9]+}/own", s.ownWishlistBook)
+	})
+
+	ui.Route("/loans", func(c chi.Router) {
+		c.Get("/", s.loanList)
+		c.Get("/overdue", s.overdueLoanList)
+		c.Post("/{id:[0-9]+}/return", s.returnLoan)
+	})
+
+	ui.Route("/highlights", func(c chi.Router) {
+		c.Get("/", s.highlightList)
+		c.Get("/search", s.highlightSearch)
+		c.Post("/import", s.importHighlights)
+	})
+
+	ui.Route("/collections", func(c chi.Router) {
+		c.Get("/", s.collectionList)
+		c.Post("/", s.createCollection)
+	})
+	ui.Route("/c", func(c chi.Router) {
+		c.Get("/{slug:[a-zA-Z0-9-]+}", s.collectionPage)
+		c.Put("/{slug:[a-zA-Z0-9-]+}", s.updateCollection)
+		c.Delete("/{slug:[a-zA-Z0-9-]+}", s.deleteCollection)
+		c.Put("/{slug:[a-zA-Z0-9-]+}/order", s.reorderCollection)
+		c.Delete("/{slug:[a-zA-Z0-9-]+}/books/{book:[0-9]+}", s.removeCollectionBook)
+	})
+
+	ui.Route("/smart", func(c chi.Router) {
+		c.Get("/", s.smartCollectionList)
+		c.Post("/", s.createSmartCollection)
+		c.Get("/sidebar", s.smartCollectionSidebar)
+	})
+	ui.Route("/s", func(c chi.Router) {
+		c.Get("/{slug:[a-zA-Z0-9-]+}", s.smartCollectionPage)
+		c.Put("/{slug:[a-zA-Z0-9-]+}", s.updateSmartCollection)
+		c.Delete("/{slug:[a-zA-Z0-9-]+}", s.deleteSmartCollection)
+	})
+
+	ui.HandleFunc("/stats", s.statsPage)
 	ui.HandleFunc("/import", s.importIndex)
 
 	ui.Route("/search", func(c chi.Router) {
@@ -85,10 +156,20 @@ func Router(revision string, db dusk.Store, fs *file.Service, f integration.Fetc
 		c.Post("/", s.goodreads)
 	})
 
-	// ui.Route("/calibre", func(c chi.Router) {
-	// 	c.Get("/", s.calibrePage)
-	// 	c.Post("/", s.calibre)
-	// })
+	ui.Get("/export/goodreads", s.exportGoodreads)
+
+	ui.Route("/calibre", func(c chi.Router) {
+		c.Get("/", s.calibrePage)
+		c.Post("/", s.calibre)
+	})
+
+	ui.HandleFunc("/jobs", s.jobList)
+	ui.Route("/jobs/{id:[0-9]+}", func(c chi.Router) {
+		c.Get("/", s.jobPage)
+		c.Get("/progress", s.jobProgress)
+		c.Post("/cancel", s.cancelJob)
+		c.Post("/retry", s.retryJob)
+	})
 
 	ui.NotFound(s.notFound)
 	return ui
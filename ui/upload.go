@@ -7,12 +7,24 @@ import (
 	"time"
 
 	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/dedup"
 	"github.com/kencx/dusk/http/request"
 	"github.com/kencx/dusk/http/response"
 	"github.com/kencx/dusk/null"
 	"github.com/kencx/dusk/ui/views"
+
+	"github.com/a-h/templ"
 )
 
+// resend the upload form with the chosen duplicate action
+var uploadDuplicateAttrs = templ.Attributes{
+	"hx-post":     "/upload",
+	"hx-include":  ".upload-form",
+	"hx-encoding": "multipart/form-data",
+	"hx-target":   "#upload__results",
+	"hx-swap":     "innerHTML",
+}
+
 func (s *Handler) uploadPage(rw http.ResponseWriter, r *http.Request) {
 	views.NewImportIndex(s.base, "upload", nil).Render(rw, r)
 }
@@ -33,15 +45,26 @@ func (s *Handler) upload(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	b.DateAdded = null.TimeFrom(time.Now())
-	res, err := s.db.CreateBook(b)
+	action, err := dedup.ParseAction(r.FormValue("duplicate"))
 	if err != nil {
-		if errors.Is(err, dusk.ErrIsbnExists) {
-			slog.Error("[UI] Failed to create book", slog.Any("err", err))
-			views.UploadError(err).Render(r.Context(), rw)
-			return
-		}
+		views.UploadError(err).Render(r.Context(), rw)
+		return
+	}
+
+	b.DateAdded = null.TimeFrom(time.Now())
+	res, err := dedup.Add(s.db, b, action)
 
+	var dupErr *dedup.Error
+	switch {
+	case errors.As(err, &dupErr):
+		views.DuplicatePrompt(b, dupErr.Matches, uploadDuplicateAttrs, nil).Render(r.Context(), rw)
+		return
+	case errors.Is(err, dusk.ErrSkipped) && action == dedup.Skip:
+		views.DuplicateSkipped(res).Render(r.Context(), rw)
+		return
+	case errors.Is(err, dusk.ErrSkipped):
+		// nothing to merge, but the file is still added to the existing book
+	case err != nil:
 		slog.Error("[UI] Failed to create book", slog.Any("err", err))
 		views.UploadError(err).Render(r.Context(), rw)
 		return
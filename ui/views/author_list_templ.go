@@ -77,7 +77,7 @@ func (v *AuthorList) Html() templ.Component {
 					return templ_7745c5c3_Err
 				}
 			} else {
-				templ_7745c5c3_Err = partials.ItemSearch("/a/search", ".list", v.filters).Render(ctx, templ_7745c5c3_Buffer)
+				templ_7745c5c3_Err = partials.ItemSearch("/a/search", ".list", "Search by name...", v.filters).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
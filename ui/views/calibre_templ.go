@@ -8,6 +8,10 @@ package views
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
+import (
+	"github.com/kencx/dusk/ui/partials"
+)
+
 func calibreForm() templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -29,7 +33,7 @@ func calibreForm() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<form class=\"calibre-form\" hx-post=\"/calibre\" enctype=\"multipart/form-data\" hx-target=\"#calibre__result_list\" hx-swap=\"innerHTML\" hx-indicator=\".spinner\"><div class=\"fileinput\"><input type=\"file\" id=\"calibre\" name=\"calibre\" required webkitdirectory directory> <small class=\"fileinput__info\">Note: Point to your Calibre directory</small></div><div class=\"controls__actions\"><button class=\"btn\" type=\"submit\">Submit</button></div></form><div id=\"calibre__result_list\"></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<form class=\"calibre-form\" hx-post=\"/calibre\" hx-target=\"#calibre__result_list\" hx-swap=\"innerHTML\" hx-indicator=\".spinner\"><div class=\"fileinput\"><input type=\"text\" id=\"calibre\" name=\"calibre\" placeholder=\"/path/to/Calibre Library\" required> <small class=\"fileinput__info\">Note: Path to your Calibre library directory on the server</small></div><div class=\"controls__actions\"><button class=\"btn\" type=\"submit\">Submit</button></div></form><div id=\"calibre__result_list\"></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -37,4 +41,38 @@ func calibreForm() templ.Component {
 	})
 }
 
+func CalibreError(err error) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var2 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var2 == nil {
+			templ_7745c5c3_Var2 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		if err != nil {
+			switch err {
+			default:
+				templ_7745c5c3_Err = partials.Error(err).Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+		}
+		return nil
+	})
+}
+
 var _ = templruntime.GeneratedTemplate
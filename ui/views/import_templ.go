@@ -17,7 +17,7 @@ import (
 var (
 	ImportTabs = partials.TabGroup{
 		RootPath: "/import",
-		Order:    []string{"search", "upload", "goodreads", "calibre", "manual"},
+		Order:    []string{"search", "upload", "goodreads", "calibre", "highlights", "manual"},
 		Tabs: []partials.Tab{
 			{
 				Name:      "Search",
@@ -39,6 +39,11 @@ var (
 				Link:      "calibre",
 				Component: calibreForm(),
 			},
+			{
+				Name:      "Highlights",
+				Link:      "highlights",
+				Component: highlightImportForm(),
+			},
 			{
 				Name:      "Manual",
 				Link:      "manual",
@@ -20,12 +20,13 @@ import (
 type Index struct {
 	page    page.Page[dusk.Book]
 	filters filters.Base
+	goal    *dusk.Goal
 	shared.Base
 }
 
-func NewIndex(base shared.Base, page page.Page[dusk.Book], filters filters.Base, err error) *Index {
+func NewIndex(base shared.Base, page page.Page[dusk.Book], filters filters.Base, goal *dusk.Goal, err error) *Index {
 	base.Err = err
-	return &Index{page, filters, base}
+	return &Index{page, filters, goal, base}
 }
 
 func (v *Index) Render(rw http.ResponseWriter, r *http.Request) {
@@ -65,7 +66,15 @@ func (v *Index) Html() templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<h2>Library</h2><div class=\"library\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<h2>Library</h2>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = partials.Goal(v.goal).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, " <div class=\"library\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -73,7 +82,7 @@ func (v *Index) Html() templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "</div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "</div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
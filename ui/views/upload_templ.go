@@ -101,9 +101,6 @@ func UploadSuccess(book *dusk.Book) templ.Component {
 	})
 }
 
-// TODO if book already exists, give existing book link
-// if similar book but different isbn, warn about similar book with link, give option to
-// merge
 func UploadError(err error) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
@@ -127,7 +124,7 @@ func UploadError(err error) templ.Component {
 		ctx = templ.ClearChildren(ctx)
 		if err != nil {
 			switch {
-			case errors.Is(err, dusk.ErrIsbnExists):
+			case errors.Is(err, dusk.ErrIsbnExists), errors.Is(err, dusk.ErrIdentifierExists):
 				templ_7745c5c3_Err = partials.ErrorFromString("Book already exists!").Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/null"
+	"github.com/kencx/dusk/ui/views"
+	"github.com/kencx/dusk/validator"
+)
+
+func (s *Handler) wishlistPage(rw http.ResponseWriter, r *http.Request) {
+	books, err := s.db.GetWishlist()
+	if err != nil {
+		slog.Error("[ui] failed to get wishlist", slog.Any("err", err))
+		views.NewWishlist(s.base, nil, err).Render(rw, r)
+		return
+	}
+	views.NewWishlist(s.base, books, nil).Render(rw, r)
+}
+
+// updateWishlistItem updates the price, priority and notes of a book on the
+// wishlist
+func (s *Handler) updateWishlistItem(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	book, err := s.db.GetBook(id)
+	if err != nil || book.Ownership != dusk.Wishlisted {
+		slog.Error("[ui] failed to get book on wishlist", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Book is not on the wishlist")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		SendToastMessage(rw, r, "Invalid wishlist details")
+		return
+	}
+
+	var w dusk.WishlistItem
+	if request.HasValue(r.Form, "price") {
+		price, err := strconv.ParseFloat(r.FormValue("price"), 64)
+		if err != nil {
+			SendToastMessage(rw, r, "Invalid price")
+			return
+		}
+		w.Price = null.FloatFrom(price)
+	}
+	if request.HasValue(r.Form, "priority") {
+		w.Priority, err = strconv.Atoi(r.FormValue("priority"))
+		if err != nil {
+			SendToastMessage(rw, r, "Invalid priority")
+			return
+		}
+	}
+	if notes := strings.TrimSpace(r.FormValue("notes")); notes != "" {
+		w.Notes = null.StringFrom(notes)
+	}
+
+	if errMap := validator.Validate(w); errMap != nil {
+		if _, ok := errMap["price"]; ok {
+			SendToastMessage(rw, r, "Price must not be negative")
+			return
+		}
+		SendToastMessage(rw, r, "Invalid priority")
+		return
+	}
+
+	book.Wishlist = &w
+	result, err := s.db.UpdateBook(id, book)
+	if err != nil {
+		slog.Error("[ui] failed to update wishlist", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Failed to update wishlist")
+		return
+	}
+
+	slog.Info("[ui] Updated wishlist", slog.Int64("id", id))
+	views.WishlistEntry(result).Render(r.Context(), rw)
+}
+
+// ownWishlistBook moves a book from the wishlist into the library
+func (s *Handler) ownWishlistBook(rw http.ResponseWriter, r *http.Request) {
+	id := request.HandleInt64("id", rw, r)
+	if id == -1 {
+		return
+	}
+
+	book, err := s.db.GetBook(id)
+	if err != nil {
+		slog.Error("[ui] failed to get book", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Book not found")
+		return
+	}
+
+	book.Ownership = dusk.Owned
+	if _, err := s.db.UpdateBook(id, book); err != nil {
+		slog.Error("[ui] failed to move book to library", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Failed to move book to library")
+		return
+	}
+
+	slog.Info("[ui] Moved book from wishlist to library", slog.Int64("id", id))
+	// remove the book from the wishlist
+	rw.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,226 @@
+package ui
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kencx/dusk"
+	"github.com/kencx/dusk/http/request"
+	"github.com/kencx/dusk/http/response"
+	"github.com/kencx/dusk/ui/views"
+	"github.com/kencx/dusk/validator"
+)
+
+func (s *Handler) workList(rw http.ResponseWriter, r *http.Request) {
+	works, err := s.db.GetAllWorks()
+	if err != nil {
+		slog.Error("[ui] failed to get all works", slog.Any("err", err))
+		views.NewWorkList(s.base, nil, err).Render(rw, r)
+		return
+	}
+	views.NewWorkList(s.base, works, nil).Render(rw, r)
+}
+
+func (s *Handler) workPage(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	w, err := s.db.GetWork(id)
+	if err != nil {
+		slog.Error("[ui] failed to get work", slog.Int64("id", id), slog.Any("err", err))
+		views.NewWork(s.base, nil, err).Render(rw, r)
+		return
+	}
+	views.NewWork(s.base, w, nil).Render(rw, r)
+}
+
+func (s *Handler) updateWork(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	w, err := s.db.GetWork(id)
+	if err != nil {
+		slog.Error("[ui] failed to get work", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Work not found")
+		return
+	}
+
+	w.Title = strings.TrimSpace(r.FormValue("title"))
+	if rating := r.FormValue("rating"); rating != "" {
+		w.Rating, err = strconv.Atoi(rating)
+		if err != nil {
+			SendToastMessage(rw, r, "Invalid rating")
+			return
+		}
+	}
+	if errMap := validator.Validate(w); errMap != nil {
+		if _, ok := errMap["title"]; ok {
+			SendToastMessage(rw, r, "Work title is missing")
+			return
+		}
+		SendToastMessage(rw, r, "Rating must be between 0 and 10")
+		return
+	}
+
+	result, err := s.db.UpdateWork(id, w)
+	if err != nil {
+		slog.Error("[ui] failed to update work", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Failed to update work")
+		return
+	}
+
+	slog.Info("[ui] Updated work", slog.Int64("id", id))
+	response.HxRedirect(rw, r, "/w/"+result.Slugify())
+}
+
+func (s *Handler) deleteWork(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	if err := s.db.DeleteWork(id); err != nil {
+		slog.Error("[ui] failed to delete work", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Failed to delete work")
+		return
+	}
+
+	slog.Info("[ui] Deleted work", slog.Int64("id", id))
+	response.HxRedirect(rw, r, "/works")
+}
+
+func (s *Handler) removeWorkBook(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	bookId, err := strconv.ParseInt(chi.URLParam(r, "book"), 10, 64)
+	if err != nil {
+		SendToastMessage(rw, r, "Invalid book")
+		return
+	}
+
+	if err := s.db.RemoveBookFromWork(id, bookId); err != nil {
+		slog.Error("[ui] failed to remove book from work", slog.Int64("id", id), slog.Int64("book", bookId), slog.Any("err", err))
+		SendToastMessage(rw, r, "Failed to remove edition")
+		return
+	}
+
+	// the work is deleted with its last edition
+	if _, err := s.db.GetWork(id); errors.Is(err, dusk.ErrDoesNotExist) {
+		response.HxRedirect(rw, r, "/works")
+		return
+	}
+
+	// remove the edition from the list
+	rw.WriteHeader(http.StatusOK)
+}
+
+// Render the other editions of a book with a form to group it with other
+// editions
+func (s *Handler) bookWork(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+	s.renderBookWork(rw, r, id)
+}
+
+// addBookToWork adds the book to an existing work, or creates a new work
+// with the book as its first edition
+func (s *Handler) addBookToWork(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	if r.FormValue("work") == "new" {
+		book, err := s.db.GetBook(id)
+		if err != nil {
+			slog.Error("[ui] failed to get book", slog.Int64("id", id), slog.Any("err", err))
+			SendToastMessage(rw, r, "Book not found")
+			return
+		}
+
+		book.Work = &dusk.Work{Title: book.Title}
+		if _, err := s.db.UpdateBook(id, book); err != nil {
+			slog.Error("[ui] failed to create work", slog.Int64("id", id), slog.Any("err", err))
+			SendToastMessage(rw, r, "Failed to create work")
+			return
+		}
+	} else {
+		workId, err := strconv.ParseInt(r.FormValue("work"), 10, 64)
+		if err != nil {
+			SendToastMessage(rw, r, "Invalid work")
+			return
+		}
+
+		if _, err := s.db.AddBookToWork(workId, id); err != nil {
+			slog.Error("[ui] failed to add book to work", slog.Int64("id", id), slog.Int64("work", workId), slog.Any("err", err))
+			SendToastMessage(rw, r, "Failed to add book to work")
+			return
+		}
+	}
+
+	slog.Info("[ui] Added book to work", slog.Int64("id", id))
+	s.renderBookWork(rw, r, id)
+}
+
+func (s *Handler) removeBookFromWork(rw http.ResponseWriter, r *http.Request) {
+	id := request.FetchIdFromSlug(rw, r)
+	if id == -1 {
+		return
+	}
+
+	book, err := s.db.GetBook(id)
+	if err != nil {
+		slog.Error("[ui] failed to get book", slog.Int64("id", id), slog.Any("err", err))
+		SendToastMessage(rw, r, "Book not found")
+		return
+	}
+
+	if book.Work != nil {
+		if err := s.db.RemoveBookFromWork(book.Work.Id, id); err != nil {
+			slog.Error("[ui] failed to remove book from work", slog.Int64("id", id), slog.Int64("work", book.Work.Id), slog.Any("err", err))
+			SendToastMessage(rw, r, "Failed to remove book from work")
+			return
+		}
+	}
+
+	slog.Info("[ui] Removed book from work", slog.Int64("id", id))
+	s.renderBookWork(rw, r, id)
+}
+
+func (s *Handler) renderBookWork(rw http.ResponseWriter, r *http.Request, id int64) {
+	book, err := s.db.GetBook(id)
+	if err != nil {
+		slog.Error("[ui] failed to get book", slog.Int64("id", id), slog.Any("err", err))
+		return
+	}
+
+	if book.Work != nil {
+		work, err := s.db.GetWork(book.Work.Id)
+		if err != nil {
+			slog.Error("[ui] failed to get work of book", slog.Int64("id", id), slog.Any("err", err))
+			return
+		}
+		views.BookWork(book, work, nil).Render(r.Context(), rw)
+		return
+	}
+
+	works, err := s.db.GetAllWorks()
+	if err != nil {
+		slog.Error("[ui] failed to get all works", slog.Any("err", err))
+		return
+	}
+	views.BookWork(book, nil, works).Render(r.Context(), rw)
+}
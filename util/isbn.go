@@ -107,3 +107,44 @@ func isbn13Validate(isbn string) bool {
 
 	return sum%10 == 0
 }
+
+// Isbn10To13 converts an ISBN-10 to its ISBN-13 with the 978 prefix. It
+// returns an empty string if the value is not a valid ISBN-10.
+func Isbn10To13(isbn string) string {
+	isbn = strings.ToUpper(strings.ReplaceAll(isbn, "-", ""))
+	if len(isbn) != 10 || !isbn10Validate(isbn) {
+		return ""
+	}
+
+	result := "978" + isbn[:9]
+	var sum int
+	for i, c := range result {
+		mul := 1
+		if i%2 != 0 {
+			mul = 3
+		}
+		sum += int(c-'0') * mul
+	}
+	return result + strconv.Itoa((10-sum%10)%10)
+}
+
+// Isbn13To10 converts an ISBN-13 to an ISBN-10. Only ISBN-13s with the 978
+// prefix have an ISBN-10, otherwise an empty string is returned.
+func Isbn13To10(isbn string) string {
+	isbn = strings.ReplaceAll(isbn, "-", "")
+	if len(isbn) != 13 || !strings.HasPrefix(isbn, "978") || !isbn13Validate(isbn) {
+		return ""
+	}
+
+	result := isbn[3:12]
+	var sum int
+	for i, c := range result {
+		sum += int(c-'0') * (10 - i)
+	}
+
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return result + "X"
+	}
+	return result + strconv.Itoa(check)
+}
@@ -71,3 +71,25 @@ func TestIsbnCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestIsbnConvert(t *testing.T) {
+	tests := []struct {
+		isbn10 string
+		isbn13 string
+	}{
+		{"0143039822", "9780143039822"},
+		{"048624895X", "9780486248950"},
+		{"0316129089", "9780316129084"},
+	}
+
+	is := is.New(t)
+	for _, tt := range tests {
+		t.Run(tt.isbn10, func(t *testing.T) {
+			is.Equal(Isbn10To13(tt.isbn10), tt.isbn13)
+			is.Equal(Isbn13To10(tt.isbn13), tt.isbn10)
+		})
+	}
+
+	is.Equal(Isbn10To13("123156789X"), "")
+	is.Equal(Isbn13To10("9791234567896"), "")
+}
@@ -0,0 +1,33 @@
+package dusk
+
+import (
+	"github.com/kencx/dusk/null"
+	"github.com/kencx/dusk/validator"
+)
+
+// WishlistPriorities are the priorities of a book on the wishlist, from lowest
+// to highest.
+var WishlistPriorities = []string{"none", "low", "medium", "high"}
+
+// WishlistItem holds the details of a book that is wanted but not owned yet.
+type WishlistItem struct {
+	Price    null.Float  `json:"price" db:"price"`
+	Priority int         `json:"priority" db:"priority"`
+	Notes    null.String `json:"notes,omitempty" db:"notes"`
+}
+
+func (w WishlistItem) Valid() validator.ErrMap {
+	errMap := validator.New()
+
+	errMap.Check(w.Price.ValueOrZero() >= 0, "price", "must be >= 0")
+	errMap.Check(w.Priority >= 0 && w.Priority < len(WishlistPriorities), "priority", "invalid priority: must be between 0 and 3")
+	return errMap
+}
+
+// PriorityName returns the name of the priority, e.g. high.
+func (w WishlistItem) PriorityName() string {
+	if w.Priority < 0 || w.Priority >= len(WishlistPriorities) {
+		return ""
+	}
+	return WishlistPriorities[w.Priority]
+}
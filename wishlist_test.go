@@ -0,0 +1,52 @@
+package dusk
+
+import (
+	"testing"
+
+	"github.com/kencx/dusk/null"
+)
+
+func TestValidateWishlistItem(t *testing.T) {
+	tests := []struct {
+		name string
+		w    WishlistItem
+		keys []string
+	}{{
+		name: "success",
+		w:    WishlistItem{Price: null.FloatFrom(9.99), Priority: 3},
+	}, {
+		name: "empty",
+		w:    WishlistItem{},
+	}, {
+		name: "negative price",
+		w:    WishlistItem{Price: null.FloatFrom(-1)},
+		keys: []string{"price"},
+	}, {
+		name: "invalid priority",
+		w:    WishlistItem{Priority: -1},
+		keys: []string{"priority"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errMap := tt.w.Valid()
+			if len(errMap) != len(tt.keys) {
+				t.Fatalf("got errs %v, want errs for %v", errMap, tt.keys)
+			}
+			for _, k := range tt.keys {
+				if _, ok := errMap[k]; !ok {
+					t.Errorf("err field missing %q", k)
+				}
+			}
+		})
+	}
+}
+
+func TestWishlistPriorityName(t *testing.T) {
+	if got := (WishlistItem{Priority: 3}).PriorityName(); got != "high" {
+		t.Errorf("got %q, want high", got)
+	}
+	if got := (WishlistItem{Priority: 9}).PriorityName(); got != "" {
+		t.Errorf("got %q, want empty", got)
+	}
+}
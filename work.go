@@ -0,0 +1,52 @@
+package dusk
+
+import (
+	"fmt"
+
+	"github.com/kencx/dusk/null"
+	"github.com/kencx/dusk/validator"
+	"github.com/kennygrant/sanitize"
+)
+
+// Work groups the editions of the same book, e.g. a hardcover, its translation
+// and an ebook. Each edition is a book with its own publisher, page count,
+// ISBNs and formats, while the rating is shared by all editions of the work.
+type Work struct {
+	Id     int64  `json:"id"`
+	Title  string `json:"title" db:"title"`
+	Rating int    `json:"rating" db:"rating"`
+
+	// Open Library work key, e.g. OL45804W
+	OpenLibrary  null.String `json:"openlibrary,omitempty" db:"openlibrary"`
+	EditionCount int         `json:"edition_count" db:"editionCount"`
+
+	// editions by date published
+	Editions []Book `json:"editions,omitempty"`
+
+	// reading history of all editions in chronological order
+	Sessions []ReadingSession `json:"sessions,omitempty"`
+}
+
+func (w Work) Slugify() string {
+	return sanitize.Path(fmt.Sprintf("%s-%d", w.Title, w.Id))
+}
+
+func (w Work) Valid() validator.ErrMap {
+	err := validator.New()
+	err.Check(w.Title != "", "title", "value is missing")
+	err.Check(w.Rating >= 0, "rating", "must be >= 0")
+	err.Check(w.Rating <= 10, "rating", "must be <= 10")
+	return err
+}
+
+// Equal reports whether a and w refer to the same work. Works that are not
+// stored yet are compared by their Open Library key and title.
+func (w *Work) Equal(a *Work) bool {
+	if w == nil || a == nil {
+		return w == a
+	}
+	if w.Id != 0 || a.Id != 0 {
+		return w.Id == a.Id
+	}
+	return w.OpenLibrary.Equal(a.OpenLibrary) && w.Title == a.Title
+}
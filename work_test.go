@@ -0,0 +1,86 @@
+package dusk
+
+import (
+	"testing"
+
+	"github.com/kencx/dusk/null"
+)
+
+func TestValidateWork(t *testing.T) {
+	tests := []struct {
+		name string
+		w    Work
+		keys []string
+	}{{
+		name: "success",
+		w:    Work{Title: "Foo", Rating: 8},
+	}, {
+		name: "no title",
+		w:    Work{Rating: 8},
+		keys: []string{"title"},
+	}, {
+		name: "rating too high",
+		w:    Work{Title: "Foo", Rating: 11},
+		keys: []string{"rating"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errMap := tt.w.Valid()
+			if len(errMap) != len(tt.keys) {
+				t.Fatalf("got errs %v, want errs for %v", errMap, tt.keys)
+			}
+			for _, k := range tt.keys {
+				if _, ok := errMap[k]; !ok {
+					t.Errorf("err field missing %q", k)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *Work
+		want bool
+	}{{
+		name: "nil",
+		want: true,
+	}, {
+		name: "one nil",
+		a:    &Work{Id: 1},
+		want: false,
+	}, {
+		name: "same id",
+		a:    &Work{Id: 1, Title: "Foo"},
+		b:    &Work{Id: 1},
+		want: true,
+	}, {
+		name: "different id",
+		a:    &Work{Id: 1, OpenLibrary: null.StringFrom("OL1W")},
+		b:    &Work{Id: 2, OpenLibrary: null.StringFrom("OL1W")},
+		want: false,
+	}, {
+		name: "same key",
+		a:    &Work{Title: "Foo", OpenLibrary: null.StringFrom("OL1W")},
+		b:    &Work{Title: "Foo", OpenLibrary: null.StringFrom("OL1W")},
+		want: true,
+	}, {
+		name: "different key",
+		a:    &Work{Title: "Foo", OpenLibrary: null.StringFrom("OL1W")},
+		b:    &Work{Title: "Foo", OpenLibrary: null.StringFrom("OL2W")},
+		want: false,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Equal(tt.b); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if got := tt.b.Equal(tt.a); got != tt.want {
+				t.Errorf("got %v reversed, want %v", got, tt.want)
+			}
+		})
+	}
+}